@@ -33,10 +33,11 @@ type ProcessTaskRequest struct {
 
 // ProcessAdditionalInfoRequest represents additional info request
 type ProcessAdditionalInfoRequest struct {
-	OriginalTask   string `json:"originalTask"`
-	AdditionalInfo string `json:"additionalInfo"`
-	NodeID         int    `json:"nodeId"`
-	RequestID      string `json:"requestId"`
+	OriginalTask   string              `json:"originalTask"`
+	AdditionalInfo string              `json:"additionalInfo"`
+	History        []subnet.DialogTurn `json:"history,omitempty"` // Whole conversation for multi-turn dialogs
+	NodeID         int                 `json:"nodeId"`
+	RequestID      string              `json:"requestId"`
 }
 
 // AgentResponse represents the agent's response to a task
@@ -44,6 +45,7 @@ type AgentResponse struct {
 	OutputType  string            `json:"outputType"`
 	Output      string            `json:"output,omitempty"`
 	InfoRequest string            `json:"infoRequest,omitempty"`
+	Error       string            `json:"error,omitempty"`
 	VLCClock    map[uint64]uint64 `json:"vlcClock"`
 }
 
@@ -89,13 +91,14 @@ func handleProcessTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Process the task through the miner
-	minerResponse := globalMiner.ProcessInput(req.Task, req.NodeID, req.RequestID)
+	minerResponse := globalMiner.ProcessInputContext(r.Context(), req.Task, req.NodeID, req.RequestID)
 
 	// Convert to HTTP response format
 	response := AgentResponse{
 		OutputType:  string(minerResponse.OutputType),
 		Output:      minerResponse.Output,
 		InfoRequest: minerResponse.InfoRequest,
+		Error:       minerResponse.Error,
 		VLCClock:    minerResponse.VLCClock.Values,
 	}
 
@@ -121,10 +124,15 @@ func handleProcessAdditionalInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Process additional info through the miner
-	minerResponse := globalMiner.ProcessAdditionalInfo(
+	// Process additional info through the miner (one dialog turn when history is given)
+	history := req.History
+	if len(history) == 0 {
+		history = []subnet.DialogTurn{{Answer: req.AdditionalInfo}}
+	}
+	minerResponse := globalMiner.ProcessDialogTurnContext(
+		r.Context(),
 		req.OriginalTask,
-		req.AdditionalInfo,
+		history,
 		req.NodeID,
 		req.RequestID,
 	)
@@ -134,6 +142,7 @@ func handleProcessAdditionalInfo(w http.ResponseWriter, r *http.Request) {
 		OutputType:  string(minerResponse.OutputType),
 		Output:      minerResponse.Output,
 		InfoRequest: minerResponse.InfoRequest,
+		Error:       minerResponse.Error,
 		VLCClock:    minerResponse.VLCClock.Values,
 	}
 
@@ -60,6 +60,14 @@ func main() {
 		return
 	}
 
+	// Check if running as a single miner/validator process of a networked subnet
+	// These nodes only serve subnet messages and need no Dgraph or bridge
+	nodeRole := os.Getenv("SUBNET_NODE_ROLE")
+	if nodeRole != "" && nodeRole != "coordinator" {
+		RunSubnetNode(nodeRole)
+		return
+	}
+
 	// Check if running in validation-only mode EARLY (before heavy initialization)
 	validationOnlyMode := os.Getenv("VALIDATION_ONLY_MODE") == "true"
 
@@ -102,7 +110,13 @@ func main() {
 	if subnetID == "" {
 		subnetID = "subnet-1" // Default to the registered subnet
 	}
-	coordinator := demo.NewDemoCoordinator(subnetID)
+	var coordinator *demo.DemoCoordinator
+	if nodeRole == "coordinator" {
+		// Miner and validators 2-4 run as separate processes (see subnet_node.go)
+		coordinator = newNetworkedCoordinator(subnetID)
+	} else {
+		coordinator = demo.NewDemoCoordinator(subnetID)
+	}
 
 	// Set up HTTP bridge URL only if not in subnet-only mode
 	if !subnetOnlyMode && coordinator.GraphAdapter != nil {
@@ -42,6 +42,19 @@ class PerEpochMainnetBridge {
         this.PINATA_GATEWAY = process.env.GATEWAY_PINATA || "coffee-defiant-raccoon-829.mypinata.cloud";
         this.PINATA_API_URL = "https://uploads.pinata.cloud/v3/files";
 
+        // Quorum certificate checks - epochs whose certificates do not verify are rejected
+        // unless REQUIRE_QUORUM_CERTIFICATES=false; the quorum is never below 2/3 (as in Go)
+        this.REQUIRE_QUORUM_CERTIFICATES = process.env.REQUIRE_QUORUM_CERTIFICATES !== "false";
+        this.CONSENSUS_QUORUM = 2 / 3;
+        if (process.env.CONSENSUS_QUORUM) {
+            const quorum = parseFloat(process.env.CONSENSUS_QUORUM);
+            if (quorum >= 2 / 3 - 1e-9 && quorum <= 1) {
+                this.CONSENSUS_QUORUM = quorum;
+            } else {
+                console.warn(`⚠️  Ignoring CONSENSUS_QUORUM=${process.env.CONSENSUS_QUORUM} (must be in [2/3, 1])`);
+            }
+        }
+
         // Account configuration - use environment variables or defaults for local Anvil
         this.accounts = {
             deployer: {
@@ -221,6 +234,10 @@ class PerEpochMainnetBridge {
                 "function subnetIdToHash(string memory subnetId) external view returns (bytes32)"
             ];
 
+            const registryABI = [
+                "function getSubnet(string memory subnetId) external view returns (address miner, uint256 minerAgentId, address[4] memory validators, bool isActive)"
+            ];
+
             const fluxABI = [
                 "function balanceOf(address account) external view returns (uint256)",
                 "function totalSupply() external view returns (uint256)"
@@ -229,6 +246,7 @@ class PerEpochMainnetBridge {
             // Create contract instances
             this.contracts.verifier = new ethers.Contract(verifierAddress, verifierABI, this.wallets.validator1);
             this.contracts.flux = new ethers.Contract(fluxAddress, fluxABI, this.provider);
+            this.contracts.registry = new ethers.Contract(registryAddress, registryABI, this.provider);
 
             console.log(`📋 Loaded contracts:`);
             console.log(`  PoCWVerifier: ${verifierAddress}`);
@@ -628,7 +646,14 @@ class PerEpochMainnetBridge {
     // Submit epoch data to blockchain using the received data
     async submitEpochToBlockchain(epochData) {
         try {
-            const successfulMiners = [this.accounts.miner.address];
+            // One entry per successful round, so each miner is rewarded for the tasks it served;
+            // older subnets without per-round miner data credit the configured miner
+            const successfulMiners = (epochData.successfulMiners && epochData.successfulMiners.length > 0)
+                ? epochData.successfulMiners
+                : [this.accounts.miner.address];
+
+            // Check that the registered validators actually agreed on the epoch's tasks
+            await this.verifyEpochCertificate(epochData);
 
             // Use actual successful/failed counts from detailed round data
             let successfulTasks = (epochData.detailedRounds || []).filter(r => r.success).length;
@@ -694,6 +719,146 @@ class PerEpochMainnetBridge {
         }
     }
 
+    /**
+     * Verify the epoch's quorum certificate against the validator set in SubnetRegistry.
+     * Mirrors TaskCertificate.Verify in subnet/quorum_certificate.go, and every successful
+     * round must carry an accepted certificate. Failures are thrown, or only logged when
+     * REQUIRE_QUORUM_CERTIFICATES=false.
+     * @param {Object} epochData - Epoch data from Go subnet
+     */
+    async verifyEpochCertificate(epochData) {
+        const certificate = epochData.certificate;
+        const tasks = (certificate && certificate.tasks) || [];
+
+        try {
+            const accepted = new Set(tasks.filter(task => task.outcome === 'accepted').map(task => task.requestId));
+            for (const round of epochData.detailedRounds || []) {
+                if (round.success && !accepted.has(round.requestId)) {
+                    throw new Error(`successful round ${round.requestId} has no accepted quorum certificate`);
+                }
+            }
+
+            const [, , registeredValidators] = await this.contracts.registry.getSubnet(epochData.subnetId);
+            const validators = new Set(registeredValidators
+                .filter(address => address !== ethers.ZeroAddress)
+                .map(address => address.toLowerCase()));
+            if (validators.size === 0) {
+                throw new Error(`no validators registered for ${epochData.subnetId}`);
+            }
+
+            for (const task of tasks) {
+                this.verifyTaskCertificate(task, validators);
+            }
+            console.log(`📜 Epoch ${epochData.epochNumber}: ${tasks.length} quorum certificate(s) verified against ${validators.size} registered validators`);
+        } catch (error) {
+            if (this.REQUIRE_QUORUM_CERTIFICATES) {
+                throw new Error(`Quorum certificate verification failed: ${error.message}`);
+            }
+            console.warn(`⚠️  Quorum certificate verification failed: ${error.message}`);
+        }
+    }
+
+    /**
+     * Verify one task certificate: every attestation is signed by a distinct registered
+     * validator, and the weight of the attestations agreeing with the outcome reaches the
+     * quorum of the registered weight (n equal shares of the whole set, so 1).
+     * Each attestation signs keccak256("\x19PoCW Vote Attestation:\n" + preimage) with
+     * preimage "subnetId|requestId|outputCommitment|accept|quality(6 decimals)|clock(id:value sorted)".
+     * The round leader signs keccak256("\x19PoCW Quorum Weights:\n" + preimage) with preimage
+     * "subnetId|requestId|outputCommitment|outcome|clock|address:weight(6 decimals, sorted)".
+     * @param {Object} task - TaskCertificate JSON
+     * @param {Set<string>} validators - Lower-case registered validator addresses
+     */
+    verifyTaskCertificate(task, validators) {
+        const clock = Object.keys(task.vlcClock || {})
+            .map(Number)
+            .sort((a, b) => a - b)
+            .map(id => `${id}:${task.vlcClock[id]}`)
+            .join(',');
+
+        const weights = this.verifyCertificateWeights(task, validators, clock);
+
+        const seen = new Set();
+        let agreeing = 0;
+        for (const attestation of task.attestations || []) {
+            const quality = Math.min(Math.max(attestation.quality || 0, 0), 1).toFixed(6);
+            const preimage = [
+                task.subnetId,
+                task.requestId,
+                task.outputCommitment.toLowerCase(),
+                String(attestation.accept),
+                quality,
+                clock
+            ].join('|');
+            const digest = ethers.keccak256(ethers.toUtf8Bytes("\x19PoCW Vote Attestation:\n" + preimage));
+            const signer = ethers.recoverAddress(digest, attestation.signature).toLowerCase();
+
+            if (!validators.has(signer)) {
+                throw new Error(`${task.requestId}: attestation from unregistered ${signer}`);
+            }
+            if (seen.has(signer)) {
+                throw new Error(`${task.requestId}: duplicate attestation from ${signer}`);
+            }
+            seen.add(signer);
+
+            if (attestation.accept === (task.outcome === 'accepted')) {
+                agreeing += weights.get(signer) || 0;
+            }
+        }
+
+        if (agreeing < this.CONSENSUS_QUORUM - 1e-6 * validators.size - 1e-9) {
+            throw new Error(`${task.requestId}: ${agreeing.toFixed(6)} of the registered weight for ${task.outcome}`);
+        }
+    }
+
+    /**
+     * Check the round leader's signature over a task certificate's weights.
+     * Mirrors TaskCertificate.verifyWeights: exactly the registered validators, each at
+     * most an equal share of the set.
+     * @param {Object} task - TaskCertificate JSON
+     * @param {Set<string>} validators - Lower-case registered validator addresses
+     * @param {string} clock - Canonical "id:value" clock of the task
+     * @returns {Map<string, number>} Weight by lower-case validator address
+     */
+    verifyCertificateWeights(task, validators, clock) {
+        const share = 1 / validators.size + 1e-6;
+        const weights = new Map();
+        for (const [key, weight] of Object.entries(task.weights || {})) {
+            const address = key.toLowerCase();
+            if (!validators.has(address)) {
+                throw new Error(`${task.requestId}: weight for unregistered ${key}`);
+            }
+            if (weights.has(address)) {
+                throw new Error(`${task.requestId}: duplicate weight for ${key}`);
+            }
+            if (!(weight >= 0 && weight <= share)) {
+                throw new Error(`${task.requestId}: weight ${weight} for ${key} outside [0, ${share.toFixed(6)}]`);
+            }
+            weights.set(address, weight);
+        }
+        for (const address of validators) {
+            if (!weights.has(address)) {
+                throw new Error(`${task.requestId}: no weight for registered ${address}`);
+            }
+        }
+
+        const pairs = [...weights.keys()].sort().map(address => `${address}:${weights.get(address).toFixed(6)}`);
+        const preimage = [
+            task.subnetId,
+            task.requestId,
+            task.outputCommitment.toLowerCase(),
+            task.outcome,
+            clock,
+            pairs.join(',')
+        ].join('|');
+        const digest = ethers.keccak256(ethers.toUtf8Bytes("\x19PoCW Quorum Weights:\n" + preimage));
+        const leader = ethers.recoverAddress(digest, task.leaderSignature || '0x').toLowerCase();
+        if (!validators.has(leader) || leader !== (task.leader || '').toLowerCase()) {
+            throw new Error(`${task.requestId}: weights signed by ${leader}, not a registered leader`);
+        }
+        return weights;
+    }
+
     /**
      * Create VLC graph object from epoch data (used for both IPFS and on-chain storage)
      * @param {Object} epochData - Epoch data from Go subnet
@@ -707,6 +872,7 @@ class PerEpochMainnetBridge {
             detailedRounds: epochData.detailedRounds || [],
             epochEventId: epochData.epochEventId || '',
             parentRoundEventId: epochData.parentRoundEventId || '',
+            certificate: epochData.certificate || null,
             timestamp: epochData.timestamp || Math.floor(Date.now() / 1000)
         };
     }
@@ -0,0 +1,68 @@
+#!/bin/bash
+
+# PoCW Distributed Subnet Script
+# Runs the miner and each validator as a separate OS process on localhost.
+# Participants exchange signed subnet messages over the HTTP transport.
+# No blockchain integration - subnet consensus only (SUBNET_ONLY_MODE)
+
+echo "🔹 PoCW DISTRIBUTED SUBNET (LOCALHOST)"
+echo "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+echo "Architecture: 1 miner + 4 validators as 5 processes"
+echo ""
+
+if ! command -v go &> /dev/null; then
+    echo "❌ Go not found. Please install Go >= 1.21."
+    exit 1
+fi
+
+# Build once so every process runs the same binary
+BIN=./pocw-subnet-node
+echo "🔨 Building subnet binary..."
+go build -o $BIN main.go agent_http_server.go subnet_node.go || exit 1
+
+export SUBNET_ONLY_MODE=true
+export SUBNET_PEERS="miner-1=http://localhost:7001,validator-2=http://localhost:7002,validator-3=http://localhost:7003,validator-4=http://localhost:7004"
+
+PIDS=()
+
+cleanup() {
+    echo ""
+    echo "🛑 Stopping subnet nodes..."
+    for pid in "${PIDS[@]}"; do
+        kill $pid 2>/dev/null || true
+    done
+    rm -f $BIN
+    echo "✅ Cleanup complete"
+    exit 0
+}
+trap cleanup SIGINT SIGTERM
+
+# Start miner and validators 2-4
+echo "⛏️  Starting miner on :7001..."
+SUBNET_NODE_ROLE=miner NODE_LISTEN=:7001 $BIN > miner-1.log 2>&1 &
+PIDS+=($!)
+
+for n in 2 3 4; do
+    echo "🛡️  Starting validator-$n on :700$n..."
+    SUBNET_NODE_ROLE=validator NODE_ID=$n NODE_LISTEN=:700$n $BIN > validator-$n.log 2>&1 &
+    PIDS+=($!)
+done
+
+# Wait for all nodes to be reachable
+for port in 7001 7002 7003 7004; do
+    for i in $(seq 1 30); do
+        if curl -s http://localhost:$port/subnet/health > /dev/null; then
+            break
+        fi
+        sleep 0.5
+    done
+done
+echo "✅ Subnet nodes ready (logs: miner-1.log, validator-N.log)"
+echo ""
+
+# Validator-1 coordinates the rounds from this terminal
+echo "🚀 Starting coordinator (validator-1): 7 rounds → 2 epochs + 1 partial"
+echo ""
+SUBNET_NODE_ROLE=coordinator $BIN
+
+cleanup
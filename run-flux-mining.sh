@@ -1810,7 +1810,7 @@ if [ "$USE_TEE_VALIDATION" = "true" ]; then
     lsof -ti:${AGENT_HTTP_PORT} | xargs kill -9 2>/dev/null || true
     sleep 1
 
-    AGENT_SERVER_MODE=true AGENT_HTTP_PORT=$AGENT_HTTP_PORT go run main.go agent_http_server.go &
+    AGENT_SERVER_MODE=true AGENT_HTTP_PORT=$AGENT_HTTP_PORT go run main.go agent_http_server.go subnet_node.go &
     AGENT_SERVER_PID=$!
 
     # Wait for agent server to be ready
@@ -1955,7 +1955,7 @@ else
     echo "   Testing VLC protocol implementation..."
     echo ""
 
-    VALIDATION_ONLY_MODE=true timeout 60 go run main.go agent_http_server.go
+    VALIDATION_ONLY_MODE=true timeout 60 go run main.go agent_http_server.go subnet_node.go
     VALIDATION_EXIT_CODE=$?
 
     echo ""
@@ -2445,13 +2445,14 @@ echo ""
 export AGENT_ID_DEC
 export RPC_URL
 export REPUTATION_REGISTRY_ADDRESS
+export SUBNET_REGISTRY_ADDRESS=$REGISTRY_ADDRESS
 export IDENTITY_REGISTRY_ADDRESS
 export CHAIN_ID
 export MINER_KEY
 export CLIENT_KEY=$PRIVATE_KEY_CLIENT
 export CLIENT_ADDRESS
 export PAYMENT_MODE
-go run main.go agent_http_server.go
+go run main.go agent_http_server.go subnet_node.go
 
 echo ""
 echo "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
@@ -154,7 +154,7 @@ echo "🚀 Starting demo: 7 rounds → 2 epochs + 1 partial"
 echo ""
 
 # Run the Go subnet system (include agent_http_server.go for TEE validation support)
-go run main.go agent_http_server.go
+go run main.go agent_http_server.go subnet_node.go
 
 # This line will be reached if main.go exits normally
 echo ""
@@ -0,0 +1,399 @@
+// Package subnet - Independent Miner Clock Verification and Fork Detection
+//
+// This file gives every validator its own causal view of each miner, separate from the
+// leader's orchestration clock. A validator checks every miner response clock itself
+// against the miner's sequence rule and the previous response it accepted, then gossips a
+// ClockCheckpointMessage (the accepted, miner-signed response) to its peers.
+//
+// Views:
+//   - One per miner clock entry: the last accepted response clock and the recently
+//     accepted responses by entry value
+//   - A checkpoint for the next entry a validator has not seen (e.g., a clarification turn
+//     only the leader received) is checked by the receiving validator like its own
+//     observation; checkpoints further ahead wait until the gap is filled
+//
+// Forks:
+//   - Two different miner-signed responses (different output commitments) for the same
+//     miner entry mean the miner showed different histories to different validators
+//   - The pair is recorded as ForkEvidence, which anyone can check with VerifyForkEvidence
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// maxObservedResponses bounds the accepted responses (and pending peer checkpoints) a
+// validator keeps per miner for fork comparison
+const maxObservedResponses = 256
+
+var (
+	// ErrMinerFork is returned when a miner signed two different responses for one clock entry
+	ErrMinerFork = errors.New("miner showed conflicting histories")
+	// ErrForkEvidence is returned when fork evidence does not prove a fork
+	ErrForkEvidence = errors.New("invalid fork evidence")
+)
+
+// ForkEvidence proves that a miner signed two different responses at the same clock entry
+type ForkEvidence struct {
+	MinerID      string                `json:"miner_id"`
+	ClockID      uint64                `json:"clock_id"`
+	Entry        uint64                `json:"entry"`
+	First        *MinerResponseMessage `json:"first"`
+	FirstSeenBy  string                `json:"first_seen_by"`
+	Second       *MinerResponseMessage `json:"second"`
+	SecondSeenBy string                `json:"second_seen_by"`
+	DetectedBy   string                `json:"detected_by"`
+	DetectedAt   time.Time             `json:"detected_at"`
+}
+
+// ForkListener is notified of every miner fork a validator detects
+type ForkListener func(evidence *ForkEvidence)
+
+// observedResponse is a miner response a validator accepted into its view
+type observedResponse struct {
+	response *MinerResponseMessage
+	seenBy   string // Validator that received it from the miner
+}
+
+// minerView is a validator's own causal view of one miner
+type minerView struct {
+	minerID  string
+	clock    *vlc.Clock                         // Clock of the last accepted response (nil before the first)
+	observed map[uint64]*observedResponse       // Miner entry -> accepted response
+	order    []uint64                           // Observed entries, oldest first (eviction order)
+	pending  map[uint64]*ClockCheckpointMessage // Peer checkpoints ahead of the view
+}
+
+// SetForkListener registers a callback for detected miner forks
+func (v *CoreValidator) SetForkListener(listener ForkListener) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.forkListener = listener
+}
+
+// DetectedForks returns the fork evidence this validator has collected, oldest first
+func (v *CoreValidator) DetectedForks() []ForkEvidence {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return append([]ForkEvidence(nil), v.forks...)
+}
+
+// ObserveMinerResponse checks a miner response's clock against this validator's own view
+// of the miner: its entry must advance as the miner's sequence rule declares and no entry
+// may fall below the previously accepted response. Observing an accepted response again is
+// a no-op. Returns the signed checkpoint to gossip to peer validators, or nil for an
+// Unprocessed response, which took no VLC step and is not observed.
+func (v *CoreValidator) ObserveMinerResponse(response *MinerResponseMessage) (*ClockCheckpointMessage, error) {
+	if response.VLCClock == nil {
+		return nil, fmt.Errorf("%w: %s carries no clock", ErrVLCSequence, response.RequestID)
+	}
+	if response.Unprocessed {
+		return nil, nil
+	}
+	clockID := v.MinerClockIDOf(response.Sender)
+	if err := VerifyRequestCausality(response, clockID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVLCSequence, err)
+	}
+
+	v.mu.Lock()
+	forks, err := v.observeLocked(response, clockID, v.ID)
+	listener := v.forkListener
+	v.mu.Unlock()
+
+	v.notifyForks(listener, forks)
+	if err != nil {
+		return nil, err
+	}
+	return v.newClockCheckpoint(response, clockID), nil
+}
+
+// ReceiveClockCheckpoint processes a peer validator's checkpoint. The embedded response must
+// be authentic; if this validator accepted a different response at the same entry the
+// miner forked. A checkpoint for the view's next entry is observed as if received from the
+// miner, one further ahead is held until the gap is filled.
+func (v *CoreValidator) ReceiveClockCheckpoint(msg *ClockCheckpointMessage) error {
+	if err := verifyIfConfigured(v.signerRegistry, msg); err != nil {
+		return err
+	}
+	if msg.Response == nil || msg.Response.VLCClock == nil {
+		return fmt.Errorf("%w: checkpoint from %s carries no response clock", ErrUnexpectedMessage, msg.Sender)
+	}
+	if err := v.VerifyMinerResponse(msg.Response); err != nil {
+		return err
+	}
+	clockID := v.MinerClockIDOf(msg.Response.Sender)
+	if entry := msg.Response.VLCClock.Values[clockID]; msg.ClockID != clockID || msg.Entry != entry {
+		return fmt.Errorf("%w: checkpoint from %s names entry %d=%d, its response has %d=%d",
+			ErrUnexpectedMessage, msg.Sender, msg.ClockID, msg.Entry, clockID, entry)
+	}
+	if err := VerifyRequestCausality(msg.Response, clockID); err != nil {
+		return fmt.Errorf("%w: %v", ErrVLCSequence, err)
+	}
+
+	v.mu.Lock()
+	forks, err := v.receiveCheckpointLocked(msg, clockID)
+	listener := v.forkListener
+	v.mu.Unlock()
+
+	v.notifyForks(listener, forks)
+	return err
+}
+
+// receiveCheckpointLocked compares or applies a verified peer checkpoint (caller holds v.mu)
+func (v *CoreValidator) receiveCheckpointLocked(msg *ClockCheckpointMessage, clockID uint64) ([]*ForkEvidence, error) {
+	view := v.minerViewLocked(clockID, msg.Response.Sender)
+	if view.minerID != msg.Response.Sender {
+		return v.observeLocked(msg.Response, clockID, msg.Sender) // Refused as an unknown sender
+	}
+	if prior, ok := view.observed[msg.Entry]; ok {
+		if fork := v.compareLocked(view, clockID, msg.Entry, prior, msg.Response, msg.Sender); fork != nil {
+			return []*ForkEvidence{fork}, nil
+		}
+		return nil, nil
+	}
+
+	participant, rule, violation := v.ruleForLocked(MinerResponseType, clockID)
+	if violation != nil {
+		return nil, v.viewViolationLocked(view, violation, msg.Response)
+	}
+	next := v.viewEntryLocked(view, participant) + rule.Increment
+	switch {
+	case msg.Entry == next:
+		return v.observeLocked(msg.Response, clockID, msg.Sender)
+	case msg.Entry > next:
+		if existing, ok := view.pending[msg.Entry]; ok {
+			if fork := v.compareLocked(view, clockID, msg.Entry, &observedResponse{response: existing.Response, seenBy: existing.Sender}, msg.Response, msg.Sender); fork != nil {
+				return []*ForkEvidence{fork}, nil
+			}
+			return nil, nil
+		}
+		if len(view.pending) < maxObservedResponses {
+			view.pending[msg.Entry] = msg
+		}
+		return nil, nil
+	}
+	return nil, nil // Older than the retained history
+}
+
+// observeLocked checks a response against the view and accepts it, then applies any peer
+// checkpoints it unblocked (caller holds v.mu)
+func (v *CoreValidator) observeLocked(response *MinerResponseMessage, clockID uint64, seenBy string) ([]*ForkEvidence, error) {
+	view := v.minerViewLocked(clockID, response.Sender)
+	entry, present := response.VLCClock.Values[clockID]
+	if view.minerID != response.Sender {
+		return nil, v.viewViolationLocked(view, &SequenceViolation{
+			Kind: ViolationUnknownSender, SenderID: clockID, Participant: response.Sender,
+			MessageType: MinerResponseType, RequestID: response.RequestID,
+			Detail: fmt.Sprintf("entry %d belongs to %s", clockID, view.minerID),
+		}, response)
+	}
+
+	if prior, ok := view.observed[entry]; present && ok {
+		if strings.EqualFold(prior.response.OutputCommitment, response.OutputCommitment) {
+			return nil, nil // Already accepted
+		}
+		var forks []*ForkEvidence
+		if fork := v.compareLocked(view, clockID, entry, prior, response, seenBy); fork != nil {
+			forks = append(forks, fork)
+		}
+		return forks, fmt.Errorf("%w: %s at entry %d=%d", ErrMinerFork, view.minerID, clockID, entry)
+	}
+
+	participant, rule, violation := v.ruleForLocked(MinerResponseType, clockID)
+	if violation == nil && !present {
+		violation = &SequenceViolation{Kind: ViolationMissingClock, SenderID: clockID, Participant: participant.Name}
+	}
+	if violation == nil {
+		if expected := v.viewEntryLocked(view, participant) + rule.Increment; entry != expected {
+			violation = &SequenceViolation{
+				Kind: ViolationWrongIncrement, SenderID: clockID, Participant: participant.Name,
+				Expected: expected, Got: entry,
+			}
+		}
+	}
+	if violation == nil && view.clock != nil {
+		var regressed []uint64
+		for id, value := range view.clock.Values {
+			if response.VLCClock.Values[id] < value {
+				regressed = append(regressed, id)
+			}
+		}
+		if len(regressed) > 0 {
+			sort.Slice(regressed, func(i, j int) bool { return regressed[i] < regressed[j] })
+			violation = &SequenceViolation{
+				Kind: ViolationClockRegressed, SenderID: clockID, Participant: participant.Name,
+				Regressed: regressed,
+			}
+		}
+	}
+	if violation != nil {
+		violation.MessageType = MinerResponseType
+		violation.RequestID = response.RequestID
+		if seenBy != v.ID {
+			violation.Detail = "checkpoint from " + seenBy
+		}
+		return nil, v.viewViolationLocked(view, violation, response)
+	}
+
+	var forks []*ForkEvidence
+	if remote, ok := view.pending[entry]; ok {
+		delete(view.pending, entry)
+		if fork := v.compareLocked(view, clockID, entry, &observedResponse{response: remote.Response, seenBy: remote.Sender}, response, seenBy); fork != nil {
+			forks = append(forks, fork)
+		}
+	}
+
+	view.clock = response.VLCClock.Copy()
+	view.observed[entry] = &observedResponse{response: response, seenBy: seenBy}
+	view.order = append(view.order, entry)
+	if len(view.order) > maxObservedResponses {
+		delete(view.observed, view.order[0])
+		view.order = view.order[1:]
+	}
+	fmt.Printf("Validator %s: Own view of %s accepted %s at entry %d=%d\n", v.ID, participant.Name, response.RequestID, clockID, entry)
+
+	if next, ok := view.pending[entry+rule.Increment]; ok {
+		delete(view.pending, next.Entry)
+		more, err := v.observeLocked(next.Response, clockID, next.Sender)
+		if err != nil {
+			fmt.Printf("⚠️  Validator %s: Held checkpoint from %s rejected - %v\n", v.ID, next.Sender, err)
+		}
+		forks = append(forks, more...)
+	}
+	return forks, nil
+}
+
+// compareLocked returns fork evidence if two responses for one miner entry differ (caller holds v.mu)
+func (v *CoreValidator) compareLocked(view *minerView, clockID, entry uint64, prior *observedResponse, response *MinerResponseMessage, seenBy string) *ForkEvidence {
+	if strings.EqualFold(prior.response.OutputCommitment, response.OutputCommitment) {
+		return nil
+	}
+	for _, known := range v.forks {
+		if known.ClockID == clockID && known.Entry == entry && sameCommitments(&known, prior.response, response) {
+			return nil // Already recorded (e.g., seen directly after a peer's checkpoint)
+		}
+	}
+	evidence := &ForkEvidence{
+		MinerID:      view.minerID,
+		ClockID:      clockID,
+		Entry:        entry,
+		First:        prior.response,
+		FirstSeenBy:  prior.seenBy,
+		Second:       response,
+		SecondSeenBy: seenBy,
+		DetectedBy:   v.ID,
+		DetectedAt:   time.Now(),
+	}
+	v.forks = append(v.forks, *evidence)
+	fmt.Printf("🍴 Validator %s: %s forked at entry %d=%d - %s (%s, seen by %s) vs %s (%s, seen by %s)\n",
+		v.ID, view.minerID, clockID, entry,
+		prior.response.RequestID, prior.response.OutputCommitment, prior.seenBy,
+		response.RequestID, response.OutputCommitment, seenBy)
+	return evidence
+}
+
+// sameCommitments reports whether evidence is about the same pair of responses, in either order
+func sameCommitments(evidence *ForkEvidence, a, b *MinerResponseMessage) bool {
+	first, second := evidence.First.OutputCommitment, evidence.Second.OutputCommitment
+	return (strings.EqualFold(first, a.OutputCommitment) && strings.EqualFold(second, b.OutputCommitment)) ||
+		(strings.EqualFold(first, b.OutputCommitment) && strings.EqualFold(second, a.OutputCommitment))
+}
+
+// minerViewLocked returns the view of a miner entry, creating it (caller holds v.mu)
+func (v *CoreValidator) minerViewLocked(clockID uint64, minerID string) *minerView {
+	if v.minerViews == nil {
+		v.minerViews = make(map[uint64]*minerView)
+	}
+	view, ok := v.minerViews[clockID]
+	if !ok {
+		view = &minerView{
+			minerID:  minerID,
+			observed: make(map[uint64]*observedResponse),
+			pending:  make(map[uint64]*ClockCheckpointMessage),
+		}
+		v.minerViews[clockID] = view
+	}
+	return view
+}
+
+// viewEntryLocked returns the miner entry of the view's last accepted response, or the
+// participant's baseline before the first (caller holds v.mu)
+func (v *CoreValidator) viewEntryLocked(view *minerView, participant SequenceParticipant) uint64 {
+	if view.clock == nil {
+		return participant.Baseline
+	}
+	return view.clock.Values[participant.ClockID]
+}
+
+// viewViolationLocked records a violation against a miner view (caller holds v.mu)
+func (v *CoreValidator) viewViolationLocked(view *minerView, violation *SequenceViolation, response *MinerResponseMessage) *SequenceViolation {
+	v.stampViolationLocked(violation, response.VLCClock)
+	violation.Local = map[uint64]uint64{}
+	if view.clock != nil {
+		violation.Local = view.clock.Copy().Values
+	}
+	v.recordViolationLocked(violation)
+	return violation
+}
+
+// newClockCheckpoint builds the signed checkpoint for an accepted miner response
+func (v *CoreValidator) newClockCheckpoint(response *MinerResponseMessage, clockID uint64) *ClockCheckpointMessage {
+	msg := &ClockCheckpointMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: response.RequestID,
+			Type:      ClockCheckpointType,
+			Sender:    v.ID,
+			Timestamp: time.Now().Unix(),
+		},
+		MinerID:  response.Sender,
+		ClockID:  clockID,
+		Entry:    response.VLCClock.Values[clockID],
+		Response: response,
+	}
+	v.sign(msg)
+	return msg
+}
+
+// notifyForks reports detected forks to the listener (called without v.mu held)
+func (v *CoreValidator) notifyForks(listener ForkListener, forks []*ForkEvidence) {
+	if listener == nil {
+		return
+	}
+	for _, fork := range forks {
+		listener(fork)
+	}
+}
+
+// VerifyForkEvidence checks that evidence proves a fork: both responses are authentic
+// (output commitments and, if registry is non-nil, miner signatures), come from the same
+// miner, carry the same entry and commit to different outputs or clocks
+func VerifyForkEvidence(evidence *ForkEvidence, registry *SignerRegistry) error {
+	if evidence.First == nil || evidence.Second == nil {
+		return fmt.Errorf("%w: missing response", ErrForkEvidence)
+	}
+	for _, response := range []*MinerResponseMessage{evidence.First, evidence.Second} {
+		if response.Sender != evidence.MinerID {
+			return fmt.Errorf("%w: response %s is from %s, not %s", ErrForkEvidence, response.RequestID, response.Sender, evidence.MinerID)
+		}
+		if response.VLCClock == nil || response.VLCClock.Values[evidence.ClockID] != evidence.Entry {
+			return fmt.Errorf("%w: response %s is not at entry %d=%d", ErrForkEvidence, response.RequestID, evidence.ClockID, evidence.Entry)
+		}
+		if err := VerifyOutputCommitment(response); err != nil {
+			return fmt.Errorf("%w: %v", ErrForkEvidence, err)
+		}
+		if err := verifyIfConfigured(registry, response); err != nil {
+			return fmt.Errorf("%w: %v", ErrForkEvidence, err)
+		}
+	}
+	if strings.EqualFold(evidence.First.OutputCommitment, evidence.Second.OutputCommitment) {
+		return fmt.Errorf("%w: both responses commit to %s", ErrForkEvidence, evidence.First.OutputCommitment)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package subnet
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// checkpointFixture is a miner key shared by two diverging miner instances (a forking
+// miner) and two validators that each see one of them
+type checkpointFixture struct {
+	registry   *SignerRegistry
+	validators []*CoreValidator
+	forked     []*CoreMiner // Same identity and key, different histories
+}
+
+func newCheckpointFixture(t *testing.T) *checkpointFixture {
+	t.Helper()
+	fixture := &checkpointFixture{registry: NewSignerRegistry()}
+
+	minerSigner, err := GenerateMessageSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture.registry.Register("miner-1", minerSigner.Address())
+	for i := 0; i < 2; i++ {
+		miner := NewCoreMiner("miner-1", "subnet-test")
+		miner.SetMessageSigner(minerSigner)
+		miner.SetContextTaskProcessor(sleepyProcessor{}) // Output echoes the task
+		fixture.forked = append(fixture.forked, miner)
+	}
+
+	validatorIDs := []string{"validator-1", "validator-2"}
+	for _, validatorID := range validatorIDs {
+		signer, err := GenerateMessageSigner()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixture.registry.Register(validatorID, signer.Address())
+
+		validator := NewCoreValidator(validatorID, "subnet-test", ConsensusValidator, 0.5)
+		validator.SetMessageSigner(signer)
+		validator.SetSignerRegistry(fixture.registry)
+		fixture.validators = append(fixture.validators, validator)
+	}
+	for _, validator := range fixture.validators {
+		validator.RegisterMiner("miner-1", MinerClockIDFor(0))
+		for i, validatorID := range validatorIDs {
+			validator.RegisterParticipant(SequenceParticipant{ClockID: ValidatorClockID(i), Kind: ParticipantValidator, Name: validatorID})
+		}
+	}
+	return fixture
+}
+
+// observe has validator i accept a response from miner instance i and returns its checkpoint
+func (f *checkpointFixture) observe(t *testing.T, i int, task string) *ClockCheckpointMessage {
+	t.Helper()
+	response := f.forked[i].ProcessInput(task, 1, "req-1")
+	checkpoint, err := f.validators[i].ObserveMinerResponse(response)
+	if err != nil {
+		t.Fatalf("validator %d rejected its own observation: %v", i, err)
+	}
+	return checkpoint
+}
+
+func TestClockCheckpointsDetectConflictingHistories(t *testing.T) {
+	f := newCheckpointFixture(t)
+	var notified []*ForkEvidence
+	f.validators[0].SetForkListener(func(evidence *ForkEvidence) { notified = append(notified, evidence) })
+
+	first := f.observe(t, 0, "task shown to validator-1")
+	second := f.observe(t, 1, "task shown to validator-2")
+
+	if err := f.validators[0].ReceiveClockCheckpoint(second); err != nil {
+		t.Fatalf("ReceiveClockCheckpoint: %v", err)
+	}
+	forks := f.validators[0].DetectedForks()
+	if len(forks) != 1 || len(notified) != 1 {
+		t.Fatalf("detected %d forks, notified %d; want 1 each", len(forks), len(notified))
+	}
+	evidence := forks[0]
+	if evidence.MinerID != "miner-1" || evidence.Entry != 2 || evidence.FirstSeenBy != "validator-1" ||
+		evidence.SecondSeenBy != "validator-2" || evidence.DetectedBy != "validator-1" {
+		t.Fatalf("evidence = %+v", evidence)
+	}
+	if err := VerifyForkEvidence(&evidence, f.registry); err != nil {
+		t.Fatalf("evidence does not verify: %v", err)
+	}
+
+	// The same pair again, from either side, is not recorded twice
+	if err := f.validators[0].ReceiveClockCheckpoint(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.validators[1].ReceiveClockCheckpoint(first); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(f.validators[0].DetectedForks()); got != 1 {
+		t.Fatalf("validator-1 recorded %d forks, want 1", got)
+	}
+	if got := len(f.validators[1].DetectedForks()); got != 1 {
+		t.Fatalf("validator-2 recorded %d forks, want 1", got)
+	}
+}
+
+func TestClockCheckpointsAgreeOnOneHistory(t *testing.T) {
+	f := newCheckpointFixture(t)
+	response := f.forked[0].ProcessInput("task", 1, "req-1")
+	checkpoint, err := f.validators[0].ObserveMinerResponse(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.validators[1].ReceiveClockCheckpoint(checkpoint); err != nil {
+		t.Fatalf("ReceiveClockCheckpoint: %v", err)
+	}
+	if _, err := f.validators[1].ObserveMinerResponse(response); err != nil {
+		t.Fatalf("validator-2 rejected the response it learned by checkpoint: %v", err)
+	}
+	if forks := f.validators[1].DetectedForks(); len(forks) != 0 {
+		t.Fatalf("honest miner reported as forked: %+v", forks)
+	}
+}
+
+func TestClockCheckpointsRejectForgedCheckpoint(t *testing.T) {
+	f := newCheckpointFixture(t)
+	checkpoint := f.observe(t, 1, "task")
+
+	forged := *checkpoint
+	response := *checkpoint.Response
+	response.Output = "altered after signing"
+	forged.Response = &response
+	if err := f.validators[0].ReceiveClockCheckpoint(&forged); err == nil {
+		t.Fatal("checkpoint carrying an altered response accepted")
+	}
+	if forks := f.validators[0].DetectedForks(); len(forks) != 0 {
+		t.Fatalf("forged checkpoint produced fork evidence: %+v", forks)
+	}
+}
+
+func TestVerifyForkEvidenceRejectsNonForks(t *testing.T) {
+	f := newCheckpointFixture(t)
+	f.observe(t, 0, "task shown to validator-1")
+	if err := f.validators[0].ReceiveClockCheckpoint(f.observe(t, 1, "task shown to validator-2")); err != nil {
+		t.Fatal(err)
+	}
+	evidence := f.validators[0].DetectedForks()[0]
+
+	same := evidence
+	same.Second = evidence.First
+	if err := VerifyForkEvidence(&same, f.registry); !errors.Is(err, ErrForkEvidence) {
+		t.Fatalf("identical responses = %v, want %v", err, ErrForkEvidence)
+	}
+
+	otherMiner := evidence
+	otherMiner.MinerID = "miner-2"
+	if err := VerifyForkEvidence(&otherMiner, f.registry); !errors.Is(err, ErrForkEvidence) {
+		t.Fatalf("evidence naming another miner = %v, want %v", err, ErrForkEvidence)
+	}
+
+	// A response the miner never signed proves nothing
+	impostor, err := GenerateMessageSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resigned := *evidence.Second
+	if err := impostor.Sign(&resigned); err != nil {
+		t.Fatal(err)
+	}
+	forged := evidence
+	forged.Second = &resigned
+	if err := VerifyForkEvidence(&forged, f.registry); !errors.Is(err, ErrForkEvidence) {
+		t.Fatalf("evidence with an impostor's signature = %v, want %v", err, ErrForkEvidence)
+	}
+}
+
+// TestClockCheckpointsConcurrentGossip delivers conflicting checkpoints from many goroutines;
+// run with -race. The fork is recorded and reported exactly once.
+func TestClockCheckpointsConcurrentGossip(t *testing.T) {
+	f := newCheckpointFixture(t)
+	var mu sync.Mutex
+	notified := 0
+	f.validators[0].SetForkListener(func(*ForkEvidence) {
+		mu.Lock()
+		notified++
+		mu.Unlock()
+	})
+
+	f.observe(t, 0, "task shown to validator-1")
+	conflicting := f.observe(t, 1, "task shown to validator-2")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f.validators[0].ReceiveClockCheckpoint(conflicting); err != nil {
+				t.Error(err)
+			}
+			f.validators[0].DetectedForks()
+		}()
+	}
+	wg.Wait()
+
+	if got := len(f.validators[0].DetectedForks()); got != 1 || notified != 1 {
+		t.Fatalf("recorded %d forks, notified %d; want 1 each", got, notified)
+	}
+}
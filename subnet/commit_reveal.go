@@ -0,0 +1,283 @@
+// Package subnet - Commit-Reveal Voting
+//
+// This file implements optional two-phase voting so validators cannot copy each other.
+//
+// Protocol:
+//  1. Commit: each validator assesses the miner response and returns only
+//     VoteCommitment(requestID, validatorID, quality, accept, abstain, salt)
+//  2. Reveal: once a quorum of commitments is in, validators reveal the full vote and salt
+//  3. The coordinator recomputes the hash; votes that do not match their commitment,
+//     and reveals that never arrive, are discarded and penalized
+//
+// The round leader is the validator that opened the commit phase for a request. A validator
+// opens its vote only for that leader, and only when the reveal request carries a quorum of
+// signed commitments (its own among them), so no one can see a vote while others may still
+// commit. The sealed vote is kept after the reveal, so a repeated reveal returns the same
+// signed vote.
+package subnet
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrCommitmentMismatch is returned when a revealed vote does not hash to its commitment
+	ErrCommitmentMismatch = errors.New("revealed vote does not match commitment")
+	// ErrNoSealedVote is returned when a validator is asked to reveal a vote it never committed
+	ErrNoSealedVote = errors.New("no committed vote for request")
+	// ErrNotRoundLeader is returned when a validator other than the round leader asks to commit or reveal
+	ErrNotRoundLeader = errors.New("not the round leader")
+	// ErrRevealTooEarly is returned when a reveal is requested before a quorum of commitments is in
+	ErrRevealTooEarly = errors.New("commit phase has not reached quorum")
+)
+
+// maxSealedVotes bounds the sealed votes a validator keeps; rounds are finalized long
+// before their vote ages out
+const maxSealedVotes = 256
+
+// sealedVote is a committed vote, kept until it ages out so reveals can be repeated
+type sealedVote struct {
+	vote       *ValidatorVoteMessage // Signed for reveal once revealed is set
+	commitment string                // Hash sent in the validator's VoteCommitMessage
+	leader     string                // Validator that opened the commit phase; only it may reveal
+	revealed   bool
+}
+
+// VoteCommitment returns the 0x-prefixed Keccak-256 commitment to a vote.
+// The request and validator IDs are bound into the hash so a commitment cannot be
+// replayed for another round or claimed by another validator.
+func VoteCommitment(requestID, validatorID string, quality float64, accept, abstain bool, salt string) string {
+	preimage := strings.Join([]string{
+		requestID,
+		validatorID,
+		strconv.FormatFloat(quality, 'g', -1, 64),
+		strconv.FormatBool(accept),
+		strconv.FormatBool(abstain),
+		salt,
+	}, "|")
+	return fmt.Sprintf("0x%x", crypto.Keccak256([]byte(preimage)))
+}
+
+// VerifyVoteReveal checks that a revealed vote matches the validator's earlier commitment
+func VerifyVoteReveal(commit *VoteCommitMessage, vote *ValidatorVoteMessage) error {
+	if commit.ValidatorID != vote.ValidatorID || commit.RequestID != vote.RequestID {
+		return fmt.Errorf("%w: commitment is for %s/%s, reveal is for %s/%s", ErrCommitmentMismatch,
+			commit.ValidatorID, commit.RequestID, vote.ValidatorID, vote.RequestID)
+	}
+	if vote.Salt == "" {
+		return fmt.Errorf("%w: reveal carries no salt", ErrCommitmentMismatch)
+	}
+
+	expected := VoteCommitment(vote.RequestID, vote.ValidatorID, vote.Quality, vote.Accept, vote.Abstain, vote.Salt)
+	if !strings.EqualFold(expected, commit.Commitment) {
+		return fmt.Errorf("%w: %s on %s", ErrCommitmentMismatch, vote.ValidatorID, vote.RequestID)
+	}
+	return nil
+}
+
+// newVoteSalt returns a random 32-byte hex salt
+func newVoteSalt() (string, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate vote salt: %v", err)
+	}
+	return fmt.Sprintf("0x%x", salt), nil
+}
+
+// CommitVote assesses a miner response and returns a signed commitment to the vote.
+// leaderID is the authenticated sender of the commit request; it becomes the round leader,
+// the only validator RevealVote opens the vote for. A repeated request from the same leader
+// returns the same commitment; one from another validator fails with ErrNotRoundLeader.
+func (v *CoreValidator) CommitVote(response *MinerResponseMessage, leaderID string) (*VoteCommitMessage, error) {
+	v.mu.RLock()
+	sealed, exists := v.sealedVotes[response.RequestID]
+	v.mu.RUnlock()
+	if exists {
+		return v.recommit(sealed, leaderID)
+	}
+
+	salt, err := newVoteSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := v.judge(response)
+
+	v.mu.Lock()
+	if sealed, exists := v.sealedVotes[response.RequestID]; exists {
+		v.mu.Unlock() // A concurrent request committed first
+		return v.recommit(sealed, leaderID)
+	}
+	vote := v.recordVerdictLocked(response, verdict)
+	vote.Salt = salt
+	sealed = &sealedVote{
+		vote:       vote,
+		commitment: VoteCommitment(vote.RequestID, vote.ValidatorID, vote.Quality, vote.Accept, vote.Abstain, salt),
+		leader:     leaderID,
+	}
+	v.sealLocked(sealed)
+	v.mu.Unlock()
+
+	return v.newVoteCommit(sealed), nil
+}
+
+// recommit answers a repeated commit request with the existing commitment
+func (v *CoreValidator) recommit(sealed *sealedVote, leaderID string) (*VoteCommitMessage, error) {
+	if leaderID != sealed.leader {
+		return nil, fmt.Errorf("%w: %s asked to commit on %s, opened by %s", ErrNotRoundLeader, leaderID, sealed.vote.RequestID, sealed.leader)
+	}
+	return v.newVoteCommit(sealed), nil
+}
+
+// sealLocked stores a sealed vote, dropping the oldest beyond maxSealedVotes (caller holds v.mu)
+func (v *CoreValidator) sealLocked(sealed *sealedVote) {
+	v.sealedVotes[sealed.vote.RequestID] = sealed
+	v.sealedOrder = append(v.sealedOrder, sealed.vote.RequestID)
+	for len(v.sealedOrder) > maxSealedVotes {
+		delete(v.sealedVotes, v.sealedOrder[0])
+		v.sealedOrder = v.sealedOrder[1:]
+	}
+}
+
+// newVoteCommit builds the signed commitment message for a sealed vote
+func (v *CoreValidator) newVoteCommit(sealed *sealedVote) *VoteCommitMessage {
+	commit := &VoteCommitMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: sealed.vote.RequestID,
+			Type:      VoteCommitType,
+			Sender:    v.ID,
+			Timestamp: time.Now().Unix(),
+		},
+		ValidatorID: v.ID,
+		Commitment:  sealed.commitment,
+	}
+
+	v.sign(commit)
+	return commit
+}
+
+// RevealVote opens the vote sealed by CommitVote, signed and including its salt.
+//
+// Returns:
+//   - ErrNoSealedVote if the validator never committed for the request
+//   - ErrNotRoundLeader if the request does not come from the leader that opened the commit phase
+//   - ErrRevealTooEarly unless the request carries a quorum of signed commitments, this
+//     validator's own among them
+//
+// A repeated reveal returns the same signed vote.
+func (v *CoreValidator) RevealVote(request *VoteRevealMessage) (*ValidatorVoteMessage, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	sealed, exists := v.sealedVotes[request.RequestID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrNoSealedVote, request.RequestID)
+	}
+	if request.Sender != sealed.leader {
+		return nil, fmt.Errorf("%w: %s asked to reveal %s, opened by %s", ErrNotRoundLeader, request.Sender, request.RequestID, sealed.leader)
+	}
+	if err := v.checkRevealQuorumLocked(request, sealed); err != nil {
+		return nil, err
+	}
+
+	if !sealed.revealed {
+		sealed.vote.Timestamp = time.Now().Unix()
+		v.attest(sealed.vote)
+		v.sign(sealed.vote)
+		sealed.revealed = true
+	}
+	revealed := *sealed.vote
+	return &revealed, nil
+}
+
+// checkRevealQuorumLocked checks that a reveal request proves the commit phase reached quorum:
+// distinct validators' signed commitments for the request, this validator's included,
+// numbering at least the quorum of registered validators (caller holds v.mu)
+func (v *CoreValidator) checkRevealQuorumLocked(request *VoteRevealMessage, sealed *sealedVote) error {
+	if request.Commitments != len(request.Sealed) {
+		return fmt.Errorf("%w: %s claims %d commitments but carries %d", ErrRevealTooEarly, request.RequestID, request.Commitments, len(request.Sealed))
+	}
+
+	committed := make(map[string]bool, len(request.Sealed))
+	for _, commit := range request.Sealed {
+		if commit == nil || commit.RequestID != request.RequestID || commit.Sender != commit.ValidatorID || committed[commit.ValidatorID] {
+			return fmt.Errorf("%w: %s carries an invalid or duplicate commitment", ErrRevealTooEarly, request.RequestID)
+		}
+		if err := verifyIfConfigured(v.signerRegistry, commit); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrRevealTooEarly, request.RequestID, err)
+		}
+		if commit.ValidatorID == v.ID && !strings.EqualFold(commit.Commitment, sealed.commitment) {
+			return fmt.Errorf("%w: %s carries another commitment for %s", ErrRevealTooEarly, request.RequestID, v.ID)
+		}
+		committed[commit.ValidatorID] = true
+	}
+	if !committed[v.ID] {
+		return fmt.Errorf("%w: %s does not carry %s's commitment", ErrRevealTooEarly, request.RequestID, v.ID)
+	}
+
+	validators := 0
+	for _, participant := range v.participants {
+		if participant.Kind == ParticipantValidator {
+			validators++
+		}
+	}
+	required := RevealQuorum(v.consensusConfig.Quorum, validators)
+	if len(committed) < required {
+		return fmt.Errorf("%w: %s has %d of %d required commitments", ErrRevealTooEarly, request.RequestID, len(committed), required)
+	}
+	return nil
+}
+
+// NewVoteCommitRequest builds the signed request asking a validator to commit to a vote
+func (v *CoreValidator) NewVoteCommitRequest(response *MinerResponseMessage, validatorID string) *VoteCommitRequestMessage {
+	msg := &VoteCommitRequestMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: response.RequestID,
+			Type:      VoteCommitRequestType,
+			Sender:    v.ID,
+			Receiver:  validatorID,
+			Timestamp: time.Now().Unix(),
+		},
+		Response: response,
+	}
+
+	v.sign(msg)
+	return msg
+}
+
+// RevealQuorum returns how many commitments a reveal request must carry in a set of
+// validators validators under quorum (outside [MinQuorum, 1]: DefaultQuorum)
+func RevealQuorum(quorum float64, validators int) int {
+	config := ConsensusConfig{Quorum: quorum}.normalized()
+	return int(math.Ceil(config.Quorum*float64(validators) - quorumEpsilon))
+}
+
+// NewVoteRevealRequest builds the signed request that opens the reveal phase for a validator.
+// commits are the signed commitments collected for the request; they prove the quorum.
+func (v *CoreValidator) NewVoteRevealRequest(requestID, validatorID string, commits []*VoteCommitMessage) *VoteRevealMessage {
+	msg := &VoteRevealMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: requestID,
+			Type:      VoteRevealType,
+			Sender:    v.ID,
+			Receiver:  validatorID,
+			Timestamp: time.Now().Unix(),
+		},
+		Commitments: len(commits),
+		Sealed:      commits,
+	}
+
+	v.sign(msg)
+	return msg
+}
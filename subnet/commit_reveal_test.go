@@ -0,0 +1,179 @@
+package subnet
+
+import (
+	"errors"
+	"testing"
+)
+
+// revealFixture is four registered validators committing to votes on one miner response;
+// validators[0] leads the round
+type revealFixture struct {
+	registry   *SignerRegistry
+	response   *MinerResponseMessage
+	validators []*CoreValidator
+}
+
+func newRevealFixture(t *testing.T) *revealFixture {
+	t.Helper()
+	registry := NewSignerRegistry()
+	f := &revealFixture{registry: registry, response: NewCoreMiner("miner-1", "subnet-test").ProcessInput("task", 1, "req-1")}
+	for _, validatorID := range testValidatorIDs {
+		signer, err := GenerateMessageSigner()
+		if err != nil {
+			t.Fatal(err)
+		}
+		registry.Register(validatorID, signer.Address())
+
+		validator := NewCoreValidator(validatorID, "subnet-test", ConsensusValidator, 0.25)
+		validator.SetMessageSigner(signer)
+		validator.SetSignerRegistry(registry)
+		f.validators = append(f.validators, validator)
+	}
+	for _, validator := range f.validators {
+		for i, validatorID := range testValidatorIDs {
+			validator.RegisterParticipant(SequenceParticipant{ClockID: ValidatorClockID(i), Kind: ParticipantValidator, Name: validatorID})
+		}
+	}
+	return f
+}
+
+// commit has every validator commit at the leader's request
+func (f *revealFixture) commit(t *testing.T) []*VoteCommitMessage {
+	t.Helper()
+	commits := make([]*VoteCommitMessage, len(f.validators))
+	for i, validator := range f.validators {
+		commit, err := validator.CommitVote(f.response, f.validators[0].ID)
+		if err != nil {
+			t.Fatalf("%s: CommitVote: %v", validator.ID, err)
+		}
+		commits[i] = commit
+	}
+	return commits
+}
+
+// reveal asks validator i to reveal, presenting commits
+func (f *revealFixture) reveal(i int, commits []*VoteCommitMessage) (*ValidatorVoteMessage, error) {
+	return f.validators[i].RevealVote(f.validators[0].NewVoteRevealRequest(f.response.RequestID, f.validators[i].ID, commits))
+}
+
+func TestRevealRequiresCommitmentQuorum(t *testing.T) {
+	f := newRevealFixture(t)
+	commits := f.commit(t)
+
+	// 2 of 4 is below the 2/3 quorum, even with the validator's own commitment
+	if _, err := f.reveal(1, commits[:2]); !errors.Is(err, ErrRevealTooEarly) {
+		t.Fatalf("reveal with 2/4 commitments = %v, want %v", err, ErrRevealTooEarly)
+	}
+
+	vote, err := f.reveal(1, commits[:3])
+	if err != nil {
+		t.Fatalf("reveal with 3/4 commitments: %v", err)
+	}
+	if err := VerifyVoteReveal(commits[1], vote); err != nil {
+		t.Fatalf("revealed vote does not match its commitment: %v", err)
+	}
+	if err := f.registry.Verify(vote); err != nil {
+		t.Fatalf("revealed vote not signed by its validator: %v", err)
+	}
+
+	again, err := f.reveal(1, commits)
+	if err != nil || again.Signature != vote.Signature || again.Salt != vote.Salt {
+		t.Fatalf("repeated reveal = %+v, %v, want the same signed vote", again, err)
+	}
+}
+
+func TestRevealRequiresOwnCommitment(t *testing.T) {
+	f := newRevealFixture(t)
+	commits := f.commit(t)
+
+	// A quorum that leaves validator-4 out does not open validator-4's vote
+	if _, err := f.reveal(3, commits[:3]); !errors.Is(err, ErrRevealTooEarly) {
+		t.Fatalf("reveal without the validator's own commitment = %v, want %v", err, ErrRevealTooEarly)
+	}
+
+	// Nor does one carrying a different commitment in its name
+	forged := *commits[3]
+	forged.Commitment = commits[2].Commitment
+	f.validators[3].sign(&forged)
+	if _, err := f.reveal(3, []*VoteCommitMessage{commits[0], commits[1], &forged}); !errors.Is(err, ErrRevealTooEarly) {
+		t.Fatalf("reveal with a substituted commitment = %v, want %v", err, ErrRevealTooEarly)
+	}
+}
+
+func TestRevealRejectsPaddedQuorum(t *testing.T) {
+	f := newRevealFixture(t)
+	commits := f.commit(t)
+
+	// The same commitment twice is one validator
+	if _, err := f.reveal(1, []*VoteCommitMessage{commits[0], commits[1], commits[1]}); !errors.Is(err, ErrRevealTooEarly) {
+		t.Fatalf("reveal with a duplicated commitment = %v, want %v", err, ErrRevealTooEarly)
+	}
+
+	// A commitment altered after signing does not count
+	tampered := *commits[2]
+	tampered.Commitment = commits[0].Commitment
+	if _, err := f.reveal(1, []*VoteCommitMessage{commits[0], commits[1], &tampered}); !errors.Is(err, ErrRevealTooEarly) {
+		t.Fatalf("reveal with a tampered commitment = %v, want %v", err, ErrRevealTooEarly)
+	}
+
+	// The claimed count must match the commitments carried
+	request := f.validators[0].NewVoteRevealRequest(f.response.RequestID, f.validators[1].ID, commits[:2])
+	request.Commitments = 4
+	f.validators[0].sign(request)
+	if _, err := f.validators[1].RevealVote(request); !errors.Is(err, ErrRevealTooEarly) {
+		t.Fatalf("reveal claiming 4 commitments with 2 = %v, want %v", err, ErrRevealTooEarly)
+	}
+}
+
+func TestRevealOnlyForRoundLeader(t *testing.T) {
+	f := newRevealFixture(t)
+	commits := f.commit(t)
+
+	if _, err := f.validators[1].CommitVote(f.response, f.validators[2].ID); !errors.Is(err, ErrNotRoundLeader) {
+		t.Fatalf("commit request from another validator = %v, want %v", err, ErrNotRoundLeader)
+	}
+	request := f.validators[2].NewVoteRevealRequest(f.response.RequestID, f.validators[1].ID, commits)
+	if _, err := f.validators[1].RevealVote(request); !errors.Is(err, ErrNotRoundLeader) {
+		t.Fatalf("reveal request from another validator = %v, want %v", err, ErrNotRoundLeader)
+	}
+
+	request = f.validators[0].NewVoteRevealRequest("req-unknown", f.validators[1].ID, commits)
+	if _, err := f.validators[1].RevealVote(request); !errors.Is(err, ErrNoSealedVote) {
+		t.Fatalf("reveal for a request never committed = %v, want %v", err, ErrNoSealedVote)
+	}
+}
+
+func TestVerifyVoteRevealDetectsChangedVote(t *testing.T) {
+	f := newRevealFixture(t)
+	commits := f.commit(t)
+	vote, err := f.reveal(1, commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := *vote
+	changed.Accept = !vote.Accept
+	if err := VerifyVoteReveal(commits[1], &changed); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Fatalf("reveal with the decision flipped = %v, want %v", err, ErrCommitmentMismatch)
+	}
+	if err := VerifyVoteReveal(commits[2], vote); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Fatalf("reveal checked against another validator's commitment = %v, want %v", err, ErrCommitmentMismatch)
+	}
+}
+
+func TestRevealQuorum(t *testing.T) {
+	for _, tc := range []struct {
+		quorum     float64
+		validators int
+		want       int
+	}{
+		{DefaultQuorum, 4, 3},
+		{DefaultQuorum, 3, 2},
+		{1, 4, 4},
+		{0.5, 4, 3}, // Below MinQuorum: the default applies
+	} {
+		if got := RevealQuorum(tc.quorum, tc.validators); got != tc.want {
+			t.Errorf("RevealQuorum(%v, %d) = %d, want %d", tc.quorum, tc.validators, got, tc.want)
+		}
+	}
+}
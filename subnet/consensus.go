@@ -0,0 +1,101 @@
+// Package subnet - Consensus Rules
+//
+// This file defines the quorum rules used by QualityAssessment. Thresholds are
+// expressed as a fraction of the total weight registered in the validator set, so the
+// same rules work for any number of validators and for unequal stake.
+package subnet
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// DefaultQuorum is the fraction of registered weight required to decide (BFT 2/3)
+const DefaultQuorum = 2.0 / 3.0
+
+// MinQuorum is the lowest quorum accepted. At 1/2 or below, accept and reject could both
+// reach the threshold; below 2/3, faulty weight of up to 1/3 could decide a round together
+// with an honest minority.
+const MinQuorum = DefaultQuorum
+
+// quorumEpsilon absorbs floating point error when comparing weight sums (e.g., 3 × 1/3)
+const quorumEpsilon = 1e-9
+
+// ConsensusConfig defines how validator votes are turned into a decision
+type ConsensusConfig struct {
+	// Quorum is the fraction [MinQuorum, 1] of RegisteredWeight that must vote the same
+	// way for a decision. 2/3 tolerates up to 1/3 faulty weight.
+	Quorum float64
+
+	// RegisteredWeight is the total voting weight of the validator set, including
+	// validators that have not voted. Must be > 0.
+	RegisteredWeight float64
+}
+
+// DefaultConsensusConfig returns a 2/3 quorum over the given registered weight
+func DefaultConsensusConfig(registeredWeight float64) ConsensusConfig {
+	return ConsensusConfig{
+		Quorum:           DefaultQuorum,
+		RegisteredWeight: registeredWeight,
+	}
+}
+
+// ConsensusConfigFromEnv returns the default config with the quorum overridden by
+// CONSENSUS_QUORUM (e.g., "0.75") when set to a valid fraction of at least MinQuorum
+func ConsensusConfigFromEnv(registeredWeight float64) ConsensusConfig {
+	config := DefaultConsensusConfig(registeredWeight)
+	if value := os.Getenv("CONSENSUS_QUORUM"); value != "" {
+		quorum, err := strconv.ParseFloat(value, 64)
+		if err != nil || !validQuorum(quorum) {
+			fmt.Printf("⚠️  Ignoring invalid CONSENSUS_QUORUM=%s (must be in [2/3, 1])\n", value)
+		} else {
+			config.Quorum = quorum
+		}
+	}
+	return config
+}
+
+// Threshold returns the weight required for a decision
+func (c ConsensusConfig) Threshold() float64 {
+	return c.Quorum * c.RegisteredWeight
+}
+
+// validQuorum reports whether quorum is in [MinQuorum, 1]
+func validQuorum(quorum float64) bool {
+	return quorum >= MinQuorum-quorumEpsilon && quorum <= 1
+}
+
+// normalized fills zero or unsafe values with defaults (legacy assessments assume total weight 1.0)
+func (c ConsensusConfig) normalized() ConsensusConfig {
+	if !validQuorum(c.Quorum) {
+		c.Quorum = DefaultQuorum
+	}
+	if c.RegisteredWeight <= 0 {
+		c.RegisteredWeight = 1.0
+	}
+	return c
+}
+
+// ConsensusOutcome is the state of a quality assessment
+type ConsensusOutcome string
+
+const (
+	// OutcomePending means more votes could still decide the assessment
+	OutcomePending ConsensusOutcome = "pending"
+	// OutcomeAccepted means accept weight reached the quorum threshold
+	OutcomeAccepted ConsensusOutcome = "accepted"
+	// OutcomeRejected means reject weight reached the quorum threshold
+	OutcomeRejected ConsensusOutcome = "rejected"
+	// OutcomeQuorumNotReached means neither side can reach the threshold
+	// (too many abstentions, a split vote, or voting closed early)
+	OutcomeQuorumNotReached ConsensusOutcome = "quorum_not_reached"
+)
+
+// NewQualityAssessment creates an assessment that decides using the given consensus rules
+func NewQualityAssessment(requestID string, config ConsensusConfig) *QualityAssessment {
+	return &QualityAssessment{
+		RequestID: requestID,
+		Config:    config,
+	}
+}
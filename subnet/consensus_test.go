@@ -0,0 +1,119 @@
+package subnet
+
+import "testing"
+
+// assessmentOf applies accept (true), reject (false) or abstain (nil) votes of equal weight
+// from a registered set of len(votes) validators
+func assessmentOf(votes ...*bool) *QualityAssessment {
+	weight := 1.0 / float64(len(votes))
+	qa := NewQualityAssessment("req-1", DefaultConsensusConfig(1.0))
+	for _, vote := range votes {
+		if vote == nil {
+			qa.AddAbstention(weight)
+		} else {
+			qa.AddVote(weight, *vote)
+		}
+	}
+	return qa
+}
+
+// Votes for assessmentOf
+var (
+	acceptVote = func() *bool { v := true; return &v }()
+	rejectVote = func() *bool { v := false; return &v }()
+)
+
+func TestQualityAssessmentQuorum(t *testing.T) {
+	qa := NewQualityAssessment("req-1", DefaultConsensusConfig(1.0))
+	qa.AddVote(0.25, true)
+	qa.AddVote(0.25, true)
+	if got := qa.Outcome(); got != OutcomePending {
+		t.Fatalf("2/4 accept with 2 outstanding = %s, want %s", got, OutcomePending)
+	}
+	qa.AddVote(0.25, true)
+	if got := qa.Outcome(); got != OutcomeAccepted || !qa.IsAccepted() || !qa.Consensus {
+		t.Fatalf("3/4 accept = %s, want %s", got, OutcomeAccepted)
+	}
+
+	if got := assessmentOf(rejectVote, rejectVote, rejectVote, acceptVote).Outcome(); got != OutcomeRejected {
+		t.Fatalf("3/4 reject = %s, want %s", got, OutcomeRejected)
+	}
+
+	// 2 × 1/3 sums just below 2/3 in floating point; it still reaches the quorum
+	if got := assessmentOf(acceptVote, acceptVote, rejectVote).Outcome(); got != OutcomeAccepted {
+		t.Fatalf("2/3 accept = %s, want %s", got, OutcomeAccepted)
+	}
+}
+
+func TestQualityAssessmentRejectsBelowTwoThirds(t *testing.T) {
+	// A majority of those who voted is not 2/3 of the registered set
+	qa := NewQualityAssessment("req-1", DefaultConsensusConfig(1.0))
+	qa.AddVote(0.25, true)
+	qa.AddVote(0.25, true)
+	qa.AddVote(0.25, false)
+	qa.Close()
+	if got := qa.Outcome(); got != OutcomeQuorumNotReached || qa.IsAccepted() {
+		t.Fatalf("2/4 accept, 1 reject, voting closed = %s, want %s", got, OutcomeQuorumNotReached)
+	}
+
+	// A split vote cannot reach 2/3 on either side, even before voting closes
+	if got := assessmentOf(acceptVote, acceptVote, rejectVote, rejectVote).Outcome(); got != OutcomeQuorumNotReached {
+		t.Fatalf("2/4 accept, 2/4 reject = %s, want %s", got, OutcomeQuorumNotReached)
+	}
+
+	// With a higher quorum, 3/4 is not enough
+	strict := NewQualityAssessment("req-1", ConsensusConfig{Quorum: 0.8, RegisteredWeight: 1.0})
+	for i := 0; i < 3; i++ {
+		strict.AddVote(0.25, true)
+	}
+	if got := strict.Outcome(); got == OutcomeAccepted {
+		t.Fatalf("3/4 accept with quorum 0.8 = %s", got)
+	}
+}
+
+func TestQualityAssessmentAbstentions(t *testing.T) {
+	// Abstained weight counts toward neither side and does not lower the threshold
+	if got := assessmentOf(acceptVote, acceptVote, nil, nil).Outcome(); got != OutcomeQuorumNotReached {
+		t.Fatalf("2/4 accept, 2/4 abstain = %s, want %s", got, OutcomeQuorumNotReached)
+	}
+	if got := assessmentOf(acceptVote, acceptVote, acceptVote, nil).Outcome(); got != OutcomeAccepted {
+		t.Fatalf("3/4 accept, 1/4 abstain = %s, want %s", got, OutcomeAccepted)
+	}
+
+	qa := NewQualityAssessment("req-1", DefaultConsensusConfig(1.0))
+	qa.AddVote(0.25, true)
+	qa.AddAbstention(0.25)
+	if got := qa.Outcome(); got != OutcomePending {
+		t.Fatalf("1/4 accept, 1/4 abstain with 2 outstanding = %s, want %s", got, OutcomePending)
+	}
+	if qa.VoteCount != 2 || qa.TotalWeight != 0.25 || qa.AbstainWeight != 0.25 {
+		t.Fatalf("assessment = %+v, want two votes with 0.25 decided and 0.25 abstained", qa)
+	}
+}
+
+func TestConsensusConfigQuorumBounds(t *testing.T) {
+	// The zero value decides at 2/3 of weight 1.0
+	qa := &QualityAssessment{}
+	qa.AddVote(0.5, true)
+	if got := qa.Outcome(); got != OutcomePending {
+		t.Fatalf("zero config, 1/2 accept = %s, want %s", got, OutcomePending)
+	}
+	qa.AddVote(0.2, true)
+	if got := qa.Outcome(); got != OutcomeAccepted {
+		t.Fatalf("zero config, 0.7 accept = %s, want %s", got, OutcomeAccepted)
+	}
+
+	// A quorum below 2/3 is never used
+	lax := NewQualityAssessment("req-1", ConsensusConfig{Quorum: 0.5, RegisteredWeight: 1.0})
+	lax.AddVote(0.5, true)
+	if got := lax.Outcome(); got == OutcomeAccepted {
+		t.Fatalf("quorum 0.5, 1/2 accept = %s, want the 2/3 default applied", got)
+	}
+
+	for value, want := range map[string]float64{"0.75": 0.75, "1": 1, "0.5": DefaultQuorum, "1.5": DefaultQuorum, "most": DefaultQuorum} {
+		t.Setenv("CONSENSUS_QUORUM", value)
+		if got := ConsensusConfigFromEnv(1.0).Quorum; got != want {
+			t.Errorf("CONSENSUS_QUORUM=%s gives quorum %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package subnet - Context-Aware Task Processing
+//
+// This file defines ContextTaskProcessor, the cancellable successor of TaskProcessor.
+// Processors receive a context carrying the miner's per-task deadline and report
+// failures as errors; the miner turns any failure into a TaskFailed response that
+// validators reject and the round coordinator refunds.
+//
+// Existing TaskProcessor implementations keep working through AdaptTaskProcessor.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultTaskTimeout bounds a single ProcessTask / ProcessAdditionalInfo call.
+// Kept below the HTTP transport's 60s client timeout so the leader receives the
+// TaskFailed response instead of a transport error.
+const DefaultTaskTimeout = 45 * time.Second
+
+// TaskOutcome is the result of one processing step
+type TaskOutcome struct {
+	OutputType  MinerOutputType // OutputReady or NeedMoreInfo
+	Output      string          // Generated solution (if OutputReady)
+	InfoRequest string          // Question for the user (if NeedMoreInfo)
+}
+
+// ContextTaskProcessor is a TaskProcessor that honours cancellation and reports errors.
+// Implementations must return promptly once ctx is done and be safe for concurrent use.
+type ContextTaskProcessor interface {
+	// ProcessTask handles initial user input and determines the response type
+	ProcessTask(ctx context.Context, input string, inputNumber int) (*TaskOutcome, error)
+
+	// ProcessAdditionalInfo handles follow-up processing with user-provided context
+	ProcessAdditionalInfo(ctx context.Context, originalInput string, additionalInfo string, inputNumber int) (*TaskOutcome, error)
+}
+
+// legacyTaskProcessor adapts a TaskProcessor to ContextTaskProcessor
+type legacyTaskProcessor struct {
+	processor TaskProcessor
+}
+
+// AdaptTaskProcessor wraps a TaskProcessor so it can be used where a ContextTaskProcessor
+// is expected. The wrapped call cannot be interrupted: on cancellation the adapter returns
+// ctx.Err() immediately and the call finishes in the background with its result discarded.
+func AdaptTaskProcessor(processor TaskProcessor) ContextTaskProcessor {
+	return &legacyTaskProcessor{processor: processor}
+}
+
+// ProcessTask runs the wrapped ProcessTask until it returns or ctx is done
+func (l *legacyTaskProcessor) ProcessTask(ctx context.Context, input string, inputNumber int) (*TaskOutcome, error) {
+	return runUntilDone(ctx, func() *TaskOutcome {
+		outputType, output, infoRequest := l.processor.ProcessTask(input, inputNumber)
+		return &TaskOutcome{OutputType: outputType, Output: output, InfoRequest: infoRequest}
+	})
+}
+
+// ProcessAdditionalInfo runs the wrapped ProcessAdditionalInfo until it returns or ctx is done
+func (l *legacyTaskProcessor) ProcessAdditionalInfo(ctx context.Context, originalInput string, additionalInfo string, inputNumber int) (*TaskOutcome, error) {
+	return runUntilDone(ctx, func() *TaskOutcome {
+		output := l.processor.ProcessAdditionalInfo(originalInput, additionalInfo, inputNumber)
+		return &TaskOutcome{OutputType: OutputReady, Output: output}
+	})
+}
+
+// runUntilDone runs fn in a goroutine and returns its result, or ctx.Err() if ctx ends first
+func runUntilDone(ctx context.Context, fn func() *TaskOutcome) (*TaskOutcome, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan *TaskOutcome, 1) // Buffered: an abandoned call never blocks
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case outcome := <-done:
+		return outcome, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runTask executes one processing step under the miner's task deadline and fills the
+// response. Any error (including a timeout) produces a TaskFailed response.
+func (m *CoreMiner) runTask(ctx context.Context, response *MinerResponseMessage, step func(ctx context.Context) (*TaskOutcome, error)) {
+	timeout := m.GetTaskTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	outcome, err := step(ctx)
+	if err == nil && outcome == nil {
+		err = errors.New("processor returned no outcome")
+	}
+	if err == nil && outcome.OutputType != OutputReady && outcome.OutputType != NeedMoreInfo {
+		err = fmt.Errorf("processor returned invalid output type %q", outcome.OutputType)
+	}
+
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("task exceeded %v deadline: %w", timeout, err)
+		}
+		response.OutputType = TaskFailed
+		response.Error = err.Error()
+		fmt.Printf("❌ Miner %s: Task %s failed - %v\n", m.ID, response.RequestID, err)
+		return
+	}
+
+	response.OutputType = outcome.OutputType
+	response.Output = outcome.Output
+	response.InfoRequest = outcome.InfoRequest
+}
@@ -6,6 +6,7 @@
 package subnet
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"sync"
@@ -18,6 +19,10 @@ import (
 // TaskProcessor defines the interface for pluggable AI task processing strategies.
 // This abstraction enables the same core miner to work with different AI models,
 // processing algorithms, or business logic while maintaining VLC consistency.
+// Implementations must be safe for concurrent use: the miner does not serialize calls.
+//
+// TaskProcessor cannot be cancelled or report errors; new processors should implement
+// ContextTaskProcessor (see context_task_processor.go).
 type TaskProcessor interface {
 	// ProcessTask handles initial user input and determines response type.
 	// Returns:
@@ -40,10 +45,13 @@ type TaskProcessor interface {
 //   - Each ProcessInput() increments clock (represents logical work)
 //   - Each ProcessAdditionalInfo() increments clock (represents additional logical work)
 //   - Clock values enable validators to verify causal ordering of operations
+//   - Requests are processed concurrently; each request's events are kept in a
+//     RequestTrace (see request_trace.go)
 type CoreMiner struct {
 	// Identity and network information
 	ID       string // Unique miner identifier
 	SubnetID string // Subnet this miner belongs to
+	ClockID  uint64 // VLC node ID this miner advances (see MinerClockIDFor)
 
 	// VLC-based causal consistency
 	VLCClock *vlc.Clock   // Vector clock tracking logical time of operations
@@ -51,14 +59,32 @@ type CoreMiner struct {
 
 	// Processing history and state
 	processedInputs map[int]*MinerResponseMessage // Audit trail of processed tasks
+	requestTraces   map[string]*RequestTrace      // Per-request VLC event history
+
+	// Idempotency (see idempotency.go) and crash recovery (optional, see miner_journal.go)
+	responseCache     map[string]*cachedResponse // requestID#turn -> completed response, for duplicates
+	inFlight          map[string]chan struct{}   // requestID#turn -> closed when the step finishes
+	responseRetention time.Duration              // How long responses stay cached (zero: DefaultResponseRetention)
+	journal           *MinerJournal              // Write-ahead log of clock transitions
+	claims            map[string]bool            // requestID -> payment claimed by this miner (journaled)
+	interrupted       map[string]*vlc.Clock      // requestID -> received clock of a journaled step cut off by a restart
+	reservedEntry     uint64                     // Miner entry reserved for the left event of the latest admitted step
 
 	// Pluggable behavior strategy
-	taskProcessor TaskProcessor // AI/processing logic implementation
+	taskProcessor   ContextTaskProcessor // AI/processing logic implementation
+	dialogProcessor DialogTaskProcessor  // Multi-turn clarification support (if the processor has it)
+	maxDialogTurns  int                  // Clarification questions allowed per task (zero: DefaultMaxDialogTurns)
+	taskTimeout     time.Duration        // Deadline for one processing step (zero: DefaultTaskTimeout)
+	streamProcessor StreamingTaskProcessor // Incremental output support (if the processor has it)
+	streamTransport Transport              // Route for streamed chunks (nil: streaming disabled)
 
 	// Payment verification (optional for trustless operation)
 	paymentVerifier PaymentVerifier // Verifies payment locked in escrow before processing
 	agentAddress    string          // Agent's Ethereum address (for payment verification)
 	minPayment      string          // Minimum payment amount required (in wei)
+
+	// Message authentication
+	signer *MessageSigner // Signs every response so validators can authenticate the miner
 }
 
 // PaymentVerifier interface for verifying escrow payments before task processing
@@ -78,14 +104,61 @@ func NewCoreMiner(id, subnetID string) *CoreMiner {
 	return &CoreMiner{
 		ID:              id,
 		SubnetID:        subnetID,
-		VLCClock:        vlc.New(), // Initialize VLC clock
+		ClockID:         MinerClockID, // Miner-1 unless SetClockID is called
+		VLCClock:        vlc.New(),    // Initialize VLC clock
 		processedInputs: make(map[int]*MinerResponseMessage),
+		requestTraces:   make(map[string]*RequestTrace),
+		responseCache:   make(map[string]*cachedResponse),
+		inFlight:        make(map[string]chan struct{}),
+		claims:          make(map[string]bool),
+		interrupted:     make(map[string]*vlc.Clock),
 	}
 }
 
-// SetTaskProcessor sets the task processing strategy
+// SetTaskProcessor sets the task processing strategy from a legacy TaskProcessor
 func (m *CoreMiner) SetTaskProcessor(processor TaskProcessor) {
+	m.taskProcessor = AdaptTaskProcessor(processor)
+	m.dialogProcessor, _ = processor.(DialogTaskProcessor)
+	m.streamProcessor, _ = processor.(StreamingTaskProcessor)
+}
+
+// SetContextTaskProcessor sets a cancellable task processing strategy
+func (m *CoreMiner) SetContextTaskProcessor(processor ContextTaskProcessor) {
 	m.taskProcessor = processor
+	m.dialogProcessor, _ = processor.(DialogTaskProcessor)
+	m.streamProcessor, _ = processor.(StreamingTaskProcessor)
+}
+
+// SetClockID sets the VLC node ID this miner advances; every miner in a subnet needs its own
+func (m *CoreMiner) SetClockID(clockID uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ClockID = clockID
+}
+
+// GetClockID returns the VLC node ID this miner advances
+func (m *CoreMiner) GetClockID() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ClockID
+}
+
+// SetTaskTimeout sets the deadline for each processing step.
+// A step that exceeds it yields a TaskFailed response.
+func (m *CoreMiner) SetTaskTimeout(timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.taskTimeout = timeout
+}
+
+// GetTaskTimeout returns the deadline applied to each processing step
+func (m *CoreMiner) GetTaskTimeout() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.taskTimeout <= 0 {
+		return DefaultTaskTimeout
+	}
+	return m.taskTimeout
 }
 
 // SetPaymentVerifier configures payment verification for trustless operation
@@ -96,25 +169,63 @@ func (m *CoreMiner) SetPaymentVerifier(verifier PaymentVerifier, agentAddr strin
 	m.minPayment = minPayment
 }
 
+// SetMessageSigner configures the key used to sign outgoing miner responses.
+// Validators holding a SignerRegistry reject responses that are unsigned or forged.
+func (m *CoreMiner) SetMessageSigner(signer *MessageSigner) {
+	m.signer = signer
+}
+
+// signResponse signs a finished response if a signer is configured
+func (m *CoreMiner) signResponse(response *MinerResponseMessage) {
+	if m.signer == nil {
+		return
+	}
+	if err := m.signer.Sign(response); err != nil {
+		fmt.Printf("⚠️  Miner %s: Failed to sign response for %s: %v\n", m.ID, response.RequestID, err)
+	}
+}
+
 // ProcessInput processes initial user input and determines the response type.
 // This method represents the first logical operation in the PoCW protocol.
 //
 // Simplified VLC Behavior:
-//   - Miner ID = 1, Validator-1 ID = 2
-//   - Only these two participants maintain VLC clocks
-//   - Other validators (2-4) just vote without VLC tracking
+//   - The miner advances its ClockID (1 for the first miner), the leader validator
+//     advances ValidatorClockID of its index
+//   - Other validators just vote without VLC tracking
 //
 // Process:
 //   0. (OPTIONAL) Verify payment locked in escrow before doing work
-//   1. Increment VLC clock for miner (ID = 1)
-//   2. Use pluggable TaskProcessor to analyze input
-//   3. Generate response with either solution (OutputReady) or info request (NeedMoreInfo)
-//   4. Store response in processing history
+//   1. Record the received event, snapshot the clock as the response's RequestClock and
+//      apply the entering VLC increment
+//   2. Use pluggable TaskProcessor to analyze input (without holding the miner lock);
+//      an error or a step exceeding the task timeout yields a TaskFailed response
+//   3. Apply the leaving VLC increment and sign the response
+//   4. Store the signed response in processing history
 //
+// Safe for concurrent use: many requests may be in the TaskProcessor at once.
 // Returns MinerResponseMessage that validators will evaluate for consensus.
 func (m *CoreMiner) ProcessInput(input string, inputNumber int, requestID string) *MinerResponseMessage {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	return m.ProcessInputContext(context.Background(), input, inputNumber, requestID)
+}
+
+// ProcessInputContext is ProcessInput with a caller context; cancelling ctx cancels
+// the task processor and yields a TaskFailed response
+func (m *CoreMiner) ProcessInputContext(ctx context.Context, input string, inputNumber int, requestID string) *MinerResponseMessage {
+	return m.processInput(ctx, input, inputNumber, requestID, nil)
+}
+
+// processInput implements ProcessInputContext. step overrides how the task processor is
+// invoked (e.g., streaming); nil calls ProcessTask.
+func (m *CoreMiner) processInput(ctx context.Context, input string, inputNumber int, requestID string, step func(ctx context.Context, response *MinerResponseMessage) (*TaskOutcome, error)) *MinerResponseMessage {
+	// A retried request is answered from the cache without advancing the clock
+	cached, release, err := m.admitStep(ctx, requestID, 0)
+	if err != nil {
+		return m.unprocessedResponse(requestID, inputNumber, TaskFailed, "", fmt.Sprintf("abandoned while a duplicate was processing: %v", err))
+	}
+	if cached != nil {
+		return cached
+	}
+	defer release()
 
 	// STEP 0: Verify payment is locked in escrow (trustless operation)
 	// Agent doesn't trust validator - queries blockchain directly for cryptographic proof
@@ -135,28 +246,18 @@ func (m *CoreMiner) ProcessInput(input string, inputNumber int, requestID string
 			fmt.Printf("   ❌ REFUSING to process task without payment proof\n")
 
 			// Return error response - no work without payment!
-			return &MinerResponseMessage{
-				SubnetMessage: SubnetMessage{
-					SubnetID:  m.SubnetID,
-					RequestID: requestID,
-					Type:      MinerResponseType,
-					Sender:    m.ID,
-					Timestamp: time.Now().Unix(),
-				},
-				VLCClock:    m.VLCClock,
-				InputNumber: inputNumber,
-				OutputType:  OutputReady,
-				Output:      fmt.Sprintf("PAYMENT_VERIFICATION_FAILED: %v", err),
-			}
+			return m.unprocessedResponse(requestID, inputNumber, OutputReady, fmt.Sprintf("PAYMENT_VERIFICATION_FAILED: %v", err), "")
+		}
+
+		// One payment funds one execution, even once the cached response has expired
+		if err := m.claimExecution(requestID); err != nil {
+			fmt.Printf("⚠️  Miner %s: REFUSING to execute task %s again - %v\n", m.ID, requestID, err)
+			return m.unprocessedResponse(requestID, inputNumber, OutputReady, fmt.Sprintf("PAYMENT_ALREADY_CLAIMED: %v", err), "")
 		}
 	} else if isVLCValidation {
 		// VLC validation doesn't require payment
 	}
 
-	// VLC Protocol: +1 for message entering from validator
-	m.VLCClock.Inc(1)
-	fmt.Printf("Miner %s: Message entered from validator → VLC [%d]\n", m.ID, m.VLCClock.Values[1])
-
 	response := &MinerResponseMessage{
 		SubnetMessage: SubnetMessage{
 			SubnetID:  m.SubnetID,
@@ -165,17 +266,25 @@ func (m *CoreMiner) ProcessInput(input string, inputNumber int, requestID string
 			Sender:    m.ID,
 			Timestamp: time.Now().Unix(),
 		},
+		Task:        input,
 		InputNumber: inputNumber,
 	}
+	requestClock, err := m.beginRequest(requestID, inputNumber, "Message entered from validator")
+	if err != nil {
+		return m.unprocessedResponse(requestID, inputNumber, TaskFailed, "", fmt.Sprintf("miner journal write failed: %v", err))
+	}
+	response.RequestClock = requestClock
 
 	// Use pluggable task processor
 	if m.taskProcessor != nil {
-		outputType, output, infoRequest := m.taskProcessor.ProcessTask(input, inputNumber)
-		response.OutputType = outputType
-		response.Output = output
-		response.InfoRequest = infoRequest
+		m.runTask(ctx, response, func(ctx context.Context) (*TaskOutcome, error) {
+			if step != nil {
+				return step(ctx, response)
+			}
+			return m.taskProcessor.ProcessTask(ctx, input, inputNumber)
+		})
 
-		if outputType == NeedMoreInfo {
+		if response.OutputType == NeedMoreInfo {
 			fmt.Printf("Miner %s: Requesting more info\n", m.ID)
 		}
 	} else {
@@ -184,39 +293,14 @@ func (m *CoreMiner) ProcessInput(input string, inputNumber int, requestID string
 		response.Output = "Default processing completed"
 	}
 
-	// VLC Protocol: +1 for message leaving to validator
-	m.VLCClock.Inc(1)
-	fmt.Printf("Miner %s: Message leaving to validator → VLC [%d]\n", m.ID, m.VLCClock.Values[1])
-
-	// Update response with current VLC state (after both increments)
-	response.VLCClock = m.VLCClock
-
-	// Store the response for tracking
-	m.processedInputs[inputNumber] = response
+	m.completeRequest(response, "Message leaving to validator")
 	return response
 }
 
-// ProcessAdditionalInfo processes user-provided additional context to generate final output.
-// This method represents a separate message and logical operation in the simplified VLC flow.
-//
-// Round-Based VLC: This is called after Validator-1 has incremented its clock to provide
-// additional context, so miner processes this as the next logical operation in the round.
-//
-// Process:
-//   1. Increment VLC clock for miner (ID = 1) - represents work of processing additional context
-//   2. Use pluggable TaskProcessor to process original + additional context
-//   3. Generate final response with OutputReady type
-//   4. Update processing history with final response
-//
-// Called after ProcessInput() returned NeedMoreInfo and user provided clarification.
-func (m *CoreMiner) ProcessAdditionalInfo(originalInput string, additionalInfo string, inputNumber int, requestID string) *MinerResponseMessage {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// VLC Protocol: +1 for message entering from validator (additional info)
-	m.VLCClock.Inc(1)
-	fmt.Printf("Miner %s: Additional info entered from validator → VLC [%d]\n", m.ID, m.VLCClock.Values[1])
-
+// unprocessedResponse builds a signed response for a request the miner did not process.
+// It carries the current clock unchanged, marked Unprocessed so validators do not sequence
+// it as a step, and is not cached, so the request can be retried.
+func (m *CoreMiner) unprocessedResponse(requestID string, inputNumber int, outputType MinerOutputType, output, errText string) *MinerResponseMessage {
 	response := &MinerResponseMessage{
 		SubnetMessage: SubnetMessage{
 			SubnetID:  m.SubnetID,
@@ -225,28 +309,41 @@ func (m *CoreMiner) ProcessAdditionalInfo(originalInput string, additionalInfo s
 			Sender:    m.ID,
 			Timestamp: time.Now().Unix(),
 		},
-		OutputType:  OutputReady,
+		VLCClock:    m.GetCurrentClock(),
 		InputNumber: inputNumber,
+		OutputType:  outputType,
+		Output:      output,
+		Error:       errText,
+		Unprocessed: true,
 	}
+	response.OutputCommitment = ResponseCommitment(response)
+	m.signResponse(response)
+	return response
+}
 
-	// Use pluggable task processor for additional info
-	if m.taskProcessor != nil {
-		response.Output = m.taskProcessor.ProcessAdditionalInfo(originalInput, additionalInfo, inputNumber)
-	} else {
-		// Default: simple concatenation
-		response.Output = originalInput + " [Additional: " + additionalInfo + "]"
-	}
-
-	// VLC Protocol: +1 for message leaving to validator (final output)
-	m.VLCClock.Inc(1)
-	fmt.Printf("Miner %s: Final output leaving to validator → VLC [%d]\n", m.ID, m.VLCClock.Values[1])
-
-	// Update response with current VLC state (after both increments)
-	response.VLCClock = m.VLCClock
+// ProcessAdditionalInfo processes user-provided additional context to generate final output.
+// This method represents a separate message and logical operation in the simplified VLC flow.
+//
+// Round-Based VLC: This is called after the leader validator has incremented its clock to
+// provide additional context, so miner processes this as the next logical operation in the round.
+//
+// Process:
+//   1. Record the received event, snapshot the clock as the response's RequestClock and
+//      apply the entering VLC increment
+//   2. Use pluggable TaskProcessor to process original + additional context (unlocked);
+//      an error or a step exceeding the task timeout yields a TaskFailed response
+//   3. Apply the leaving VLC increment and sign the response
+//   4. Update processing history with the signed final response
+//
+// Called after ProcessInput() returned NeedMoreInfo and user provided clarification.
+func (m *CoreMiner) ProcessAdditionalInfo(originalInput string, additionalInfo string, inputNumber int, requestID string) *MinerResponseMessage {
+	return m.ProcessAdditionalInfoContext(context.Background(), originalInput, additionalInfo, inputNumber, requestID)
+}
 
-	// Update stored response
-	m.processedInputs[inputNumber] = response
-	return response
+// ProcessAdditionalInfoContext is ProcessAdditionalInfo with a caller context.
+// It is a single-turn dialog; see ProcessDialogTurnContext.
+func (m *CoreMiner) ProcessAdditionalInfoContext(ctx context.Context, originalInput string, additionalInfo string, inputNumber int, requestID string) *MinerResponseMessage {
+	return m.ProcessDialogTurnContext(ctx, originalInput, []DialogTurn{{Answer: additionalInfo}}, inputNumber, requestID)
 }
 
 // GetCurrentClock returns the current VLC clock value
@@ -262,6 +359,14 @@ func (m *CoreMiner) ResetClock() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.VLCClock = vlc.New()
+	m.processedInputs = make(map[int]*MinerResponseMessage) // Responses carry the discarded clock
+	m.requestTraces = make(map[string]*RequestTrace) // Traces refer to the discarded clock
+	m.responseCache = make(map[string]*cachedResponse) // Cached responses carry discarded clocks
+	m.interrupted = make(map[string]*vlc.Clock)        // Interrupted admissions refer to the discarded clock
+	m.reservedEntry = 0
+	if err := m.journalLocked(JournalReset, "", 0, m.VLCClock.Copy(), nil); err != nil {
+		fmt.Printf("⚠️  Miner %s: Failed to journal clock reset: %v\n", m.ID, err)
+	}
 	fmt.Printf("🔄 Miner %s: VLC clock reset to initial state for subnet operations\n", m.ID)
 }
 
@@ -274,6 +379,9 @@ func (m *CoreMiner) UpdateValidatorClock(validatorClock *vlc.Clock) {
 	
 	// Merge validator's VLC state into miner's clock for causal consistency
 	m.VLCClock.Merge([]*vlc.Clock{validatorClock})
+	if err := m.journalLocked(JournalMerged, "", 0, m.VLCClock.Copy(), nil); err != nil {
+		fmt.Printf("⚠️  Miner %s: Failed to journal clock merge: %v\n", m.ID, err)
+	}
 }
 
 // GetProcessedInputs returns all processed inputs for debugging
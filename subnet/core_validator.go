@@ -4,6 +4,8 @@
 package subnet
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -25,6 +27,9 @@ const (
 	ConsensusValidator
 )
 
+// ErrVLCSequence is returned when a message's clock is not a valid causal successor
+var ErrVLCSequence = errors.New("VLC sequence violation")
+
 // QualityAssessor defines the interface for pluggable quality assessment strategies.
 // Implementations can provide domain-specific logic for evaluating miner output quality.
 // This enables the same core validator to work with different quality metrics.
@@ -34,6 +39,29 @@ type QualityAssessor interface {
 	AssessQuality(response *MinerResponseMessage) (quality float64, accept bool)
 }
 
+// AbstainingQualityAssessor is an optional extension of QualityAssessor for assessors that
+// can decline to judge an output (e.g., an external model timed out or the output is outside
+// the assessor's domain). An abstaining validator still participates in the round, but its
+// weight counts toward neither accept nor reject.
+type AbstainingQualityAssessor interface {
+	QualityAssessor
+
+	// AssessOrAbstain evaluates a response like AssessQuality, or returns a non-empty
+	// abstainReason when no judgement can be made (quality and accept are then ignored).
+	AssessOrAbstain(response *MinerResponseMessage) (quality float64, accept bool, abstainReason string)
+}
+
+// DetailedQualityAssessor is an optional extension of QualityAssessor for assessors that can
+// explain their score. The report's per-criterion breakdown is carried in the validator's vote,
+// so the subnet can see why an output was accepted or rejected.
+type DetailedQualityAssessor interface {
+	QualityAssessor
+
+	// AssessDetailed evaluates a response like AssessQuality and returns the score, decision
+	// and breakdown as a report. A report with AbstainReason set makes the validator abstain.
+	AssessDetailed(response *MinerResponseMessage) *QualityReport
+}
+
 // UserInteractionHandler defines the interface for pluggable user interaction simulation.
 // This abstraction allows different user behavior patterns for testing and demo scenarios.
 type UserInteractionHandler interface {
@@ -42,6 +70,19 @@ type UserInteractionHandler interface {
 	SimulateUserInteraction(inputNumber int, output string) (accept bool, feedback string)
 }
 
+// InteractiveUserHandler is an optional extension of UserInteractionHandler for handlers that
+// reach the user, who may take a while to answer (see user_interaction.go). Both calls block
+// until the user answers, and return ErrNoUserResponse (or ctx's error) if they do not in time.
+type InteractiveUserHandler interface {
+	UserInteractionHandler
+
+	// ReviewOutput shows the user a miner output and returns their decision and feedback
+	ReviewOutput(ctx context.Context, prompt *UserPrompt) (accept bool, feedback string, err error)
+
+	// RequestClarification relays a miner's question and returns the user's answer
+	RequestClarification(ctx context.Context, prompt *UserPrompt) (answer string, err error)
+}
+
 // CoreValidator represents a generic validator node in the PoCW subnet architecture.
 // It provides VLC-based sequence validation, pluggable quality assessment, and consensus voting.
 // The validator tracks miner state using Vector Logical Clocks to ensure causal consistency.
@@ -51,13 +92,27 @@ type CoreValidator struct {
 	SubnetID string        // Subnet this validator belongs to
 	Role     ValidatorRole // Validator's specific role in the subnet
 	Weight   float64       // Voting weight in consensus (e.g., 0.25 for 1/4 validators)
+	ClockID  uint64        // VLC node ID advanced by this validator's orchestration operations
 
 	// VLC-based state tracking
-	MinerClock *vlc.Clock // Vector clock tracking miner's causal state
-	mu         sync.RWMutex // Protects concurrent access to validator state
+	MinerClock    *vlc.Clock        // Vector clock tracking miner's causal state
+	causalBuffer  *vlc.CausalBuffer // Holds early miner responses (nil = strict ValidateSequence)
+	minerClockIDs map[string]uint64 // Miner ID -> VLC node ID (unregistered miners use MinerClockID)
+	mu           sync.RWMutex      // Protects concurrent access to validator state
+
+	// Declarative sequence validation (see sequence_rules.go)
+	participants  map[uint64]SequenceParticipant // Registered VLC participants by clock ID
+	sequenceRules []SequenceRule                 // Per-kind increment rules
+	violations    []SequenceViolation            // Most recent rejected messages
+
+	// Independent miner views and fork detection (see clock_checkpoints.go)
+	minerViews   map[uint64]*minerView // Miner VLC node ID -> this validator's own view
+	forks        []ForkEvidence        // Conflicting miner responses detected
+	forkListener ForkListener          // Notified of detected forks
 
 	// Consensus and quality assessment
-	assessments map[string]*QualityAssessment // Per-request quality tracking
+	assessments     map[string]*QualityAssessment // Per-request quality tracking
+	consensusConfig ConsensusConfig               // Quorum rules for local assessments
 
 	// Pluggable behavior strategies
 	qualityAssessor        QualityAssessor        // Strategy for evaluating output quality
@@ -65,6 +120,18 @@ type CoreValidator struct {
 
 	// x402 Payment integration
 	paymentCoordinator *PaymentCoordinator // Handles payment tokens (USDC/AIUSD) and escrow interactions
+
+	// Message authentication
+	signer         *MessageSigner  // Signs outgoing votes and info requests
+	signerRegistry *SignerRegistry // Known participant addresses for verifying incoming messages
+
+	// Commit-reveal voting
+	sealedVotes map[string]*sealedVote // requestID -> committed vote
+	sealedOrder []string               // requestIDs in commit order, for pruning
+
+	// Streaming output (leader only)
+	streams       map[string]*ChunkAssembler // requestID -> chunks received so far
+	chunkListener ChunkListener              // Shows streamed chunks to the user
 }
 
 // NewCoreValidator creates a new generic validator instance with specified parameters.
@@ -82,8 +149,14 @@ func NewCoreValidator(id, subnetID string, role ValidatorRole, weight float64) *
 		SubnetID:    subnetID,
 		Role:        role,
 		Weight:      weight,
-		MinerClock:  vlc.New(), // Initialize VLC clock
+		ClockID:     ValidatorClockID(0), // Validator-1 unless SetClockID is called
+		MinerClock:  vlc.New(),           // Initialize VLC clock
 		assessments: make(map[string]*QualityAssessment),
+		sealedVotes: make(map[string]*sealedVote),
+		participants: map[uint64]SequenceParticipant{
+			MinerClockID: {ClockID: MinerClockID, Kind: ParticipantMiner, Name: getParticipantName(MinerClockID)},
+		},
+		sequenceRules: DefaultSequenceRules(),
 	}
 }
 
@@ -92,6 +165,27 @@ func (v *CoreValidator) SetQualityAssessor(assessor QualityAssessor) {
 	v.qualityAssessor = assessor
 }
 
+// SetConsensusConfig sets the quorum rules used for this validator's assessments
+func (v *CoreValidator) SetConsensusConfig(config ConsensusConfig) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.consensusConfig = config
+}
+
+// SetWeight updates the validator's voting weight (e.g., after an accountability penalty)
+func (v *CoreValidator) SetWeight(weight float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.Weight = weight
+}
+
+// GetWeight returns the validator's current voting weight
+func (v *CoreValidator) GetWeight() float64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.Weight
+}
+
 // SetUserInteractionHandler sets the user interaction strategy
 func (v *CoreValidator) SetUserInteractionHandler(handler UserInteractionHandler) {
 	v.userInteractionHandler = handler
@@ -102,75 +196,236 @@ func (v *CoreValidator) SetPaymentCoordinator(pc *PaymentCoordinator) {
 	v.paymentCoordinator = pc
 }
 
+// SetMessageSigner configures the key used to sign this validator's votes and info requests
+func (v *CoreValidator) SetMessageSigner(signer *MessageSigner) {
+	v.signer = signer
+}
+
+// SetSignerRegistry configures the participant addresses used to authenticate incoming messages.
+// Once set, VerifyMinerResponse rejects miner responses that are unsigned or forged.
+func (v *CoreValidator) SetSignerRegistry(registry *SignerRegistry) {
+	v.signerRegistry = registry
+}
+
+// VerifyMinerResponse authenticates a miner response before it is validated or voted on,
+// and checks that it commits to its own output. The signature check is skipped when no
+// signer registry is configured (unauthenticated legacy mode).
+func (v *CoreValidator) VerifyMinerResponse(response *MinerResponseMessage) error {
+	if err := VerifyOutputCommitment(response); err != nil {
+		fmt.Printf("🚫 Validator %s: Rejected miner response for %s - %v\n", v.ID, response.RequestID, err)
+		return err
+	}
+	if v.signerRegistry == nil {
+		return nil
+	}
+	if err := v.signerRegistry.Verify(response); err != nil {
+		fmt.Printf("🚫 Validator %s: Rejected miner response for %s - %v\n", v.ID, response.RequestID, err)
+		return err
+	}
+	return nil
+}
+
+// sign signs an outgoing message if a signer is configured
+func (v *CoreValidator) sign(msg Signable) {
+	if v.signer == nil {
+		return
+	}
+	if err := v.signer.Sign(msg); err != nil {
+		fmt.Printf("⚠️  Validator %s: Failed to sign %s for %s: %v\n", v.ID, msg.Header().Type, msg.Header().RequestID, err)
+	}
+}
+
 // ValidateSequence validates the causal ordering using Vector Logical Clocks.
-// In the simplified round-based system, only Miner (ID=1) and Validator-1 (ID=2) 
-// participate in VLC tracking.
-//
-// VLC Validation Rules:
-//   - Bootstrap: Accept first message from any participant
-//   - Increment: Accept +1 increment for the sending participant
-//   - Cross-tracking: Validate causal consistency between both participants
+// The sender must be a registered participant (RegisterParticipant, RegisterMiner); its
+// clock entry must advance exactly as its kind's sequence rule declares for miner
+// responses (see sequence_rules.go). Use CheckSequence for the violation report.
 //
 // Returns true if the clock represents valid causal progression.
 func (v *CoreValidator) ValidateSequence(incomingClock *vlc.Clock, senderID uint64) bool {
+	return v.CheckSequence(MinerResponseType, incomingClock, senderID) == nil
+}
+
+// EnableCausalDelivery makes ReceiveMinerResponse hold miner responses that arrive before
+// their causal predecessors instead of rejecting them. Unless configured otherwise, each
+// registered participant's clock entry is expected to advance as its sequence rule declares.
+func (v *CoreValidator) EnableCausalDelivery(config vlc.CausalBufferConfig) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
-	// Check if this sender is bootstrapped in our tracking
-	_, exists := v.MinerClock.Values[senderID]
-	if !exists {
-		// First message from this sender - bootstrap and accept
-		v.MinerClock.Merge([]*vlc.Clock{incomingClock})
-		fmt.Printf("Validator %s: Bootstrapped %s clock - %v\n", v.ID, getParticipantName(senderID), incomingClock.Values)
-		return true
-	}
-
-	// Miner messages should increment by 2 (enter + leave)
-	// Check if it's a +2 increment from the miner (senderID = 1)
-	if senderID == 1 {
-		expectedValue := v.MinerClock.Values[senderID] + 2
-		if incomingClock.Values[senderID] == expectedValue {
-			v.MinerClock.Merge([]*vlc.Clock{incomingClock})
-			fmt.Printf("Validator %s: VLC sequence validated (+2) for Miner - %v\n", v.ID, incomingClock.Values)
-			return true
+	derive := config.Increments == nil
+	v.causalBuffer = vlc.NewCausalBuffer(config)
+	if derive {
+		v.syncCausalIncrementsLocked()
+	}
+}
+
+// ReceiveMinerResponse validates a miner response's VLC sequence with causal delivery.
+//
+// Returns the responses that are now deliverable, in causal order, after merging them
+// into MinerClock. This may include earlier responses that were waiting on this one.
+// An empty result with nil error means the response is held until its predecessors
+// arrive. Without causal delivery enabled this is equivalent to ValidateSequence.
+// An Unprocessed response took no VLC step; it is delivered as is without touching MinerClock.
+func (v *CoreValidator) ReceiveMinerResponse(response *MinerResponseMessage) ([]*MinerResponseMessage, error) {
+	if response.VLCClock == nil {
+		return nil, fmt.Errorf("%w: %s carries no clock", ErrVLCSequence, response.RequestID)
+	}
+	if response.Unprocessed {
+		return []*MinerResponseMessage{response}, nil
+	}
+
+	v.mu.RLock()
+	buffer := v.causalBuffer
+	clockID := v.minerClockIDLocked(response.Sender)
+	v.mu.RUnlock()
+
+	if err := VerifyRequestCausality(response, clockID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVLCSequence, err)
+	}
+
+	if buffer == nil {
+		if violation := v.CheckSequence(MinerResponseType, response.VLCClock, clockID); violation != nil {
+			return nil, violation
 		}
-		fmt.Printf("Validator %s: VLC sequence error for Miner - expected +2 from %v, got %v\n",
-			v.ID, v.MinerClock.Values, incomingClock.Values)
-		return false
+		return []*MinerResponseMessage{response}, nil
 	}
 
-	// Other senders might use +1 (future extension)
-	if v.MinerClock.IsPlusOneIncrement(incomingClock, senderID) {
-		v.MinerClock.Merge([]*vlc.Clock{incomingClock})
-		fmt.Printf("Validator %s: VLC sequence validated (+1) for %s - %v\n", v.ID, getParticipantName(senderID), incomingClock.Values)
-		return true
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// The buffer bootstraps senders it has no entry for; refuse unregistered ones first
+	// and start registered ones from their baseline
+	participant, _, violation := v.ruleForLocked(MinerResponseType, clockID)
+	if violation != nil {
+		violation.RequestID = response.RequestID
+		v.recordViolationLocked(v.stampViolationLocked(violation, response.VLCClock))
+		return nil, violation
+	}
+
+	delivered, err := buffer.Receive(v.baselineClockLocked(), &vlc.CausalMessage{
+		SenderID: clockID,
+		Clock:    response.VLCClock,
+		Payload:  response,
+	})
+	if err != nil {
+		violation := v.stampViolationLocked(&SequenceViolation{
+			Kind: ViolationCausalDelivery, SenderID: clockID, Participant: participant.Name,
+			MessageType: MinerResponseType, RequestID: response.RequestID, Detail: err.Error(),
+		}, response.VLCClock)
+		v.recordViolationLocked(violation)
+		return nil, violation
 	}
 
-	fmt.Printf("Validator %s: VLC sequence error for %s - expected +1 from %v, got %v\n",
-		v.ID, getParticipantName(senderID), v.MinerClock.Values, incomingClock.Values)
-	return false
+	if len(delivered) == 0 {
+		fmt.Printf("⏸️  Validator %s: Holding %s until causal predecessors arrive (%d buffered)\n",
+			v.ID, response.RequestID, buffer.Pending())
+		return nil, nil
+	}
+
+	responses := make([]*MinerResponseMessage, 0, len(delivered))
+	for _, msg := range delivered {
+		v.MinerClock.Merge([]*vlc.Clock{msg.Clock})
+		released := msg.Payload.(*MinerResponseMessage)
+		responses = append(responses, released)
+		fmt.Printf("Validator %s: VLC causal delivery of %s - %v\n", v.ID, released.RequestID, msg.Clock.Values)
+	}
+	return responses, nil
+}
+
+// ExpireCausalGaps drops held miner responses whose causal predecessors never arrived
+// within the configured gap timeout and returns them for the caller to handle
+func (v *CoreValidator) ExpireCausalGaps() []*MinerResponseMessage {
+	v.mu.RLock()
+	buffer := v.causalBuffer
+	v.mu.RUnlock()
+
+	if buffer == nil {
+		return nil
+	}
+
+	expired := buffer.Expire(time.Now())
+	responses := make([]*MinerResponseMessage, 0, len(expired))
+	for _, msg := range expired {
+		response := msg.Payload.(*MinerResponseMessage)
+		fmt.Printf("⌛ Validator %s: Dropping %s - causal gap never filled\n", v.ID, response.RequestID)
+		responses = append(responses, response)
+	}
+	return responses
 }
 
 // VoteOnOutput evaluates a miner's response and generates a consensus vote.
 // This method focuses purely on quality assessment - VLC validation should be done separately.
 //
 // Process:
-//   1. Create/update quality assessment for this request  
-//   2. Use pluggable quality assessor to evaluate output
+//   1. Use pluggable quality assessor to evaluate output (unlocked)
+//   2. Create/update quality assessment for this request
 //   3. Generate signed vote message with quality score and acceptance decision
 //
+// The vote is signed with the validator's MessageSigner (if configured), covering the
+// quality score, decision, weight and the LastMinerClock snapshot.
+//
 // Note: VLC validation is performed separately as it's a local verification,
 // while quality voting requires distributed consensus.
 func (v *CoreValidator) VoteOnOutput(response *MinerResponseMessage) *ValidatorVoteMessage {
+	verdict := v.judge(response) // May call out to a model; runs without holding v.mu
 
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
+	vote := v.recordVerdictLocked(response, verdict)
+	v.attest(vote)
+	v.sign(vote)
+	return vote
+}
+
+// qualityVerdict is the quality assessor's judgement of one response
+type qualityVerdict struct {
+	quality       float64
+	accept        bool
+	abstainReason string         // Non-empty: the validator abstains
+	report        *QualityReport // DetailedQualityAssessor only
+}
+
+// judge runs the pluggable quality assessor on a response. It does not hold v.mu, so a slow
+// assessor (e.g., a judge model) does not block heartbeats or clock checks meanwhile.
+func (v *CoreValidator) judge(response *MinerResponseMessage) qualityVerdict {
+	if response.OutputType == TaskFailed {
+		// The miner reported no output; there is nothing to assess
+		fmt.Printf("Validator %s: Rejecting failed task %s - %s\n", v.ID, response.RequestID, response.Error)
+		return qualityVerdict{}
+	}
+	if response.Unprocessed {
+		// The miner refused the task; its output is only the reason
+		fmt.Printf("Validator %s: Rejecting unprocessed task %s - %s\n", v.ID, response.RequestID, response.Output)
+		return qualityVerdict{}
+	}
+	if v.qualityAssessor == nil {
+		// Default: accept everything with medium quality
+		return qualityVerdict{quality: 0.75, accept: true}
+	}
+	return runQualityAssessor(v.qualityAssessor, response)
+}
+
+// runQualityAssessor evaluates a response through the richest interface the assessor implements
+func runQualityAssessor(assessor QualityAssessor, response *MinerResponseMessage) qualityVerdict {
+	var verdict qualityVerdict
+	if detailed, ok := assessor.(DetailedQualityAssessor); ok {
+		verdict.report = detailed.AssessDetailed(response)
+		verdict.quality, verdict.accept, verdict.abstainReason = verdict.report.Quality, verdict.report.Accept, verdict.report.AbstainReason
+	} else if abstaining, ok := assessor.(AbstainingQualityAssessor); ok {
+		verdict.quality, verdict.accept, verdict.abstainReason = abstaining.AssessOrAbstain(response)
+	} else {
+		verdict.quality, verdict.accept = assessor.AssessQuality(response)
+	}
+	return verdict
+}
+
+// recordVerdictLocked records a verdict in the local assessment and returns the unsigned
+// vote (caller holds v.mu)
+func (v *CoreValidator) recordVerdictLocked(response *MinerResponseMessage, verdict qualityVerdict) *ValidatorVoteMessage {
 	// Ensure assessment exists for this request
 	if _, exists := v.assessments[response.RequestID]; !exists {
-		v.assessments[response.RequestID] = &QualityAssessment{
-			RequestID: response.RequestID,
-		}
+		v.assessments[response.RequestID] = NewQualityAssessment(response.RequestID, v.consensusConfig)
 	}
 
 	vote := &ValidatorVoteMessage{
@@ -181,27 +436,26 @@ func (v *CoreValidator) VoteOnOutput(response *MinerResponseMessage) *ValidatorV
 			Sender:    v.ID,
 			Timestamp: time.Now().Unix(),
 		},
-		ValidatorID:    v.ID,
-		Weight:         v.Weight,
-		LastMinerClock: v.MinerClock.Copy(), // Include current VLC state for audit trail
-	}
-
-	// Use pluggable quality assessment
-	var quality float64
-	var accept bool
-	if v.qualityAssessor != nil {
-		quality, accept = v.qualityAssessor.AssessQuality(response)
-	} else {
-		// Default: accept everything with medium quality
-		quality, accept = 0.75, true
+		ValidatorID:      v.ID,
+		Weight:           v.Weight,
+		LastMinerClock:   v.MinerClock.Copy(),          // Include current VLC state for audit trail
+		OutputCommitment: ResponseCommitment(response), // Recomputed: binds the vote to the exact output
+		AttestedClock:    response.VLCClock.Copy(),
+		Report:           verdict.report,
 	}
 
-	vote.Quality = quality
-	vote.Accept = accept
-
 	// Add vote to assessment
 	assessment := v.assessments[response.RequestID]
-	assessment.AddVote(v.Weight, accept)
+	if verdict.abstainReason != "" {
+		vote.Abstain = true
+		vote.AbstainReason = verdict.abstainReason
+		assessment.AddAbstention(v.Weight)
+		fmt.Printf("🤷 Validator %s: Abstaining on %s - %s\n", v.ID, response.RequestID, verdict.abstainReason)
+	} else {
+		vote.Quality = verdict.quality
+		vote.Accept = verdict.accept
+		assessment.AddQualityVote(v.Weight, verdict.accept, verdict.quality)
+	}
 
 	return vote
 }
@@ -213,11 +467,11 @@ func (v *CoreValidator) VoteOnOutput(response *MinerResponseMessage) *ValidatorV
 //
 // Returns nil if called on non-UI validator, otherwise returns info request message.
 func (v *CoreValidator) RequestMoreInfo(requestID, question string) *InfoRequestMessage {
-	if v.Role != UserInterfaceValidator {
+	if !v.IsUserInterface() {
 		return nil // Only UI validator can request more info
 	}
 
-	return &InfoRequestMessage{
+	infoRequest := &InfoRequestMessage{
 		SubnetMessage: SubnetMessage{
 			SubnetID:  v.SubnetID,
 			RequestID: requestID,
@@ -227,6 +481,81 @@ func (v *CoreValidator) RequestMoreInfo(requestID, question string) *InfoRequest
 		},
 		Question: question,
 	}
+
+	v.sign(infoRequest)
+	return infoRequest
+}
+
+// NewUserInputMessage builds the signed message that forwards a user task to a miner.
+// The validator's current clock is attached so the miner can merge it before processing,
+// replacing the direct UpdateValidatorClock call used by in-process subnets.
+func (v *CoreValidator) NewUserInputMessage(requestID, minerID, input string, inputNumber int) *UserInputMessage {
+	return v.newUserInputMessage(requestID, minerID, input, inputNumber, false)
+}
+
+// newUserInputMessage builds and signs a user input, optionally asking for a streamed output
+func (v *CoreValidator) newUserInputMessage(requestID, minerID, input string, inputNumber int, stream bool) *UserInputMessage {
+	msg := &UserInputMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: requestID,
+			Type:      UserInputType,
+			Sender:    v.ID,
+			Receiver:  minerID,
+			Timestamp: time.Now().Unix(),
+		},
+		Input:       input,
+		InputNumber: inputNumber,
+		VLCClock:    v.GetLastMinerClock(),
+		Stream:      stream,
+	}
+
+	v.sign(msg)
+	return msg
+}
+
+// NewAdditionalInfoMessage builds the signed message that forwards a user clarification to a miner
+func (v *CoreValidator) NewAdditionalInfoMessage(requestID, minerID, originalInput, additionalInfo string, inputNumber int) *AdditionalInfoMessage {
+	msg := &AdditionalInfoMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: requestID,
+			Type:      AdditionalInfoType,
+			Sender:    v.ID,
+			Receiver:  minerID,
+			Timestamp: time.Now().Unix(),
+		},
+		AdditionalInfo: additionalInfo,
+		OriginalInput:  originalInput,
+		InputNumber:    inputNumber,
+		VLCClock:       v.GetLastMinerClock(),
+	}
+
+	v.sign(msg)
+	return msg
+}
+
+// NewFinalOutputMessage builds the signed round result, carrying the round-end clock
+func (v *CoreValidator) NewFinalOutputMessage(requestID, receiver, output string, inputNumber int, accepted, userRejected bool, consensus float64) *FinalOutputMessage {
+	msg := &FinalOutputMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: requestID,
+			Type:      FinalOutputType,
+			Sender:    v.ID,
+			Receiver:  receiver,
+			Timestamp: time.Now().Unix(),
+		},
+		Output:       output,
+		Accepted:     accepted,
+		UserRejected: userRejected,
+		Consensus:    consensus,
+		InputNumber:  inputNumber,
+		VLCClock:     v.GetLastMinerClock(),
+	}
+
+	v.sign(msg)
+	return msg
 }
 
 // GetAssessment returns the current quality assessment for a request
@@ -236,14 +565,9 @@ func (v *CoreValidator) GetAssessment(requestID string) *QualityAssessment {
 
 	if assessment, exists := v.assessments[requestID]; exists {
 		// Return a copy to avoid race conditions
-		return &QualityAssessment{
-			RequestID:   assessment.RequestID,
-			TotalWeight: assessment.TotalWeight,
-			AcceptVotes: assessment.AcceptVotes,
-			RejectVotes: assessment.RejectVotes,
-			VoteCount:   assessment.VoteCount,
-			Consensus:   assessment.Consensus,
-		}
+		snapshot := *assessment
+		snapshot.Scores = append([]WeightedScore(nil), assessment.Scores...)
+		return &snapshot
 	}
 	return nil
 }
@@ -267,12 +591,12 @@ func (v *CoreValidator) UpdateMinerClock(minerClock *vlc.Clock) {
 
 // IncrementValidatorClock increments validator's own VLC for validator operations
 // Called when validator performs round orchestration operations (user input, final output, etc.)
+// The entry advanced is the validator's ClockID, so the clock shows which validator led the round.
 func (v *CoreValidator) IncrementValidatorClock() {
 	v.mu.Lock()
 	defer v.mu.Unlock()
-	
-	const validatorID uint64 = 2 // Validator-1 ID in round-based system
-	v.MinerClock.Inc(validatorID)
+
+	v.MinerClock.Inc(v.ClockID)
 	fmt.Printf("Validator %s: Incremented VLC for validator operation - %v\n", v.ID, v.MinerClock.Values)
 }
 
@@ -287,13 +611,15 @@ func (v *CoreValidator) SimulateUserInteraction(inputNumber int, output string)
 
 // getParticipantName returns human-readable name for VLC participant IDs
 func getParticipantName(id uint64) string {
-	switch id {
-	case 1:
+	switch {
+	case id == MinerClockID:
 		return "Miner"
-	case 2:
-		return "Validator-1"
-	default:
+	case IsMinerClockID(id):
+		return fmt.Sprintf("Miner-%d", id-MinerClockIDBase+1)
+	case id == 0:
 		return fmt.Sprintf("Participant-%d", id)
+	default:
+		return fmt.Sprintf("Validator-%d", id-MinerClockID)
 	}
 }
 
@@ -343,6 +669,45 @@ func (v *CoreValidator) FinalizePayment(requestID string, consensusReached bool,
 	}
 }
 
+// RefundUndecidedPayment refunds a task whose validators could not reach a quorum
+// (e.g., too many validators missed the vote deadline). The client is not charged
+// for work the subnet could not judge.
+func (v *CoreValidator) RefundUndecidedPayment(requestID string) error {
+	if v.paymentCoordinator == nil {
+		// Payment system not configured - nothing to refund
+		return nil
+	}
+
+	v.paymentCoordinator.UpdatePaymentConsensus(requestID, false, 0)
+	if v.paymentCoordinator.GetPaymentMode() == "direct" {
+		fmt.Printf("↩️  Validator %s: Discarding direct payment for request %s (undecided: no quorum)\n",
+			v.ID, requestID)
+	} else {
+		fmt.Printf("↩️  Validator %s: Refunding payment from escrow for request %s (undecided: no quorum)\n",
+			v.ID, requestID)
+	}
+	return v.paymentCoordinator.RefundPayment(requestID)
+}
+
+// RefundFailedTask refunds a task the miner could not complete (TaskFailed response,
+// e.g., the task processor timed out). The client is not charged for work not delivered.
+func (v *CoreValidator) RefundFailedTask(requestID string, reason string) error {
+	if v.paymentCoordinator == nil {
+		// Payment system not configured - nothing to refund
+		return nil
+	}
+
+	v.paymentCoordinator.UpdatePaymentConsensus(requestID, false, 0)
+	if v.paymentCoordinator.GetPaymentMode() == "direct" {
+		fmt.Printf("↩️  Validator %s: Discarding direct payment for request %s (task failed: %s)\n",
+			v.ID, requestID, reason)
+	} else {
+		fmt.Printf("↩️  Validator %s: Refunding payment from escrow for request %s (task failed: %s)\n",
+			v.ID, requestID, reason)
+	}
+	return v.paymentCoordinator.RefundPayment(requestID)
+}
+
 // GetPaymentStatus returns current payment status for a request
 func (v *CoreValidator) GetPaymentStatus(requestID string) *PaymentTracker {
 	if v.paymentCoordinator == nil {
@@ -11,9 +11,11 @@
 package demo
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -26,223 +28,184 @@ import (
 // plugins to create a realistic but controlled testing environment.
 //
 // Architecture:
-//   - Uses 1 miner with DemoTaskProcessor (hardcoded AI responses)
+//   - Uses DEMO_MINER_COUNT miners (default 1) with DemoTaskProcessor (hardcoded AI responses);
+//     a TaskRouter assigns each task to one of them (TASK_ROUTING)
 //   - Uses 4 validators with DemoQualityAssessor and DemoUserInteractionHandler
 //   - Processes 7 predefined inputs with known expected outcomes
 //   - Demonstrates both normal processing and info request scenarios
+//
+// All miner and validator interaction goes through Transport. In a single process the
+// transport is in-memory; in a networked subnet only Validator-1 is local and the miners
+// and other validators are reached over HTTP (see NewNetworkedDemoCoordinator). Rounds of
+// an epoch led by a remote validator are delegated to it and recorded from its result.
 type DemoCoordinator struct {
 	SubnetID            string                            // Unique identifier for this demo subnet
-	Miner               *subnet.CoreMiner                 // AI agent processing tasks
-	Validators          []*subnet.CoreValidator           // Quality assessment and consensus nodes
+	Miners              []*subnet.CoreMiner               // AI agents hosted in this process (empty when all miners are remote)
+	Validators          []*subnet.CoreValidator           // Validators hosted in this process
+	MinerIDs            []string                          // Participant IDs of all miners on the transport
+	Router              *subnet.TaskRouter                // Assigns each task to a miner
+	ValidatorIDs        []string                          // Participant IDs of all voting validators on the transport
+	Transport           subnet.Transport                  // Message transport between participants
+	Consensus           subnet.ConsensusConfig            // Quorum rules for round decisions
+	Accountability      *subnet.ValidatorAccountability   // Validator agreement tracking and weight slashing
+	CommitReveal        bool                              // Validators commit to sealed votes before revealing them
+	VoteDeadline        time.Duration                     // How long a round waits for validator votes
+	MinerTimeout        time.Duration                     // How long one request to a miner may take (task deadline plus overhead)
+	MaxDialogTurns      int                               // Clarification questions relayed per task before the round is dropped
+	StreamOutput        bool                              // Ask the miner to stream outputs to the leader (networked: the leader must listen)
+	CertificateSigners  []common.Address                  // Validator set that quorum certificates must verify against
+	Leaders             *subnet.LeaderSchedule            // Per-epoch rotation of the user-interface (leader) role
+	gateway             *subnet.CoreValidator             // Local validator that fronts users and signs election messages
+	leaderID            string                            // Validator leading the current epoch (local or remote)
+	leader              *subnet.CoreValidator             // Local validator orchestrating rounds (nil while a remote validator leads)
+	roundClock          *vlc.Clock                        // Leader clock at the end of the last round, handed to the next leader
 	userInputs          []string                          // Predefined demo inputs for consistent testing
 	GraphAdapter        *subnet.SubnetGraphAdapter        // Graph adapter for VLC event visualization
 	PaymentCoord        *subnet.PaymentCoordinator        // x402 payment system integration
 	ReputationMgr       *subnet.ReputationFeedbackManager // Reputation feedback auth generation
 	ReputationSubmitter *subnet.ReputationBatchSubmitter  // Reputation feedback batch submission
+	Signers             *subnet.SignerRegistry            // Participant signing addresses for message authentication
+	minerAddresses      map[string]string                 // Miner ID -> agent address paid and rewarded for its tasks
+	minerAgentIDs       map[string]*big.Int               // Miner ID -> ERC-8004 agent ID credited with its reputation
+	sessionMiners       map[int]string                    // Epoch -> miner serving that epoch's payment session
 }
 
-// NewDemoCoordinator creates a new demo coordinator with all PoC-specific logic
+// NewDemoCoordinator creates a new demo coordinator with all PoC-specific logic.
+// The miner and all validators run in this process and talk over an in-memory transport.
 func NewDemoCoordinator(subnetID string) *DemoCoordinator {
-	// Create core miner with demo task processor
-	miner := subnet.NewCoreMiner("miner-1", subnetID)
-	miner.SetTaskProcessor(NewDemoTaskProcessor())
+	// Every participant signs its messages; receivers verify against this registry
+	signers := subnet.NewSignerRegistry()
+	transport := subnet.NewInMemoryTransport()
+
+	// Create core miners with demo task processor
+	minerIDs := DemoMinerIDs()
+	miners := make([]*subnet.CoreMiner, len(minerIDs))
+	for i := range miners {
+		miners[i] = NewDemoMiner(i+1, subnetID, signers)
+		miners[i].SetStreamTransport(transport)
+		if err := transport.Register(miners[i].ID, subnet.NewMinerHandler(miners[i], signers)); err != nil {
+			fmt.Printf("❌ Failed to register %s on transport: %v\n", miners[i].ID, err)
+			os.Exit(1)
+		}
+	}
 
 	// Create core validators with demo plugins
-	validators := make([]*subnet.CoreValidator, 4)
-	for i := 0; i < 4; i++ {
-		role := subnet.ConsensusValidator
-		if i == 0 {
-			role = subnet.UserInterfaceValidator // First validator handles user interaction
-		}
-
-		validator := subnet.NewCoreValidator(
-			fmt.Sprintf("validator-%d", i+1),
-			subnetID,
-			role,
-			0.25, // Equal weights for 4 validators
-		)
+	validators := make([]*subnet.CoreValidator, DemoValidatorCount)
+	for i := range validators {
+		validators[i] = NewDemoValidator(i+1, subnetID, signers)
+		if err := transport.Register(validators[i].ID, subnet.NewValidatorHandler(validators[i])); err != nil {
+			fmt.Printf("❌ Failed to register %s on transport: %v\n", validators[i].ID, err)
+			os.Exit(1)
+		}
+	}
 
-		// Set demo-specific plugins
-		validator.SetQualityAssessor(NewDemoQualityAssessor())
-		validator.SetUserInteractionHandler(NewDemoUserInteractionHandler())
+	dc := newDemoCoordinator(subnetID, transport, signers, validators[0], minerIDs, DemoValidatorIDs())
+	dc.Miners = miners
+	dc.Validators = validators
+	dc.registerParticipants()
 
-		validators[i] = validator
+	// Simulate a crashed validator to exercise leader failover and vote deadlines
+	if crashed := os.Getenv("DEMO_CRASHED_VALIDATOR"); crashed != "" {
+		transport.Unregister(crashed)
+		fmt.Printf("💥 Simulating crashed validator %s (unreachable on transport)\n", crashed)
 	}
 
-	// Create graph adapter for visualization
-	graphAdapter := subnet.NewSubnetGraphAdapter(subnetID, 1, "subnet-coordinator")
+	if dc.PaymentCoord != nil {
+		for _, miner := range miners {
+			configureMinerPayment(miner, dc.PaymentCoord, dc.minerAddresses[miner.ID])
+		}
+	}
+	return dc
+}
 
-	// Check if running in subnet-only mode (skip payment and reputation systems)
-	subnetOnlyMode := os.Getenv("SUBNET_ONLY_MODE") == "true"
+// NewNetworkedDemoCoordinator creates a coordinator for a subnet whose participants run as
+// separate processes. Only uiValidator lives in this process; when a remote validator is
+// elected, rounds are delegated to it (it serves them with LeaderHandler). The miners in minerIDs (miner-N advances VLC
+// entry MinerClockIDFor(N-1)) and validators listed in validatorIDs are reached through
+// transport. uiValidator must already be registered on the transport if it is listed in validatorIDs.
+func NewNetworkedDemoCoordinator(subnetID string, transport subnet.Transport, signers *subnet.SignerRegistry, uiValidator *subnet.CoreValidator, minerIDs []string, validatorIDs []string) *DemoCoordinator {
+	dc := newDemoCoordinator(subnetID, transport, signers, uiValidator, minerIDs, validatorIDs)
+	dc.registerParticipants()
+	return dc
+}
 
-	// Get RPC URL for blockchain interactions (used by payment and reputation systems)
-	rpcURL := os.Getenv("RPC_URL")
-	if rpcURL == "" {
-		rpcURL = "http://localhost:8545" // Default to localhost if not set
-	}
+// newDemoCoordinator wires the shared parts of a coordinator: graph adapter, task routing,
+// payments and reputation
+func newDemoCoordinator(subnetID string, transport subnet.Transport, signers *subnet.SignerRegistry, uiValidator *subnet.CoreValidator, minerIDs []string, validatorIDs []string) *DemoCoordinator {
+	// Create graph adapter for visualization
+	graphAdapter := subnet.NewSubnetGraphAdapter(subnetID, 1, "subnet-coordinator")
 
-	// Check if running in validation-only mode
-	validationOnlyMode := os.Getenv("VALIDATION_ONLY_MODE") == "true"
+	rpcURL := demoRPCURL()
 
 	var paymentCoord *subnet.PaymentCoordinator
-	if !subnetOnlyMode && !validationOnlyMode {
-		// Initialize payment coordinator (x402 payment system)
-		fmt.Println("💰 Initializing x402 Payment System...")
-
-		// V1 Coordinator key - use environment or fallback to local
-		v1CoordKey := os.Getenv("VALIDATOR_1_KEY")
-		if v1CoordKey == "" {
-			v1CoordKey = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
-		}
+	var reputationManager *subnet.ReputationFeedbackManager
+	var reputationSubmitter *subnet.ReputationBatchSubmitter
 
-		var err error
-		paymentCoord, err = subnet.NewPaymentCoordinator(
-			rpcURL,
-			"contract_addresses.json",
-			v1CoordKey,
-		)
-		if err != nil {
-			fmt.Printf("⚠️  Payment system unavailable: %v\n", err)
-			fmt.Println("   Continuing without payment integration...")
-			paymentCoord = nil
-		} else {
-			fmt.Println("✅ Payment coordinator initialized successfully")
+	// Skip payment and reputation systems in subnet-only and validation-only modes
+	// (in validation-only mode the agent is not registered yet)
+	if paymentsEnabled() {
+		paymentCoord = newDemoPaymentCoordinator(rpcURL)
+		if paymentCoord != nil {
 			// Set payment coordinator in UI validator (validator-1)
-			validators[0].SetPaymentCoordinator(paymentCoord)
-
-			// Configure miner with payment verification (trustless operation)
-			// Miner will verify payment is locked in escrow before processing tasks
-			// Agent address - use environment or fallback to local
-			agentAddress := os.Getenv("MINER_ADDRESS")
-			if agentAddress == "" {
-				agentAddress = "0x9965507D1a55bcC2695C58ba16FB37d819B0A4dc"
-			}
-			minPayment := "10000000" // 10 tokens minimum (10 * 10^6 wei for USDC decimals)
-			miner.SetPaymentVerifier(paymentCoord, agentAddress, minPayment)
-			fmt.Printf("🔐 Miner configured with payment verification\n")
-			fmt.Printf("   Agent address: %s\n", agentAddress)
-			fmt.Printf("   Minimum payment: 10 %s\n", paymentCoord.GetPaymentTokenName())
+			uiValidator.SetPaymentCoordinator(paymentCoord)
 		}
+		reputationManager, reputationSubmitter = newDemoReputationSystem(rpcURL)
+	} else if os.Getenv("VALIDATION_ONLY_MODE") == "true" {
+		fmt.Println("⏭️  Skipping payment system initialization (validation-only mode)")
 	} else {
-		if validationOnlyMode {
-			fmt.Println("⏭️  Skipping payment system initialization (validation-only mode)")
-		} else {
-			fmt.Println("⏭️  Skipping payment system initialization (subnet-only mode)")
-		}
-		paymentCoord = nil
+		fmt.Println("⏭️  Skipping payment system initialization (subnet-only mode)")
 	}
 
-	var reputationManager *subnet.ReputationFeedbackManager
-	var reputationSubmitter *subnet.ReputationBatchSubmitter
-
-	// Skip reputation system if in validation-only mode (agent not registered yet)
-	if !subnetOnlyMode && !validationOnlyMode {
-		// Initialize reputation feedback manager
-		fmt.Println("⭐ Initializing Reputation Feedback System...")
-
-		// IdentityRegistry address from environment
-		identityRegistryAddrStr := os.Getenv("IDENTITY_REGISTRY_ADDRESS")
-		if identityRegistryAddrStr == "" {
-			fmt.Printf("❌ IDENTITY_REGISTRY_ADDRESS not set\n")
-			os.Exit(1)
-		}
-		identityRegistryAddr := common.HexToAddress(identityRegistryAddrStr)
-
-		// Get chain ID from environment or default to 31337 (local)
-		chainIDStr := os.Getenv("CHAIN_ID")
-		chainIDValue := uint64(31337) // Default to local
-		if chainIDStr != "" {
-			if parsedChainID, err := strconv.ParseUint(chainIDStr, 10, 64); err == nil {
-				chainIDValue = parsedChainID
-			}
-		}
-
-		// Get miner key from environment or fallback to local
-		minerKey := os.Getenv("MINER_KEY")
-		if minerKey == "" {
-			minerKey = "0x8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba"
-		}
-
-		// Get client address from environment or fallback to Sepolia
-		clientAddr := os.Getenv("CLIENT_ADDRESS")
-		if clientAddr == "" {
-			clientAddr = "0xfA6EC9Cf1E293A91a8ea2EdCc4A2324d48129821" // Sepolia client with USDC
-		}
-
-		// Get agent ID from environment (set by run-flux-mining.sh after querying blockchain)
-		agentIDStr := os.Getenv("AGENT_ID_DEC")
-		if agentIDStr == "" {
-			fmt.Printf("❌ AGENT_ID_DEC not set - agent must be registered on blockchain first\n")
-			fmt.Printf("   Run the script which queries/registers agent ID from IdentityRegistry\n")
-			os.Exit(1)
-		}
-
-		agentID, err := strconv.ParseUint(agentIDStr, 10, 64)
-		if err != nil {
-			fmt.Printf("❌ Invalid AGENT_ID_DEC: %s - must be a valid number\n", agentIDStr)
-			os.Exit(1)
-		}
-
-		fmt.Printf("   Using Agent ID: %d (from blockchain)\n", agentID)
+	// Track validator behavior against consensus; penalties lower effective voting weight
+	accountability := subnet.NewValidatorAccountability(subnet.AccountabilityConfig{
+		AuditLogPath: os.Getenv("ACCOUNTABILITY_AUDIT_LOG"),
+	})
+	for _, id := range validatorIDs {
+		accountability.RegisterValidator(id, 1.0/DemoValidatorCount)
+	}
 
-		reputationMgr, err := subnet.NewReputationFeedbackManager(
-			agentID,                         // Agent ID from environment (e.g., 1168)
-			minerKey,                        // Miner's private key - from environment or local fallback
-			common.HexToAddress(clientAddr), // Client address - from environment or local fallback
-			identityRegistryAddr,
-			chainIDValue, // Use environment chain ID or default
-		)
-		if err != nil {
-			fmt.Printf("⚠️  Reputation init failed: %v\n", err)
-			reputationMgr = nil
+	// Tasks and their rewards go to the miner the router picks
+	minerAddresses := make(map[string]string, len(minerIDs))
+	for i, minerID := range minerIDs {
+		address := demoMinerAddress(i + 1)
+		if signer, ok := signers.Address(minerID); ok && address == "" {
+			address = signer.Hex()
 		}
-
-		// Initialize reputation batch submitter (client-side)
-		if reputationMgr != nil {
-			// ReputationRegistry address from environment or contract_addresses.json
-			reputationRegistryAddrStr := os.Getenv("REPUTATION_REGISTRY_ADDRESS")
-			if reputationRegistryAddrStr == "" {
-				fmt.Printf("❌ REPUTATION_REGISTRY_ADDRESS not set\n")
-				os.Exit(1)
-			}
-			reputationRegistryAddr := common.HexToAddress(reputationRegistryAddrStr)
-
-			// Initialize TaskIndexCounter from blockchain to prevent IndexLimit errors
-			_ = reputationMgr.InitializeFromBlockchain(rpcURL, reputationRegistryAddr)
-
-			// Get client key from environment or fallback to Sepolia
-			clientKey := os.Getenv("CLIENT_KEY")
-			if clientKey == "" {
-				clientKey = "0xdbda1821b80551c9d65939329250298aa3472ba22feea921c0cf5d620ea67b97" // Sepolia client key
-			}
-
-			submitter, err := subnet.NewReputationBatchSubmitter(
-				rpcURL, // Use RPC URL from environment
-				reputationRegistryAddr,
-				clientKey,    // Client's private key - from environment or local fallback
-				chainIDValue, // Use environment chain ID or default
-			)
-			if err != nil {
-				fmt.Printf("⚠️  Batch submitter failed: %v\n", err)
-				reputationSubmitter = nil
-			} else {
-				reputationSubmitter = submitter
-			}
-		}
-		reputationManager = reputationMgr
-	} else {
-		reputationManager = nil
-		reputationSubmitter = nil
+		minerAddresses[minerID] = address
+		graphAdapter.SetMinerAddress(minerID, address)
+	}
+	var minerAgentIDs map[string]*big.Int
+	if reputationManager != nil {
+		minerAgentIDs = demoMinerAgentIDs(minerIDs, reputationManager.AgentID)
 	}
 
-	return &DemoCoordinator{
+	dc := &DemoCoordinator{
 		SubnetID:            subnetID,
-		Miner:               miner,
-		Validators:          validators,
+		Validators:          []*subnet.CoreValidator{uiValidator},
+		MinerIDs:            minerIDs,
+		Router:              subnet.NewTaskRouter(routingStrategyFromEnv(), minerIDs),
+		ValidatorIDs:        validatorIDs,
+		Transport:           transport,
+		Consensus:           subnet.ConsensusConfigFromEnv(float64(len(validatorIDs)) / DemoValidatorCount), // Equal weights
 		GraphAdapter:        graphAdapter,
 		PaymentCoord:        paymentCoord,
 		ReputationMgr:       reputationManager,
 		ReputationSubmitter: reputationSubmitter,
+		Signers:             signers,
+		minerAddresses:      minerAddresses,
+		minerAgentIDs:       minerAgentIDs,
+		sessionMiners:       make(map[int]string),
+		Accountability:      accountability,
+		CommitReveal:        os.Getenv("COMMIT_REVEAL_VOTING") == "true",
+		VoteDeadline:        voteDeadlineFromEnv(),
+		MinerTimeout:        taskTimeoutFromEnv() + minerExchangeSlack,
+		MaxDialogTurns:      maxDialogTurnsFromEnv(),
+		StreamOutput:        os.Getenv("STREAM_OUTPUT") == "true",
+		CertificateSigners:  certificateValidatorSet(subnetID, signers, validatorIDs),
+		Leaders:             subnet.NewLeaderSchedule(subnetID, validatorIDs),
+		gateway:             uiValidator,
+		leaderID:            uiValidator.ID,
+		leader:              uiValidator,
 		userInputs: []string{
 			"Analyze market trends for Q4",
 			"Generate summary report for project Alpha",
@@ -253,23 +216,48 @@ func NewDemoCoordinator(subnetID string) *DemoCoordinator {
 			"Provide comprehensive analysis of system architecture",
 		},
 	}
+
+	// Keep local validators' own weight in sync with accountability penalties
+	accountability.OnWeightChange = func(change subnet.WeightChange) {
+		for _, validator := range dc.Validators {
+			if validator.ID == change.ValidatorID {
+				validator.SetWeight(change.NewWeight)
+			}
+		}
+	}
+	return dc
 }
 
-// RunVLCValidation performs VLC protocol validation on the miner before allowing subnet operations.
-// This validates that the agent correctly implements Vector Logical Clock causality.
-// Returns true if validation passes, false otherwise.
+// RunVLCValidation performs VLC protocol validation on every miner before allowing subnet operations.
+// This validates that each agent correctly implements Vector Logical Clock causality.
+// Returns true if every miner passes, false otherwise.
 func (dc *DemoCoordinator) RunVLCValidation() bool {
 	fmt.Println("\n╔══════════════════════════════════════════════════════════════╗")
 	fmt.Println("║              AGENT VLC PROTOCOL VALIDATION                  ║")
 	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
 	fmt.Println()
 
+	// VLC validation drives the miners directly, so it needs in-process miners
+	if len(dc.Miners) == 0 {
+		fmt.Printf("❌ VLC validation requires the miners in this process (miners %v are remote)\n", dc.MinerIDs)
+		return false
+	}
+
+	for i, miner := range dc.Miners {
+		if !dc.validateMinerVLC(miner, fmt.Sprintf("vlc-validation-test-%d", i+1)) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateMinerVLC runs the VLC protocol test for one miner and resets its clock if it passes
+func (dc *DemoCoordinator) validateMinerVLC(miner *subnet.CoreMiner, requestID string) bool {
 	// Use only Validator-1 for testing the miner
 	fmt.Printf("═══ Validator-1 Testing Agent ═══\n")
 
 	validator := dc.Validators[0]  // Use only first validator
-	requestID := "vlc-validation-test-1"
-	test := validator.ValidateAgentVLC(dc.Miner, requestID)
+	test := validator.ValidateAgentVLC(miner, requestID)
 
 	result := validator.CreateVLCValidationResult(test)
 	score := result.Score
@@ -293,7 +281,7 @@ func (dc *DemoCoordinator) RunVLCValidation() bool {
 		fmt.Println("╔══════════════════════════════════════════════════════════════╗")
 		fmt.Println("║        ✅ AGENT PASSED VLC PROTOCOL VALIDATION              ║")
 		fmt.Println("║                                                              ║")
-		fmt.Printf("║  Agent: %-52s ║\n", dc.Miner.ID)
+		fmt.Printf("║  Agent: %-52s ║\n", miner.ID)
 		fmt.Printf("║  Score: %d/100                                               ║\n", score)
 		fmt.Println("║  Status: AUTHORIZED FOR SUBNET OPERATIONS                   ║")
 		fmt.Println("║                                                              ║")
@@ -305,14 +293,14 @@ func (dc *DemoCoordinator) RunVLCValidation() bool {
 		fmt.Println()
 
 		// Reset miner's VLC clock for fresh start in actual subnet operations
-		dc.Miner.ResetClock()
+		miner.ResetClock()
 
 		return true
 	} else {
 		fmt.Println("╔══════════════════════════════════════════════════════════════╗")
 		fmt.Println("║        ❌ AGENT FAILED VLC PROTOCOL VALIDATION              ║")
 		fmt.Println("║                                                              ║")
-		fmt.Printf("║  Agent: %-52s ║\n", dc.Miner.ID)
+		fmt.Printf("║  Agent: %-52s ║\n", miner.ID)
 		fmt.Printf("║  Score: %d/100 (Required: ≥70)                               ║\n", score)
 		fmt.Println("║  Status: NOT AUTHORIZED                                     ║")
 		fmt.Println("║                                                              ║")
@@ -327,10 +315,10 @@ func (dc *DemoCoordinator) RunVLCValidation() bool {
 func (dc *DemoCoordinator) RunDemo() {
 	fmt.Printf("\n\n=== Starting Demo ===\n")
 	fmt.Printf("Subnet ID: %s\n", dc.SubnetID)
-	fmt.Printf("Miner: %s\n", dc.Miner.ID)
+	fmt.Printf("Miners: %v (routing: %s)\n", dc.MinerIDs, dc.Router.Strategy)
 	fmt.Printf("Validators: ")
-	for _, v := range dc.Validators {
-		fmt.Printf("%s ", v.ID)
+	for _, id := range dc.ValidatorIDs {
+		fmt.Printf("%s ", id)
 	}
 	fmt.Printf("Graph Adapter: Enabled for VLC event visualization\n")
 	fmt.Printf("\n")
@@ -384,31 +372,228 @@ func (dc *DemoCoordinator) RunDemo() {
 	}
 }
 
-// processInput handles a single user input through the complete round-based workflow with VLC
+// demoRoundsPerEpoch is the number of rounds in an epoch (matches the graph adapter's epochs)
+const demoRoundsPerEpoch = 3
+
+// heartbeatTimeout bounds how long a leader candidate may take to answer a liveness probe
+const heartbeatTimeout = 2 * time.Second
+
+// roundEpoch returns the epoch a demo input belongs to
+func roundEpoch(inputNumber int) int {
+	return (inputNumber-1)/demoRoundsPerEpoch + 1
+}
+
+// electLeader returns the ID of the validator that leads the round, rotating leadership per epoch.
+//
+// Every candidate in LeaderSchedule order is probed over the transport, wherever it runs; a
+// candidate that does not answer a heartbeat, or refuses the handover, is marked failed for
+// the epoch and the next one takes over. Returns "" when no candidate is available.
+func (dc *DemoCoordinator) electLeader(inputNumber int) string {
+	epoch := roundEpoch(inputNumber)
+
+	for _, candidate := range dc.Leaders.Candidates(epoch) {
+		if dc.Leaders.IsFailed(epoch, candidate) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), heartbeatTimeout)
+		probe := dc.gateway.NewHeartbeatMessage(candidate, epoch) // Signed: validators verify probes
+		reply, err := subnet.SendHeartbeat(ctx, dc.Transport, candidate, probe)
+		cancel()
+		if err == nil {
+			err = dc.checkHeartbeat(reply, candidate)
+		}
+		if err == nil && candidate != dc.leaderID {
+			err = dc.handOverLeadership(candidate, epoch)
+		}
+		if err != nil {
+			fmt.Printf("💔 Leader candidate %s not responding for epoch %d: %v - failing over\n", candidate, epoch, err)
+			dc.Leaders.MarkFailed(epoch, candidate)
+			continue
+		}
+		return candidate
+	}
+	return ""
+}
+
+// checkHeartbeat authenticates a heartbeat reply from validatorID
+func (dc *DemoCoordinator) checkHeartbeat(reply *subnet.HeartbeatMessage, validatorID string) error {
+	if reply.Sender != validatorID {
+		return fmt.Errorf("%w: heartbeat for %s came from %s", subnet.ErrUnexpectedMessage, validatorID, reply.Sender)
+	}
+	return dc.Signers.Verify(reply)
+}
+
+// handOverLeadership moves the user-interface role and round clock to next. Local validators
+// switch roles directly; a remote validator is told with a signed LeaderHandoverMessage, and a
+// remote previous leader is told to step down the same way. Payment duties follow a local leader.
+func (dc *DemoCoordinator) handOverLeadership(next string, epoch int) error {
+	fmt.Printf("🔄 Epoch %d leader: %s → %s\n", epoch, dc.leaderID, next)
+
+	if validator := dc.localValidator(next); validator != nil {
+		validator.TakeLeadership(dc.roundClock)
+		if dc.PaymentCoord != nil {
+			validator.SetPaymentCoordinator(dc.PaymentCoord)
+		}
+	} else if err := dc.sendLeaderHandover(next, next, epoch); err != nil {
+		return err
+	}
+
+	if previous := dc.localValidator(dc.leaderID); previous != nil {
+		previous.SetRole(subnet.ConsensusValidator)
+	} else if dc.leaderID != "" {
+		if err := dc.sendLeaderHandover(dc.leaderID, next, epoch); err != nil {
+			fmt.Printf("⚠️  Could not tell %s to step down: %v\n", dc.leaderID, err)
+		}
+	}
+
+	dc.leaderID = next
+	dc.leader = dc.localValidator(next)
+	return nil
+}
+
+// sendLeaderHandover tells a remote validator that leader leads the epoch
+func (dc *DemoCoordinator) sendLeaderHandover(validatorID, leader string, epoch int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), heartbeatTimeout)
+	defer cancel()
+
+	msg := dc.gateway.NewLeaderHandoverMessage(validatorID, leader, epoch, dc.roundClock)
+	reply, err := subnet.SendLeaderHandover(ctx, dc.Transport, validatorID, msg)
+	if err != nil {
+		return err
+	}
+	return dc.checkHeartbeat(reply, validatorID)
+}
+
+// localValidator returns the validator with the given ID if it is hosted in this process
+func (dc *DemoCoordinator) localValidator(validatorID string) *subnet.CoreValidator {
+	for _, validator := range dc.Validators {
+		if validator.ID == validatorID {
+			return validator
+		}
+	}
+	return nil
+}
+
+// processInput elects the round's leader and has it run the round: a local leader runs it
+// here, a remote one is asked to over the transport
 func (dc *DemoCoordinator) processInput(inputNumber int, input string) {
+	fmt.Printf("User Intent: %s\n", input)
+
+	leaderID := dc.electLeader(inputNumber) // The epoch's leader orchestrates the round
+	if leaderID == "" {
+		fmt.Printf("❌ No validator available to lead round %d\n", inputNumber)
+		fmt.Printf("→ Round %d: DROPPED (no leader)\n", inputNumber)
+		return
+	}
+	if dc.leader == nil {
+		dc.delegateRound(leaderID, inputNumber, input)
+		return
+	}
+	dc.leadRound(inputNumber, input)
+}
+
+// delegatedRoundTimeout bounds how long a remote leader may take to run a round
+const delegatedRoundTimeout = 5 * time.Minute
+
+// delegateRound asks the remote epoch leader to run a round and records the signed result.
+// A leader that cannot be reached is failed over for the rest of the epoch; its round is dropped
+// rather than rerun, since the miner may already have served it.
+func (dc *DemoCoordinator) delegateRound(leaderID string, inputNumber int, input string) {
+	epoch := roundEpoch(inputNumber)
+	fmt.Printf("Round %d: Delegated to epoch %d leader %s\n", inputNumber, epoch, leaderID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), delegatedRoundTimeout)
+	defer cancel()
+	request := dc.gateway.NewRoundRequestMessage(leaderID, epoch, inputNumber, input)
+	result, err := subnet.RequestRound(ctx, dc.Transport, leaderID, request)
+	if err == nil && result.Sender != leaderID {
+		err = fmt.Errorf("%w: round result came from %s", subnet.ErrUnexpectedMessage, result.Sender)
+	}
+	if err == nil {
+		err = dc.Signers.Verify(result)
+	}
+	if err != nil {
+		fmt.Printf("💔 Leader %s failed round %d: %v - failing over\n", leaderID, inputNumber, err)
+		dc.Leaders.MarkFailed(epoch, leaderID)
+		fmt.Printf("→ Round %d: DROPPED (leader unavailable)\n", inputNumber)
+		return
+	}
+
+	// Hand the round-end clock to whoever leads next
+	dc.roundClock = result.VLCClock
+	if result.Round == nil {
+		fmt.Printf("→ Round %d: DROPPED (at leader %s)\n", inputNumber, leaderID)
+		return
+	}
+
+	// Only certificates that verify here go into the epoch
+	round := *result.Round
+	if round.Certificate != nil {
+		if err := round.Certificate.Verify(dc.CertificateSigners, dc.Consensus.Quorum); err != nil {
+			fmt.Printf("⚠️  Quorum certificate from %s not accepted: %v\n", leaderID, err)
+			round.Certificate = nil
+		}
+	}
+	dc.GraphAdapter.ImportRound(round, inputNumber, result.VLCClock)
+	fmt.Printf("→ Round %d: %s (led by %s)\n", inputNumber, round.FinalResult, leaderID)
+}
+
+// LeaderHandler serves this coordinator's validator on the transport, running the rounds a
+// remote gateway delegates to it while it leads an epoch (see subnet.NewLeaderHandler)
+func (dc *DemoCoordinator) LeaderHandler() subnet.MessageHandler {
+	return subnet.NewLeaderHandler(dc.gateway, dc.runDelegatedRound)
+}
+
+// runDelegatedRound runs a round for a remote gateway and returns the round's record
+func (dc *DemoCoordinator) runDelegatedRound(ctx context.Context, request *subnet.RoundRequestMessage) *subnet.RoundData {
+	var completed *subnet.RoundData
+	dc.GraphAdapter.SetRoundCompletedCallback(func(round subnet.RoundData) { completed = &round })
+	defer dc.GraphAdapter.SetRoundCompletedCallback(nil)
+
+	fmt.Printf("--- Leading input %d of epoch %d for %s ---\n", request.InputNumber, request.Epoch, request.Sender)
+	dc.leaderID, dc.leader = dc.gateway.ID, dc.gateway
+	dc.leadRound(request.InputNumber, request.Input)
+	return completed
+}
+
+// leadRound handles a single user input through the complete round-based workflow with VLC,
+// orchestrated by the local leader
+func (dc *DemoCoordinator) leadRound(inputNumber int, input string) {
 	// Use timestamp to ensure unique request IDs across runs
 	requestID := fmt.Sprintf("req-%s-%d-%d", dc.SubnetID, inputNumber, time.Now().Unix())
 
-	fmt.Printf("User Intent: %s\n", input)
-
 	// *** ROUND START: User input (no VLC increment - user is external) ***
-	uiValidator := dc.Validators[0] // Validator-1 is the round orchestrator
+	uiValidator := dc.leader
+	// Hand the round-end clock to whoever leads next
+	defer func() { dc.roundClock = uiValidator.GetLastMinerClock() }()
+
 	// NO VLC increment for user communication - user is external to subnet
-	fmt.Printf("Round %d: Started by Validator-1 receiving user input\n", inputNumber)
+	fmt.Printf("Round %d: Started by %s receiving user input\n", inputNumber, uiValidator.ID)
+
+	// Drop any miner responses whose causal gap was never filled
+	uiValidator.ExpireCausalGaps()
+
+	// Assign the task to a miner; it serves every turn of the task and is credited with the result
+	minerID, err := dc.routeTask(requestID, inputNumber)
+	if err != nil {
+		fmt.Printf("❌ No miner for round %d: %v\n", inputNumber, err)
+		fmt.Printf("→ Round %d: DROPPED (no miner)\n", inputNumber)
+		return
+	}
+	defer dc.Router.Release(requestID) // Dropped rounds free the miner without a result
+	fmt.Printf("🧭 Task routed to %s (%s)\n", minerID, dc.Router.Strategy)
 
 	// Track user input that starts the round
 	userInputEventID := dc.GraphAdapter.TrackUserInput(requestID, input, uiValidator.GetLastMinerClock(), "")
 
 	// *** x402 PAYMENT HANDLING ***
 	var paymentRequest *subnet.PaymentRequest
-	totalTasks := 7 // Total tasks in demo
+	totalTasks := len(dc.userInputs) // Total tasks in demo
 
 	if dc.PaymentCoord != nil {
-		// Get addresses from environment
-		agentAddrStr := os.Getenv("MINER_ADDRESS")
-		if agentAddrStr == "" {
-			agentAddrStr = "0x86cDAb16A19602F74E4fFB996baD70307105a3A3"
-		}
+		// The routed miner's agent is paid for this task
+		agentAddrStr := dc.minerAddresses[minerID]
 		clientAddrStr := os.Getenv("CLIENT_ADDRESS")
 		if clientAddrStr == "" {
 			clientAddrStr = "0xfA6EC9Cf1E293A91a8ea2EdCc4A2324d48129821"
@@ -456,110 +641,244 @@ func (dc *DemoCoordinator) processInput(inputNumber int, input string) {
 	// Step 1: Validator sends request to miner
 	// VLC Protocol: +1 for message leaving validator to miner
 	uiValidator.IncrementValidatorClock()
-	fmt.Printf("Validator-1: Message leaving to miner → VLC incremented\n")
-
-	// Sync miner's clock with validator's current state
-	dc.Miner.UpdateValidatorClock(uiValidator.GetLastMinerClock())
+	fmt.Printf("%s: Message leaving to miner → VLC incremented\n", uiValidator.ID)
+
+	// The message carries validator's current clock; miner merges it and processes
+	// the input (will increment twice: enter + leave)
+	inputMsg := uiValidator.NewUserInputMessage(requestID, minerID, input, inputNumber)
+	if dc.StreamOutput {
+		// Chunks arrive at the leader while the miner works; they are not VLC events
+		inputMsg = uiValidator.NewStreamingUserInputMessage(requestID, minerID, input, inputNumber)
+	}
+	minerResponse, err := dc.sendUserInput(minerID, inputMsg)
+	if err != nil {
+		fmt.Printf("❌ Miner %s unavailable: %v\n", minerID, err)
+		fmt.Printf("→ Round %d: DROPPED (no miner response)\n", inputNumber)
+		return
+	}
+	if minerResponse.Sender != minerID {
+		fmt.Printf("❌ Response for %s came from %s, not the assigned %s\n", requestID, minerResponse.Sender, minerID)
+		fmt.Printf("→ Round %d: DROPPED (wrong miner)\n", inputNumber)
+		return
+	}
+	// Authenticated before anything reads it or merges its clock; later turns are checked
+	// by checkTurnResponse
+	if err := uiValidator.VerifyMinerResponse(minerResponse); err != nil {
+		fmt.Printf("→ Round %d: DROPPED (unauthenticated miner response)\n", inputNumber)
+		return
+	}
 
-	// Miner processes input (will increment twice: enter + leave)
-	minerResponse := dc.Miner.ProcessInput(input, inputNumber, requestID)
+	// The final output must be exactly what was streamed to the user
+	if err := uiValidator.VerifyStreamedOutput(minerResponse); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Printf("→ Round %d: DROPPED (streamed output mismatch)\n", inputNumber)
+		return
+	}
 
 	// Step 2: Validator receives response from miner
 	// VLC Protocol: +1 for message entering validator from miner
 	uiValidator.IncrementValidatorClock()
-	fmt.Printf("Validator-1: Message entered from miner → VLC incremented\n")
+	fmt.Printf("%s: Message entered from miner → VLC incremented\n", uiValidator.ID)
 
 	// Track miner's response (output or info request)
 	minerResponseEventID := dc.GraphAdapter.TrackMinerResponse(requestID, minerResponse, userInputEventID)
 
 	if minerResponse.OutputType == subnet.NeedMoreInfo {
 		// Handle info request scenario
-		dc.handleInfoRequest(inputNumber, input, minerResponse, minerResponseEventID)
+		dc.handleInfoRequest(inputNumber, minerID, input, minerResponse, minerResponseEventID)
 	} else {
 		// Handle normal output scenario
-		dc.handleNormalOutput(inputNumber, minerResponse, minerResponseEventID)
+		dc.handleNormalOutput(inputNumber, minerID, minerResponse, minerResponseEventID)
 	}
 }
 
-// handleInfoRequest processes the scenario where miner needs more information with VLC orchestration
-func (dc *DemoCoordinator) handleInfoRequest(inputNumber int, originalInput string, minerResponse *subnet.MinerResponseMessage, parentEventID string) {
-	fmt.Printf("Miner requests more info: %s\n", minerResponse.InfoRequest)
+// sendUserInput sends a task to its miner, retrying once after a transport error. The retry
+// reuses the request ID, so a miner that already processed the task answers from its cache
+// without advancing its clock. Streamed tasks are not retried: their chunks are already in
+// the leader's assembler.
+func (dc *DemoCoordinator) sendUserInput(minerID string, msg *subnet.UserInputMessage) (*subnet.MinerResponseMessage, error) {
+	send := func() (*subnet.MinerResponseMessage, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), dc.MinerTimeout)
+		defer cancel()
+		return subnet.SendUserInput(ctx, dc.Transport, minerID, msg)
+	}
+	response, err := send()
+	if err != nil && !msg.Stream {
+		fmt.Printf("⚠️  Miner %s unreachable (%v) - retrying %s\n", minerID, err, msg.RequestID)
+		response, err = send()
+	}
+	return response, err
+}
 
-	// Step 1: Validate miner's VLC sequence (NeedMoreInfo message)
-	dc.validateVLCSequenceFromMiner(minerResponse)
+// minerExchangeSlack is the time a request to a miner may take beyond its task deadline
+// (TASK_TIMEOUT), for transport and signing
+const minerExchangeSlack = 15 * time.Second
 
-	// Step 2: UI Validator orchestrates info request
-	uiValidator := dc.Validators[0]
+// handleInfoRequest processes the scenario where miner needs more information with VLC orchestration.
+// The miner may ask several follow-up questions; every turn is a full VLC exchange and is
+// tracked in the graph, and the whole conversation is sent with each turn to the task's miner.
+func (dc *DemoCoordinator) handleInfoRequest(inputNumber int, minerID string, originalInput string, minerResponse *subnet.MinerResponseMessage, parentEventID string) {
+	uiValidator := dc.leader
+	var history []subnet.DialogTurn
 
-	// Update UI validator's VLC with miner's latest state
-	uiValidator.UpdateMinerClock(minerResponse.VLCClock)
+	for minerResponse.OutputType == subnet.NeedMoreInfo {
+		turn := len(history) + 1
+		fmt.Printf("Miner requests more info (turn %d): %s\n", turn, minerResponse.InfoRequest)
+
+		// Step 1: Validate miner's VLC sequence (NeedMoreInfo message, already authenticated)
+		dc.validateVLCSequenceFromMiner(minerResponse)
+
+		// Update UI validator's VLC with miner's latest state
+		uiValidator.UpdateMinerClock(minerResponse.VLCClock)
+
+		if turn > dc.maxDialogTurns() {
+			fmt.Printf("→ Round %d: DROPPED (miner exceeded %d clarification turns)\n", inputNumber, dc.maxDialogTurns())
+			return
+		}
 
-	infoRequest := uiValidator.RequestMoreInfo(minerResponse.RequestID, minerResponse.InfoRequest)
+		// Step 2: UI Validator orchestrates info request
+		infoRequest := uiValidator.RequestMoreInfo(minerResponse.RequestID, minerResponse.InfoRequest)
+		if infoRequest == nil {
+			return
+		}
 
-	if infoRequest != nil {
 		// Validator to User: NO VLC increment (user is external)
 		fmt.Printf("Validator %s asks user: %s\n", uiValidator.ID, infoRequest.Question)
 
-		// Step 3: Simulate user providing additional info based on demo scenario
-		var additionalInfo string
-		switch inputNumber {
-		case 3:
-			additionalInfo = "Focus on cost optimization and ROI analysis specifically."
-		case 6:
-			additionalInfo = "Use REST API with JSON payloads, authentication via OAuth 2.0."
+		// Step 3: The user answers (simulated by the demo handler, awaited over USER_HTTP_ADDR)
+		additionalInfo, err := uiValidator.AskUser(context.Background(), &subnet.UserPrompt{
+			RequestID:   minerResponse.RequestID,
+			InputNumber: inputNumber,
+			Task:        minerResponse.Task,
+			Question:    infoRequest.Question,
+			Turn:        turn,
+		})
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			fmt.Printf("→ Round %d: DROPPED (no user clarification)\n", inputNumber)
+			return
 		}
 
 		// User to Validator: NO VLC increment (user is external)
 		fmt.Printf("User provides: %s\n", additionalInfo)
+		history = append(history, subnet.DialogTurn{Question: minerResponse.InfoRequest, Answer: additionalInfo})
 
 		// Track validator state for processing additional info (no increment yet)
 		infoResponseEventID := dc.GraphAdapter.TrackInfoResponse(minerResponse.RequestID, additionalInfo, uiValidator.GetLastMinerClock(), parentEventID)
 
-		// Step 4: Validator sends additional info to miner
+		// Step 4: Validator sends the conversation so far to miner
 		// VLC Protocol: +1 for message leaving validator to miner
 		uiValidator.IncrementValidatorClock()
-		fmt.Printf("Validator-1: Additional info leaving to miner → VLC incremented\n")
-
-		// Sync miner with validator's updated VLC state
-		dc.Miner.UpdateValidatorClock(uiValidator.GetLastMinerClock())
-
-		// Miner processes additional info (will increment twice: enter + leave)
-		finalResponse := dc.Miner.ProcessAdditionalInfo(originalInput, additionalInfo, inputNumber, minerResponse.RequestID)
+		fmt.Printf("%s: Additional info leaving to miner → VLC incremented\n", uiValidator.ID)
+
+		// Miner merges validator's updated VLC state and processes the turn
+		// (will increment twice: enter + leave)
+		infoMsg := uiValidator.NewDialogTurnMessage(minerResponse.RequestID, minerID, originalInput, history, inputNumber)
+		ctx, cancel := context.WithTimeout(context.Background(), dc.MinerTimeout)
+		nextResponse, err := subnet.SendAdditionalInfo(ctx, dc.Transport, minerID, infoMsg)
+		cancel()
+		if err != nil {
+			fmt.Printf("❌ Miner %s unavailable: %v\n", minerID, err)
+			fmt.Printf("→ Round %d: DROPPED (no miner response)\n", inputNumber)
+			return
+		}
+		// Every turn must be the assigned miner's signed answer to this request
+		if err := checkTurnResponse(uiValidator, minerID, minerResponse.RequestID, len(history), nextResponse); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			fmt.Printf("→ Round %d: DROPPED (unauthenticated miner response)\n", inputNumber)
+			return
+		}
 
-		// Step 5: Validator receives final response from miner
+		// Step 5: Validator receives the next response from miner (output or another question)
 		// VLC Protocol: +1 for message entering validator from miner
 		uiValidator.IncrementValidatorClock()
-		fmt.Printf("Validator-1: Final response entered from miner → VLC incremented\n")
+		fmt.Printf("%s: Response to turn %d entered from miner → VLC incremented\n", uiValidator.ID, turn)
+
+		// Track miner VLC increment for this turn
+		parentEventID = dc.GraphAdapter.TrackMinerResponse(minerResponse.RequestID, nextResponse, infoResponseEventID)
+		minerResponse = nextResponse
+	}
+
+	// Step 6: Handle final output with quality voting
+	dc.handleNormalOutput(inputNumber, minerID, minerResponse, parentEventID)
+}
+
+// checkTurnResponse rejects a reply to a clarification turn unless the assigned miner sent
+// it for this request and turn and signed it
+func checkTurnResponse(v *subnet.CoreValidator, minerID, requestID string, turn int, response *subnet.MinerResponseMessage) error {
+	if response.Sender != minerID {
+		return fmt.Errorf("response for %s came from %s, not the assigned %s", requestID, response.Sender, minerID)
+	}
+	if response.RequestID != requestID || response.Turn != turn {
+		return fmt.Errorf("miner %s answered %s turn %d, expected %s turn %d", minerID, response.RequestID, response.Turn, requestID, turn)
+	}
+	return v.VerifyMinerResponse(response)
+}
+
+// maxDialogTurns returns the clarification turn limit, defaulting to subnet.DefaultMaxDialogTurns
+func (dc *DemoCoordinator) maxDialogTurns() int {
+	if dc.MaxDialogTurns <= 0 {
+		return subnet.DefaultMaxDialogTurns
+	}
+	return dc.MaxDialogTurns
+}
+
+// registerParticipants tells every local validator which VLC entry each miner and validator advances
+func (dc *DemoCoordinator) registerParticipants() {
+	for _, validator := range dc.Validators {
+		RegisterDemoParticipants(validator, dc.MinerIDs, dc.ValidatorIDs)
+	}
+}
 
-		// Track miner VLC increment for final processing
-		finalProcessEventID := dc.GraphAdapter.TrackMinerResponse(minerResponse.RequestID, finalResponse, infoResponseEventID)
+// routeTask assigns a task to a miner. A payment session escrows for a single agent, so in
+// session mode every task of an epoch goes to the miner that opened the epoch's session.
+func (dc *DemoCoordinator) routeTask(requestID string, inputNumber int) (string, error) {
+	if dc.PaymentCoord == nil || !dc.PaymentCoord.IsSessionMode() ||
+		dc.PaymentCoord.IsStandaloneTask(inputNumber, len(dc.userInputs)) {
+		return dc.Router.Route(requestID)
+	}
 
-		// Step 5: Handle final output with quality voting
-		dc.handleNormalOutput(inputNumber, finalResponse, finalProcessEventID)
+	epoch := dc.PaymentCoord.GetEpochForTask(inputNumber)
+	if minerID, ok := dc.sessionMiners[epoch]; ok {
+		return minerID, dc.Router.Assign(requestID, minerID)
 	}
+	minerID, err := dc.Router.Route(requestID)
+	if err == nil {
+		dc.sessionMiners[epoch] = minerID
+	}
+	return minerID, err
 }
 
-// validateVLCSequenceFromMiner validates miner's VLC sequence across all validators
+// validateVLCSequenceFromMiner has every local validator check the miner's clock against its
+// own view of the miner, then gossips each accepted response to the other validators as a
+// clock checkpoint; they check it themselves and compare it with what they saw, so a miner
+// showing different histories to different validators is caught. Each validator
+// authenticates the response itself before it touches its clocks.
 func (dc *DemoCoordinator) validateVLCSequenceFromMiner(minerResponse *subnet.MinerResponseMessage) {
-	// Each validator independently validates miner's VLC sequence
 	allValid := true
 	validCount := 0
-	for i, validator := range dc.Validators {
-		if i == 0 {
-			// Validator-1 (UI) - full VLC participant
-			if validator.ValidateSequence(minerResponse.VLCClock, 1) {
-				validCount++
-			} else {
-				allValid = false
-			}
-		} else {
-			// Other validators - just check VLC format validity
-			if minerResponse.VLCClock != nil && len(minerResponse.VLCClock.Values) > 0 {
-				validCount++
-			} else {
+	for _, validator := range dc.Validators {
+		if err := validator.VerifyMinerResponse(minerResponse); err != nil {
+			allValid = false
+			continue
+		}
+		if validator == dc.leader {
+			// Round leader (UI) also delivers the response into its orchestration clock
+			delivered, err := validator.ReceiveMinerResponse(minerResponse)
+			if err != nil || len(delivered) == 0 {
 				allValid = false
+				continue
 			}
 		}
+		checkpoint, err := validator.ObserveMinerResponse(minerResponse)
+		if err != nil {
+			allValid = false
+			continue
+		}
+		validCount++
+		if checkpoint != nil {
+			dc.gossipClockCheckpoint(validator.ID, checkpoint)
+		}
 	}
 
 	if allValid {
@@ -569,43 +888,73 @@ func (dc *DemoCoordinator) validateVLCSequenceFromMiner(minerResponse *subnet.Mi
 	}
 }
 
+// gossipClockCheckpoint sends a validator's checkpoint to every other validator on the transport
+func (dc *DemoCoordinator) gossipClockCheckpoint(fromID string, checkpoint *subnet.ClockCheckpointMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), dc.VoteDeadline)
+	defer cancel()
+
+	for _, peerID := range dc.ValidatorIDs {
+		if peerID == fromID {
+			continue
+		}
+		if err := subnet.SendClockCheckpoint(ctx, dc.Transport, peerID, checkpoint); err != nil {
+			fmt.Printf("⚠️  Clock checkpoint %s → %s not delivered: %v\n", fromID, peerID, err)
+		}
+	}
+}
+
 // validateVLCSequenceFromValidator validates validator-1's VLC operations
 func (dc *DemoCoordinator) validateVLCSequenceFromValidator(validatorClock *vlc.Clock) {
-	dc.Miner.UpdateValidatorClock(validatorClock)
+	for _, miner := range dc.Miners {
+		miner.UpdateValidatorClock(validatorClock)
+	}
 }
 
-// handleNormalOutput processes normal miner output through VLC validation and quality consensus
-func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *subnet.MinerResponseMessage, parentEventID string) {
-	fmt.Printf("Miner output: %s\n", minerResponse.Output)
+// handleNormalOutput processes normal miner output through VLC validation and quality consensus.
+// The round's result (reputation, payment) is credited to minerID, the miner that served it.
+// The response has been authenticated by processInput or checkTurnResponse.
+func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerID string, minerResponse *subnet.MinerResponseMessage, parentEventID string) {
+	if minerResponse.OutputType == subnet.TaskFailed {
+		fmt.Printf("Miner failed: %s\n", minerResponse.Error)
+	} else {
+		fmt.Printf("Miner output: %s\n", minerResponse.Output)
+	}
 
-	// Step 1: Validate miner's VLC sequence for OutputReady message
+	// Step 1: Validate miner's VLC sequence for OutputReady / TaskFailed message
 	dc.validateVLCSequenceFromMiner(minerResponse)
 
 	// Step 2: UI Validator updates its VLC state with miner's latest
-	uiValidator := dc.Validators[0]
+	uiValidator := dc.leader
 	uiValidator.UpdateMinerClock(minerResponse.VLCClock)
 
 	// Step 3: Create shared quality assessment for consensus voting
-	sharedAssessment := &subnet.QualityAssessment{
-		RequestID: minerResponse.RequestID,
-	}
+	// Votes weigh the validators' current (post-penalty) weight against a quorum of the full
+	// registered weight, as the round's quorum certificate is verified; the weight snapshot
+	// is signed into the certificate
+	weights := dc.Accountability.Weights()
+	sharedAssessment := subnet.NewQualityAssessment(minerResponse.RequestID, dc.Consensus)
 
 	// Step 4: All validators vote on output quality (distributed consensus)
 	fmt.Printf("🧠 Validators performing Semantic Alignment & quality assessment voting (distributed consensus)...\n")
-	votes := make([]*subnet.ValidatorVoteMessage, 0, len(dc.Validators))
+	// Votes are gathered concurrently; a slow or crashed validator only costs its own vote
+	collector := subnet.NewVoteCollector(dc.VoteDeadline, dc.directVoteRequester(minerResponse))
+	if dc.CommitReveal {
+		collector.Request = dc.commitRevealRequester(minerResponse)
+	}
+	collector.Admit = dc.voteAdmitter(weights)
 
-	// Each validator performs quality assessment and voting
-	for _, validator := range dc.Validators {
-		// Note: VLC validation already done above - this is pure quality voting
-		vote := validator.VoteOnOutput(minerResponse)
-		if vote != nil {
-			votes = append(votes, vote)
-			// Add each validator's vote to the shared assessment
-			sharedAssessment.AddVote(vote.Weight, vote.Accept)
-		} else {
-			fmt.Printf("ERROR: Validator %s failed to generate vote\n", validator.ID)
-		}
+	collection := collector.Collect(context.Background(), sharedAssessment, dc.ValidatorIDs)
+	for validatorID, err := range collection.Failed {
+		fmt.Printf("🚫 No vote from %s: %v\n", validatorID, err)
+	}
+	if collection.TimedOut {
+		fmt.Printf("⏰ Vote deadline (%v) passed; %d validator(s) did not answer: %v\n",
+			dc.VoteDeadline, len(collection.Missing), collection.Missing)
+	} else if len(collection.Missing) > 0 {
+		fmt.Printf("⚡ Consensus finalized early after %v; %d vote(s) not needed\n",
+			collection.Elapsed.Round(time.Millisecond), len(collection.Missing))
 	}
+	votes := collection.Votes
 
 	// Step 5: Check consensus using the shared assessment
 	var consensusResult string
@@ -613,58 +962,121 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 	var userFeedback string
 	var finalResult string
 
-	// Count accepts/rejects from votes
-	acceptCount := 0
+	// Count accepts/rejects/abstentions from votes
+	acceptCount, rejectCount, abstainCount := 0, 0, 0
 	for _, v := range votes {
-		if v.Accept {
+		switch {
+		case v.Abstain:
+			abstainCount++
+		case v.Accept:
 			acceptCount++
+		default:
+			rejectCount++
 		}
 	}
 
-	if sharedAssessment.IsAccepted() {
+	switch sharedAssessment.Outcome() {
+	case subnet.OutcomeAccepted:
 		consensusResult = fmt.Sprintf("ACCEPTED (%d/%d validators)", acceptCount, len(votes))
 		fmt.Printf("✓ Consensus: %s\n", consensusResult)
 
-		// Step 6: Simulate user feedback using UI validator
-		userAccepts, userFeedback = uiValidator.SimulateUserInteraction(inputNumber, minerResponse.Output)
-		fmt.Printf("✓ User: %s\n", userFeedback)
-
-		if userAccepts {
+		// Step 6: The user reviews the output (simulated by the demo handler, awaited over USER_HTTP_ADDR)
+		var err error
+		userAccepts, userFeedback, err = uiValidator.ReviewWithUser(context.Background(), &subnet.UserPrompt{
+			RequestID:   minerResponse.RequestID,
+			InputNumber: inputNumber,
+			Task:        minerResponse.Task,
+			Output:      minerResponse.Output,
+		})
+
+		switch {
+		case err != nil:
+			userAccepts, userFeedback = false, fmt.Sprintf("No user feedback (%v)", err)
+			fmt.Printf("✗ User: %s\n", userFeedback)
+			finalResult = "NO USER RESPONSE"
+		case userAccepts:
+			fmt.Printf("✓ User: %s\n", userFeedback)
 			finalResult = "DELIVERED"
-		} else {
+		default:
+			fmt.Printf("✓ User: %s\n", userFeedback)
 			finalResult = "USER REJECTED"
 		}
-	} else {
-		consensusResult = fmt.Sprintf("REJECTED (%d/%d validators)", acceptCount, len(votes))
+	case subnet.OutcomeRejected:
+		consensusResult = fmt.Sprintf("REJECTED (%d/%d validators)", rejectCount, len(votes))
 		fmt.Printf("✗ Consensus: %s\n", consensusResult)
 
 		userAccepts = false
 		userFeedback = "No user feedback (validator rejection)"
 		finalResult = "VALIDATOR REJECTED"
+	default:
+		consensusResult = fmt.Sprintf("QUORUM NOT REACHED (%d accept, %d reject, %d abstain; %.0f%% required)",
+			acceptCount, rejectCount, abstainCount, dc.Consensus.Quorum*100)
+		fmt.Printf("✗ Consensus: %s\n", consensusResult)
+
+		userAccepts = false
+		userFeedback = "No user feedback (no validator quorum)"
+		finalResult = "NO QUORUM"
 	}
 
+	// Consensus quality: weighted median of validator scores (robust to outliers)
+	quality := sharedAssessment.Aggregate()
+	qualityScore := quality.WeightedMedian
+	fmt.Printf("📏 Quality: median %.2f, trimmed mean %.2f, σ %.2f, disagreement %.2f (%d scores)\n",
+		quality.WeightedMedian, quality.TrimmedMean, quality.StdDev, quality.Disagreement, quality.Count)
+
+	// Score every validator against the decided outcome (may adjust weights)
+	dc.Accountability.RecordOutcome(minerResponse.RequestID, sharedAssessment.Outcome(), qualityScore)
+
+	// Certify decided rounds so the epoch submission proves the validators agreed
+	dc.certifyRound(minerResponse, sharedAssessment.Outcome(), votes, weights)
+
+	// Publish each validator's judgement (and any judge rationale) with the round
+	dc.GraphAdapter.RecordVoteAssessments(minerResponse.RequestID, votes)
+
 	// *** ROUND END ***
 	fmt.Printf("→ Round %d: %s\n", inputNumber, finalResult)
 
-	// *** REPUTATION: Generate FeedbackAuth BEFORE epoch submission ***
+	// *** REPUTATION: Record task result BEFORE epoch submission ***
 	// This ensures feedback is included in the epoch data
+	taskSuccess := sharedAssessment.IsAccepted() && userAccepts
 	if dc.ReputationMgr != nil {
-		taskSuccess := sharedAssessment.IsAccepted() && userAccepts
-		_, err := dc.ReputationMgr.GenerateFeedbackAuth(
+		dc.ReputationMgr.RecordMinerTaskResult(
 			minerResponse.RequestID,
 			inputNumber,
+			dc.minerAgentIDs[minerID],
 			taskSuccess,
+			qualityScore,
 		)
-		if err != nil {
-			fmt.Printf("⚠️  Failed to generate FeedbackAuth: %v\n", err)
-		}
 	}
 
+	// Feed the result back into routing (reputation strategy)
+	dc.Router.Complete(minerResponse.RequestID, taskSuccess, qualityScore)
+
 	// *** PAYMENT FINALIZATION: Process payment AFTER round completes ***
 	if dc.PaymentCoord != nil {
-		qualityScore := sharedAssessment.AcceptVotes / sharedAssessment.TotalWeight
-		totalTasks := 7
-		taskApproved := sharedAssessment.IsAccepted() && userAccepts
+		totalTasks := len(dc.userInputs)
+		taskApproved := taskSuccess
+
+		// Failed tasks and undecided rounds (no quorum before the vote deadline) are refunded
+		// outright; in session mode they count as unapproved and are refunded at settlement
+		finalizeTaskPayment := func() {
+			var err error
+			if minerResponse.OutputType == subnet.TaskFailed {
+				err = uiValidator.RefundFailedTask(minerResponse.RequestID, minerResponse.Error)
+			} else if collection.Undecided() {
+				err = uiValidator.RefundUndecidedPayment(minerResponse.RequestID)
+			} else {
+				err = uiValidator.FinalizePayment(
+					minerResponse.RequestID,
+					sharedAssessment.IsAccepted(),
+					userAccepts,
+					qualityScore,
+				)
+			}
+			if err != nil {
+				fmt.Printf("⚠️  Payment finalization error: %v\n", err)
+			}
+		}
 
 		if dc.PaymentCoord.IsSessionMode() {
 			epochNumber := dc.PaymentCoord.GetEpochForTask(inputNumber)
@@ -672,15 +1084,7 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 
 			if isStandalone {
 				// Standalone task (task 7): Per-task finalization
-				err := uiValidator.FinalizePayment(
-					minerResponse.RequestID,
-					sharedAssessment.IsAccepted(),
-					userAccepts,
-					qualityScore,
-				)
-				if err != nil {
-					fmt.Printf("⚠️  Payment finalization error: %v\n", err)
-				}
+				finalizeTaskPayment()
 			} else {
 				// Session task: Update session metrics, finalize at end of epoch
 				dc.PaymentCoord.UpdateSessionTaskResult(epochNumber, taskApproved, qualityScore)
@@ -693,20 +1097,23 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 			}
 		} else {
 			// Per-task mode: Finalize after round completes
-			err := uiValidator.FinalizePayment(
-				minerResponse.RequestID,
-				sharedAssessment.IsAccepted(),
-				userAccepts,
-				qualityScore,
-			)
-			if err != nil {
-				fmt.Printf("⚠️  Payment finalization error: %v\n", err)
-			}
+			finalizeTaskPayment()
 		}
 	}
 
 	// Sync miner with final validator state before round completion
-	dc.Miner.UpdateValidatorClock(uiValidator.GetLastMinerClock())
+	finalOutput := uiValidator.NewFinalOutputMessage(
+		minerResponse.RequestID,
+		minerID,
+		minerResponse.Output,
+		inputNumber,
+		sharedAssessment.IsAccepted(),
+		sharedAssessment.IsAccepted() && !userAccepts,
+		sharedAssessment.AcceptVotes,
+	)
+	if err := subnet.SendFinalOutput(context.Background(), dc.Transport, minerID, finalOutput); err != nil {
+		fmt.Printf("⚠️  Failed to deliver round result to miner: %v\n", err)
+	}
 
 	// Track comprehensive round completion with all actions in one VLC mutation
 	// NOTE: This may trigger epoch submission if this is the 3rd round
@@ -732,11 +1139,186 @@ func (dc *DemoCoordinator) handleNormalOutput(inputNumber int, minerResponse *su
 	}
 }
 
+// certifyRound bundles the validators' attestations and the weights that decided the round
+// into a quorum certificate signed by the round leader.
+// Undecided rounds have nothing to certify; certificates that would not verify against the
+// registered validator set are reported and left out of the epoch.
+func (dc *DemoCoordinator) certifyRound(minerResponse *subnet.MinerResponseMessage, outcome subnet.ConsensusOutcome, votes []*subnet.ValidatorVoteMessage, weights map[string]float64) {
+	signerWeights := make(map[common.Address]float64, len(dc.ValidatorIDs))
+	for _, id := range dc.ValidatorIDs {
+		if address, ok := dc.Signers.Address(id); ok {
+			signerWeights[address] = weights[id]
+		}
+	}
+
+	cert, err := subnet.NewTaskCertificate(minerResponse, outcome, votes, signerWeights)
+	if err != nil {
+		return
+	}
+	if err := dc.leader.SignCertificate(cert); err != nil {
+		fmt.Printf("⚠️  Quorum certificate not issued: %v\n", err)
+		return
+	}
+	if err := cert.Verify(dc.CertificateSigners, dc.Consensus.Quorum); err != nil {
+		fmt.Printf("⚠️  Quorum certificate not issued: %v\n", err)
+		return
+	}
+
+	dc.GraphAdapter.RecordTaskCertificate(minerResponse.RequestID, cert)
+	fmt.Printf("📜 Quorum certificate: %d validator attestations for %s\n", len(cert.Attestations), cert.Outcome)
+}
+
+// voteAdmitter authenticates votes and weighs them with the round's weight snapshot.
+// Votes are weighted by the coordinator's view of each validator, not the self-reported weight.
+func (dc *DemoCoordinator) voteAdmitter(weights map[string]float64) subnet.VoteAdmitter {
+	return func(vote *subnet.ValidatorVoteMessage) (float64, error) {
+		if err := dc.admitVote(vote); err != nil {
+			return 0, err
+		}
+		return weights[vote.ValidatorID], nil
+	}
+}
+
+// admitVote authenticates a vote and records it for equivocation checks
+func (dc *DemoCoordinator) admitVote(vote *subnet.ValidatorVoteMessage) error {
+	// Only authenticated votes count toward consensus, and only as the validator that signed them
+	if err := dc.Signers.Verify(vote); err != nil {
+		return err
+	}
+	if vote.Sender != vote.ValidatorID {
+		return fmt.Errorf("%w: %s sent a vote as %s", subnet.ErrVoterMismatch, vote.Sender, vote.ValidatorID)
+	}
+
+	// Conflicting votes for the same request are discarded and penalized
+	return dc.Accountability.ObserveVote(vote)
+}
+
+// directVoteRequester asks a validator for its vote in a single exchange
+func (dc *DemoCoordinator) directVoteRequester(minerResponse *subnet.MinerResponseMessage) subnet.VoteRequester {
+	return func(ctx context.Context, validatorID string) (*subnet.ValidatorVoteMessage, error) {
+		// Validators refuse to vote on miner output they cannot authenticate
+		// Note: VLC validation already done above - this is pure quality voting
+		return subnet.RequestValidatorVote(ctx, dc.Transport, validatorID, minerResponse)
+	}
+}
+
+// commitRevealRequester runs the commit phase and returns a requester for the reveal phase.
+// Every validator commits to a sealed vote before any vote is revealed, so no validator
+// can see and copy another's decision. Both phases are bounded by the vote deadline.
+// Reveals that do not match their commitment, or never arrive, are penalized.
+func (dc *DemoCoordinator) commitRevealRequester(minerResponse *subnet.MinerResponseMessage) subnet.VoteRequester {
+	uiValidator := dc.leader
+	requestID := minerResponse.RequestID
+
+	// Phase 1: Commit - validators assess and return only a hash of their vote
+	commits := dc.collectVoteCommitments(minerResponse)
+	fmt.Printf("🔒 %d/%d validators committed sealed votes\n", len(commits), len(dc.ValidatorIDs))
+
+	// Validators check these signed commitments before revealing, so none opens its vote
+	// while the commit phase is short of quorum
+	sealed := make([]*subnet.VoteCommitMessage, 0, len(commits))
+	for _, validatorID := range dc.ValidatorIDs {
+		if commit, committed := commits[validatorID]; committed {
+			sealed = append(sealed, commit)
+		}
+	}
+
+	// Validators refuse to reveal without a quorum of commitments; that is not their fault
+	if required := subnet.RevealQuorum(dc.Consensus.Quorum, len(dc.ValidatorIDs)); len(sealed) < required {
+		fmt.Printf("🔓 Reveal skipped: %d of %d required commitments\n", len(sealed), required)
+		return func(ctx context.Context, validatorID string) (*subnet.ValidatorVoteMessage, error) {
+			return nil, fmt.Errorf("%w: %d of %d commitments", subnet.ErrRevealTooEarly, len(sealed), required)
+		}
+	}
+
+	// Phase 2: Reveal - opened only after a quorum of commitments is in
+	return func(ctx context.Context, validatorID string) (*subnet.ValidatorVoteMessage, error) {
+		commit, committed := commits[validatorID]
+		if !committed {
+			return nil, fmt.Errorf("no commitment from %s", validatorID)
+		}
+
+		request := uiValidator.NewVoteRevealRequest(requestID, validatorID, sealed)
+		vote, err := subnet.RequestVoteReveal(ctx, dc.Transport, validatorID, request)
+		if err != nil {
+			// Cancellation means consensus finalized early; only a missed deadline is a fault
+			if !errors.Is(err, context.Canceled) {
+				dc.Accountability.Penalize(validatorID, requestID, "missing vote reveal")
+			}
+			return nil, err
+		}
+		if err := subnet.VerifyVoteReveal(commit, vote); err != nil {
+			dc.Accountability.Penalize(validatorID, requestID, "vote does not match commitment")
+			return nil, err
+		}
+		return vote, nil
+	}
+}
+
+// collectVoteCommitments requests sealed vote commitments from all validators in parallel,
+// returning the authenticated commitments received before the vote deadline
+func (dc *DemoCoordinator) collectVoteCommitments(minerResponse *subnet.MinerResponseMessage) map[string]*subnet.VoteCommitMessage {
+	uiValidator := dc.leader
+
+	deadline := dc.VoteDeadline
+	if deadline <= 0 {
+		deadline = subnet.DefaultVoteDeadline
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+
+	type commitResult struct {
+		validatorID string
+		commit      *subnet.VoteCommitMessage
+		err         error
+	}
+	results := make(chan commitResult, len(dc.ValidatorIDs))
+	for _, validatorID := range dc.ValidatorIDs {
+		go func() {
+			request := uiValidator.NewVoteCommitRequest(minerResponse, validatorID)
+			commit, err := subnet.RequestVoteCommit(ctx, dc.Transport, validatorID, request)
+			results <- commitResult{validatorID: validatorID, commit: commit, err: err}
+		}()
+	}
+
+	commits := make(map[string]*subnet.VoteCommitMessage)
+	for pending := len(dc.ValidatorIDs); pending > 0; pending-- {
+		select {
+		case result := <-results:
+			if result.err != nil {
+				fmt.Printf("ERROR: Validator %s failed to commit vote: %v\n", result.validatorID, result.err)
+				continue
+			}
+			err := dc.Signers.Verify(result.commit)
+			if err == nil && (result.commit.Sender != result.validatorID || result.commit.ValidatorID != result.validatorID) {
+				err = fmt.Errorf("%w: %s sent a commitment as %s", subnet.ErrVoterMismatch, result.commit.Sender, result.commit.ValidatorID)
+			}
+			if err != nil {
+				fmt.Printf("🚫 Discarding commitment from %s: %v\n", result.validatorID, err)
+				continue
+			}
+			commits[result.validatorID] = result.commit
+		case <-ctx.Done():
+			fmt.Printf("⏰ Commit deadline (%v) passed; %d validator(s) did not commit\n", deadline, pending)
+			return commits
+		}
+	}
+	return commits
+}
+
 // printSummary prints the final state of the subnet
 func (dc *DemoCoordinator) printSummary() {
 	fmt.Printf("=== Demo Summary (Refactored Architecture) ===\n")
-	minerClock := dc.Miner.GetCurrentClock()
-	fmt.Printf("Miner final VLC Clock: %v\n", minerClock.Values)
+	for _, miner := range dc.Miners {
+		minerClock := miner.GetCurrentClock()
+		fmt.Printf("%s final VLC Clock: %v\n", miner.ID, minerClock.Values)
+	}
+
+	fmt.Printf("\nMiner routing (%s):\n", dc.Router.Strategy)
+	for _, stats := range dc.Router.Stats() {
+		fmt.Printf("  %s: %d tasks, %d succeeded, reputation %.2f\n",
+			stats.MinerID, stats.Completed, stats.Succeeded, stats.Reputation())
+	}
 
 	fmt.Printf("\nValidator final states:\n")
 	for _, validator := range dc.Validators {
@@ -744,6 +1326,8 @@ func (dc *DemoCoordinator) printSummary() {
 		fmt.Printf("  %s: Last miner clock = %v\n", validator.ID, validatorClock.Values)
 	}
 
+	dc.Accountability.PrintSummary()
+
 	// Print session payment summary if in session mode
 	if dc.PaymentCoord != nil && dc.PaymentCoord.IsSessionMode() {
 		dc.PaymentCoord.PrintSessionSummary()
@@ -0,0 +1,740 @@
+package demo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hetu-project/FLUX-Mining-8004-x402/subnet"
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// Demo participant layout: DEMO_MINER_COUNT miners (default 1) and 4 validators,
+// validator-1 orchestrates rounds
+const (
+	DemoMinerID        = "miner-1"
+	DemoValidatorCount = 4
+)
+
+// defaultMinerKeys are the local Anvil miner keys used when MINER_KEY (miner-1) or
+// MINER_N_KEY (miner-N) is not set; further miners get ephemeral keys
+var defaultMinerKeys = []string{
+	"0x8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba",
+	"0x92db14e403b83dfe3df233f83dfa3a0d7096f21ca9b0d6d6b8d88b2b4ec1564e",
+	"0x4bbbf85ce3377467afe5d46f804f221813b2bb87f24d81f60f1fcdbf7cbf4356",
+	"0x2a871d0798f97d79848a013d4936a73bf4cc922c825d33c1cf7073dff6d409c6",
+}
+
+// defaultValidatorKeys are the local Anvil validator keys used when VALIDATOR_N_KEY is not set
+var defaultValidatorKeys = []string{
+	"0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	"0x5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+	"0x7c852118294e51e653712a81e05800f419141751be58f605c371e15141b007a6",
+	"0x47e179ec197488593b187f80a00eb0da91f1b9d0b13f8733639f19c30a34926a",
+}
+
+// DemoValidatorID returns the participant ID of the n-th demo validator (1-based)
+func DemoValidatorID(n int) string {
+	return fmt.Sprintf("validator-%d", n)
+}
+
+// DemoValidatorIDs returns the participant IDs of all demo validators in order
+func DemoValidatorIDs() []string {
+	ids := make([]string, DemoValidatorCount)
+	for i := range ids {
+		ids[i] = DemoValidatorID(i + 1)
+	}
+	return ids
+}
+
+// DemoMinerIDFor returns the participant ID of the n-th demo miner (1-based)
+func DemoMinerIDFor(n int) string {
+	return fmt.Sprintf("miner-%d", n)
+}
+
+// DemoMinerIDs returns the participant IDs of all demo miners in order (DEMO_MINER_COUNT)
+func DemoMinerIDs() []string {
+	ids := make([]string, demoMinerCountFromEnv())
+	for i := range ids {
+		ids[i] = DemoMinerIDFor(i + 1)
+	}
+	return ids
+}
+
+// demoMinerKeyEnv returns the environment variable holding the n-th miner's key
+func demoMinerKeyEnv(n int) string {
+	if n == 1 {
+		return "MINER_KEY"
+	}
+	return fmt.Sprintf("MINER_%d_KEY", n)
+}
+
+// defaultMinerKey returns the local fallback key of the n-th miner ("" if there is none)
+func defaultMinerKey(n int) string {
+	if n > len(defaultMinerKeys) {
+		return ""
+	}
+	return defaultMinerKeys[n-1]
+}
+
+// demoMinerAddress returns the n-th miner's agent (payment and reward) address:
+// MINER_ADDRESS (miner-1) or MINER_N_ADDRESS, falling back to the address of its key.
+// Returns "" for a miner without a configured or default key.
+func demoMinerAddress(n int) string {
+	envVar := "MINER_ADDRESS"
+	if n > 1 {
+		envVar = fmt.Sprintf("MINER_%d_ADDRESS", n)
+	}
+	if address := os.Getenv(envVar); address != "" {
+		return address
+	}
+
+	key := os.Getenv(demoMinerKeyEnv(n))
+	if key == "" {
+		key = defaultMinerKey(n)
+	}
+	if signer, err := subnet.NewMessageSigner(key); err == nil {
+		return signer.Address().Hex()
+	}
+	return ""
+}
+
+// loadMessageSigner creates a participant's message signer from an environment key,
+// falling back to the given local key and finally to an ephemeral key
+func loadMessageSigner(envVar, fallbackKey string) *subnet.MessageSigner {
+	key := os.Getenv(envVar)
+	if key == "" {
+		key = fallbackKey
+	}
+
+	signer, err := subnet.NewMessageSigner(key)
+	if err != nil {
+		fmt.Printf("⚠️  Invalid signing key in %s (%v), using ephemeral key\n", envVar, err)
+		signer, err = subnet.GenerateMessageSigner()
+		if err != nil {
+			fmt.Printf("❌ Failed to generate signing key: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	return signer
+}
+
+// NewDemoMiner creates the n-th demo miner (1-based) with its task processor, VLC node ID
+// and signing key, registering its signing address in signers
+func NewDemoMiner(n int, subnetID string, signers *subnet.SignerRegistry) *subnet.CoreMiner {
+	miner := subnet.NewCoreMiner(DemoMinerIDFor(n), subnetID)
+	miner.SetClockID(subnet.MinerClockIDFor(n - 1)) // Each miner advances its own VLC entry
+	miner.SetTaskProcessor(NewDemoTaskProcessor())
+	if os.Getenv("LLM_MODEL") != "" {
+		// Real model server instead of canned demo answers
+		config, err := subnet.OpenAIConfigFromEnv()
+		if err == nil {
+			var processor *subnet.OpenAITaskProcessor
+			if processor, err = subnet.NewOpenAITaskProcessor(config); err == nil {
+				miner.SetContextTaskProcessor(processor)
+				fmt.Printf("🤖 Miner using model %s at %s (%s decisions)\n", config.Model, config.BaseURL, processor.DecisionMode())
+			}
+		}
+		if err != nil {
+			fmt.Printf("❌ Invalid LLM configuration: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	miner.SetTaskTimeout(taskTimeoutFromEnv())
+	miner.SetResponseRetention(responseRetentionFromEnv())
+	miner.SetMaxDialogTurns(maxDialogTurnsFromEnv())
+	enableMinerJournalFromEnv(miner)
+
+	minerSigner := loadMessageSigner(demoMinerKeyEnv(n), defaultMinerKey(n))
+	miner.SetMessageSigner(minerSigner)
+	signers.Register(miner.ID, minerSigner.Address())
+
+	return miner
+}
+
+// NewDemoValidator creates the n-th demo validator (1-based) with demo plugins and signing key,
+// registering its signing address in signers. Validator-1 starts as the user interface
+// validator; the coordinator rotates the role per epoch (see LeaderSchedule).
+func NewDemoValidator(n int, subnetID string, signers *subnet.SignerRegistry) *subnet.CoreValidator {
+	role := subnet.ConsensusValidator
+	if n == 1 {
+		role = subnet.UserInterfaceValidator // First validator handles user interaction
+	}
+
+	validator := subnet.NewCoreValidator(
+		DemoValidatorID(n),
+		subnetID,
+		role,
+		1.0/DemoValidatorCount, // Equal weights for all validators
+	)
+	validator.SetClockID(subnet.ValidatorClockID(n - 1)) // Validator-N advances VLC entry N+1 when leading
+
+	// Set demo-specific plugins
+	validator.SetQualityAssessor(qualityAssessorFromEnv(validator.ID, subnetID))
+	validator.SetUserInteractionHandler(userInteractionHandlerFromEnv())
+
+	// Show streamed output to the user as it arrives (only the round leader receives chunks)
+	validator.SetChunkListener(func(chunk *subnet.MinerChunkMessage, partial string) {
+		fmt.Printf("📡 %s ← chunk %d: %q\n", validator.ID, chunk.Sequence, chunk.Chunk)
+	})
+
+	// Hold out-of-order miner responses until their causal predecessors arrive
+	validator.EnableCausalDelivery(vlc.CausalBufferConfig{})
+
+	// Message authentication
+	validatorSigner := loadMessageSigner(fmt.Sprintf("VALIDATOR_%d_KEY", n), defaultValidatorKeys[n-1])
+	validator.SetMessageSigner(validatorSigner)
+	validator.SetSignerRegistry(signers)
+	signers.Register(validator.ID, validatorSigner.Address())
+
+	return validator
+}
+
+// RegisterDemoParticipants tells a validator which VLC entry each demo miner (MinerClockIDFor)
+// and validator (ValidatorClockID, by position in validatorIDs) advances; validators refuse
+// clocks from entries nobody registered
+func RegisterDemoParticipants(validator *subnet.CoreValidator, minerIDs, validatorIDs []string) {
+	for i, minerID := range minerIDs {
+		validator.RegisterMiner(minerID, subnet.MinerClockIDFor(i))
+	}
+	for i, validatorID := range validatorIDs {
+		validator.RegisterParticipant(subnet.SequenceParticipant{
+			ClockID: subnet.ValidatorClockID(i),
+			Kind:    subnet.ParticipantValidator,
+			Name:    validatorID,
+		})
+	}
+}
+
+// NewDemoSignerRegistry builds the signer registry for a process that only hosts some
+// of the demo participants. Addresses are derived from the participant keys (environment
+// or local fallback), then overridden by SUBNET_SIGNERS so hosts need not share keys:
+//
+//	SUBNET_SIGNERS="miner-1=0xabc...,validator-2=0xdef..."
+func NewDemoSignerRegistry() *subnet.SignerRegistry {
+	signers := subnet.NewSignerRegistry()
+
+	registerKey := func(participantID, envVar, fallbackKey string) {
+		key := os.Getenv(envVar)
+		if key == "" {
+			key = fallbackKey
+		}
+		if signer, err := subnet.NewMessageSigner(key); err == nil {
+			signers.Register(participantID, signer.Address())
+		}
+	}
+
+	for n := 1; n <= demoMinerCountFromEnv(); n++ {
+		registerKey(DemoMinerIDFor(n), demoMinerKeyEnv(n), defaultMinerKey(n))
+	}
+	for i := 1; i <= DemoValidatorCount; i++ {
+		registerKey(DemoValidatorID(i), fmt.Sprintf("VALIDATOR_%d_KEY", i), defaultValidatorKeys[i-1])
+	}
+
+	for _, entry := range strings.Split(os.Getenv("SUBNET_SIGNERS"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if !common.IsHexAddress(parts[1]) {
+			fmt.Printf("⚠️  Ignoring invalid signer address for %s: %s\n", parts[0], parts[1])
+			continue
+		}
+		signers.Register(parts[0], common.HexToAddress(parts[1]))
+	}
+
+	return signers
+}
+
+// paymentsEnabled reports whether the x402 payment and reputation systems should run
+func paymentsEnabled() bool {
+	return os.Getenv("SUBNET_ONLY_MODE") != "true" && os.Getenv("VALIDATION_ONLY_MODE") != "true"
+}
+
+// voteDeadlineFromEnv returns the per-round vote deadline from VOTE_DEADLINE (e.g., "5s")
+func voteDeadlineFromEnv() time.Duration {
+	value := os.Getenv("VOTE_DEADLINE")
+	if value == "" {
+		return subnet.DefaultVoteDeadline
+	}
+	deadline, err := time.ParseDuration(value)
+	if err != nil || deadline <= 0 {
+		fmt.Printf("⚠️  Ignoring invalid VOTE_DEADLINE=%s (e.g., 5s)\n", value)
+		return subnet.DefaultVoteDeadline
+	}
+	return deadline
+}
+
+// taskTimeoutFromEnv returns the miner's per-step task deadline from TASK_TIMEOUT (e.g., "30s")
+func taskTimeoutFromEnv() time.Duration {
+	value := os.Getenv("TASK_TIMEOUT")
+	if value == "" {
+		return subnet.DefaultTaskTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		fmt.Printf("⚠️  Ignoring invalid TASK_TIMEOUT=%s (e.g., 30s)\n", value)
+		return subnet.DefaultTaskTimeout
+	}
+	return timeout
+}
+
+// httpUserHandler is shared by every local validator; only the round leader prompts the user
+var (
+	httpUserHandler     *subnet.HTTPUserHandler
+	httpUserHandlerOnce sync.Once
+)
+
+// userInteractionHandlerFromEnv returns the long-poll HTTP handler serving end users on
+// USER_HTTP_ADDR (bearer USER_HTTP_TOKEN, replies awaited for USER_RESPONSE_TIMEOUT), or the
+// simulated demo user when no address is set. An address that cannot be served is fatal.
+func userInteractionHandlerFromEnv() subnet.UserInteractionHandler {
+	addr := os.Getenv("USER_HTTP_ADDR")
+	if addr == "" {
+		return NewDemoUserInteractionHandler()
+	}
+
+	httpUserHandlerOnce.Do(func() {
+		httpUserHandler = subnet.NewHTTPUserHandler(subnet.HTTPUserConfig{
+			ListenAddr: addr,
+			Token:      os.Getenv("USER_HTTP_TOKEN"),
+			Timeout:    userResponseTimeoutFromEnv(),
+		})
+		if err := httpUserHandler.Start(); err != nil {
+			fmt.Printf("❌ Failed to serve end users: %v\n", err)
+			os.Exit(1)
+		}
+	})
+	return httpUserHandler
+}
+
+// userResponseTimeoutFromEnv returns how long a round waits for each user reply, from
+// USER_RESPONSE_TIMEOUT (e.g., "2m")
+func userResponseTimeoutFromEnv() time.Duration {
+	value := os.Getenv("USER_RESPONSE_TIMEOUT")
+	if value == "" {
+		return subnet.DefaultUserResponseTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		fmt.Printf("⚠️  Ignoring invalid USER_RESPONSE_TIMEOUT=%s (e.g., 2m)\n", value)
+		return subnet.DefaultUserResponseTimeout
+	}
+	return timeout
+}
+
+// responseRetentionFromEnv returns how long miners answer duplicate requests from their
+// cache, from RESPONSE_RETENTION (e.g., "10m")
+func responseRetentionFromEnv() time.Duration {
+	value := os.Getenv("RESPONSE_RETENTION")
+	if value == "" {
+		return subnet.DefaultResponseRetention
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention <= 0 {
+		fmt.Printf("⚠️  Ignoring invalid RESPONSE_RETENTION=%s (e.g., 10m)\n", value)
+		return subnet.DefaultResponseRetention
+	}
+	return retention
+}
+
+// demoMinerCountFromEnv returns how many miners the demo subnet hosts from DEMO_MINER_COUNT
+func demoMinerCountFromEnv() int {
+	value := os.Getenv("DEMO_MINER_COUNT")
+	if value == "" {
+		return 1
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 {
+		fmt.Printf("⚠️  Ignoring invalid DEMO_MINER_COUNT=%s (e.g., 3)\n", value)
+		return 1
+	}
+	return count
+}
+
+// routingStrategyFromEnv returns how tasks are assigned to miners from TASK_ROUTING
+func routingStrategyFromEnv() subnet.RoutingStrategy {
+	strategy, err := subnet.ParseRoutingStrategy(os.Getenv("TASK_ROUTING"))
+	if err != nil {
+		fmt.Printf("⚠️  Ignoring %v\n", err)
+		return subnet.RouteRoundRobin
+	}
+	return strategy
+}
+
+// maxDialogTurnsFromEnv returns the clarification turn limit from DIALOG_MAX_TURNS
+func maxDialogTurnsFromEnv() int {
+	value := os.Getenv("DIALOG_MAX_TURNS")
+	if value == "" {
+		return subnet.DefaultMaxDialogTurns
+	}
+	turns, err := strconv.Atoi(value)
+	if err != nil || turns <= 0 {
+		fmt.Printf("⚠️  Ignoring invalid DIALOG_MAX_TURNS=%s (e.g., 3)\n", value)
+		return subnet.DefaultMaxDialogTurns
+	}
+	return turns
+}
+
+// Quality assessor kinds configurable from the environment (see qualityAssessorFromEnv)
+var qualityAssessorEnv = map[string]string{
+	"judge":     "JUDGE_MODEL",
+	"rubric":    "RUBRIC_FILE",
+	"reference": "REFERENCE_FILE",
+}
+
+// ensembleFile is the QUALITY_ENSEMBLE_FILE format: a default mix and per-validator mixes
+type ensembleFile struct {
+	Default    *subnet.EnsembleSpec           `json:"default,omitempty"`
+	Validators map[string]subnet.EnsembleSpec `json:"validators,omitempty"`
+}
+
+// qualityAssessorFromEnv returns a validator's quality assessor:
+//   - QUALITY_ENSEMBLE_FILE: the validator's own mix, or the file's default mix; components
+//     name "demo", "judge" (JUDGE_*), "rubric" (RUBRIC_FILE) or "reference" (REFERENCE_FILE)
+//   - otherwise the one assessor configured by JUDGE_MODEL, RUBRIC_FILE or REFERENCE_FILE
+//   - otherwise the demo assessor
+//
+// An assessor that cannot be configured is fatal.
+func qualityAssessorFromEnv(validatorID, subnetID string) subnet.QualityAssessor {
+	resolve := func(kind string) (subnet.QualityAssessor, error) {
+		return demoQualityAssessor(kind, subnetID)
+	}
+
+	if path := os.Getenv("QUALITY_ENSEMBLE_FILE"); path != "" {
+		spec, err := loadEnsembleSpec(path, validatorID)
+		if err != nil {
+			fmt.Printf("❌ Failed to load quality ensemble: %v\n", err)
+			os.Exit(1)
+		}
+		if spec != nil {
+			ensemble, err := spec.Build(resolve)
+			if err != nil {
+				fmt.Printf("❌ Validator %s: %v\n", validatorID, err)
+				os.Exit(1)
+			}
+			names := make([]string, 0, len(spec.Components))
+			for _, component := range ensemble.Components() {
+				names = append(names, fmt.Sprintf("%s×%.2g", component.Name, component.Weight))
+			}
+			fmt.Printf("🧩 Validator %s assesses with %s: %s\n", validatorID, ensemble.Name(), strings.Join(names, ", "))
+			return ensemble
+		}
+	}
+
+	var configured []string
+	for _, kind := range []string{"judge", "rubric", "reference"} {
+		if os.Getenv(qualityAssessorEnv[kind]) != "" {
+			configured = append(configured, kind)
+		}
+	}
+	switch len(configured) {
+	case 0:
+		return NewDemoQualityAssessor()
+	case 1:
+		assessor, err := resolve(configured[0])
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		return assessor
+	default:
+		fmt.Printf("❌ Several quality assessors configured (%s); combine them with QUALITY_ENSEMBLE_FILE\n", strings.Join(configured, ", "))
+		os.Exit(1)
+		return nil
+	}
+}
+
+// loadEnsembleSpec returns the mix a validator uses from an ensemble file, or nil if the file
+// has neither a mix for it nor a default
+func loadEnsembleSpec(path, validatorID string) (*subnet.EnsembleSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var file ensembleFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if spec, ok := file.Validators[validatorID]; ok {
+		return &spec, nil
+	}
+	return file.Default, nil
+}
+
+// demoQualityAssessor creates the assessor of one kind from its environment configuration
+func demoQualityAssessor(kind, subnetID string) (subnet.QualityAssessor, error) {
+	env, known := qualityAssessorEnv[kind]
+	switch {
+	case kind == "demo":
+	case !known:
+		return nil, fmt.Errorf("unknown quality assessor %q (expected demo, judge, rubric or reference)", kind)
+	case os.Getenv(env) == "":
+		return nil, fmt.Errorf("%s assessor needs %s", kind, env)
+	}
+
+	switch kind {
+	case "judge":
+		config, err := subnet.LLMJudgeConfigFromEnv()
+		if err != nil {
+			return nil, fmt.Errorf("invalid judge configuration: %v", err)
+		}
+		judge, err := subnet.NewLLMJudgeAssessor(config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid judge configuration: %v", err)
+		}
+		fmt.Printf("⚖️  Validator quality judged by model %s at %s\n", config.Model, config.BaseURL)
+		return judge, nil
+
+	case "rubric":
+		path := os.Getenv("RUBRIC_FILE")
+		assessor, err := subnet.LoadRubricAssessor(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load quality rubric: %v", err)
+		}
+		if rubric := assessor.Rubric(); rubric.SubnetID != "" && rubric.SubnetID != subnetID {
+			return nil, fmt.Errorf("quality rubric %s is for subnet %s, not %s", path, rubric.SubnetID, subnetID)
+		}
+		return assessor, nil
+
+	case "reference":
+		path := os.Getenv("REFERENCE_FILE")
+		assessor, err := subnet.LoadReferenceAssessor(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load reference answers: %v", err)
+		}
+		if set := assessor.References(); set.SubnetID != "" && set.SubnetID != subnetID {
+			return nil, fmt.Errorf("reference answers %s are for subnet %s, not %s", path, set.SubnetID, subnetID)
+		}
+		return assessor, nil
+
+	default:
+		return NewDemoQualityAssessor(), nil
+	}
+}
+
+// enableMinerJournalFromEnv makes a miner journal its state to MINER_JOURNAL_DIR/<miner-id>.jsonl
+// and recovers from it; a journal that cannot be recovered is fatal
+func enableMinerJournalFromEnv(miner *subnet.CoreMiner) {
+	dir := os.Getenv("MINER_JOURNAL_DIR")
+	if dir == "" {
+		return
+	}
+	recovery, err := miner.EnableJournal(filepath.Join(dir, miner.ID+".jsonl"))
+	if err != nil {
+		fmt.Printf("❌ Miner %s: Journal recovery failed: %v\n", miner.ID, err)
+		os.Exit(1)
+	}
+	if recovery.Entries == 0 {
+		fmt.Printf("📒 Miner %s: Journaling state to %s\n", miner.ID, dir)
+		return
+	}
+	fmt.Printf("📒 Miner %s: Recovered %d journal entries - VLC %v, %d step(s) since last reset\n",
+		miner.ID, recovery.Entries, recovery.Clock.Values, recovery.Completed)
+	if len(recovery.Interrupted) > 0 {
+		fmt.Printf("   ⚠️  Interrupted by the restart (resumed when retried): %s\n", strings.Join(recovery.Interrupted, ", "))
+	}
+}
+
+// certificateValidatorSet returns the validator addresses quorum certificates are checked against:
+// the on-chain set from SubnetRegistry.getSubnet when SUBNET_REGISTRY_ADDRESS is set and payments
+// run against a chain, otherwise the locally registered signing addresses
+func certificateValidatorSet(subnetID string, signers *subnet.SignerRegistry, validatorIDs []string) []common.Address {
+	if registry := os.Getenv("SUBNET_REGISTRY_ADDRESS"); registry != "" && paymentsEnabled() {
+		validators, err := subnet.FetchSubnetValidators(demoRPCURL(), common.HexToAddress(registry), subnetID)
+		if err == nil {
+			fmt.Printf("📜 Quorum certificates checked against %d registered validators of %s\n", len(validators), subnetID)
+			return validators
+		}
+		fmt.Printf("⚠️  Could not load validator set from SubnetRegistry: %v (using local signers)\n", err)
+	}
+
+	validators := make([]common.Address, 0, len(validatorIDs))
+	for _, id := range validatorIDs {
+		if address, ok := signers.Address(id); ok {
+			validators = append(validators, address)
+		}
+	}
+	return validators
+}
+
+// demoRPCURL returns the RPC URL for blockchain interactions (used by payment and reputation systems)
+func demoRPCURL() string {
+	rpcURL := os.Getenv("RPC_URL")
+	if rpcURL == "" {
+		rpcURL = "http://localhost:8545" // Default to localhost if not set
+	}
+	return rpcURL
+}
+
+// newDemoPaymentCoordinator initializes the x402 payment coordinator, returning nil if unavailable
+func newDemoPaymentCoordinator(rpcURL string) *subnet.PaymentCoordinator {
+	fmt.Println("💰 Initializing x402 Payment System...")
+
+	// V1 Coordinator key - use environment or fallback to local
+	v1CoordKey := os.Getenv("VALIDATOR_1_KEY")
+	if v1CoordKey == "" {
+		v1CoordKey = defaultValidatorKeys[0]
+	}
+
+	paymentCoord, err := subnet.NewPaymentCoordinator(
+		rpcURL,
+		"contract_addresses.json",
+		v1CoordKey,
+	)
+	if err != nil {
+		fmt.Printf("⚠️  Payment system unavailable: %v\n", err)
+		fmt.Println("   Continuing without payment integration...")
+		return nil
+	}
+
+	fmt.Println("✅ Payment coordinator initialized successfully")
+	return paymentCoord
+}
+
+// configureMinerPayment makes the miner verify escrowed payment to agentAddress before processing tasks
+func configureMinerPayment(miner *subnet.CoreMiner, paymentCoord *subnet.PaymentCoordinator, agentAddress string) {
+	// Configure miner with payment verification (trustless operation)
+	// Miner will verify payment is locked in escrow before processing tasks
+	minPayment := "10000000" // 10 tokens minimum (10 * 10^6 wei for USDC decimals)
+	miner.SetPaymentVerifier(paymentCoord, agentAddress, minPayment)
+	fmt.Printf("🔐 Miner configured with payment verification\n")
+	fmt.Printf("   Agent address: %s\n", agentAddress)
+	fmt.Printf("   Minimum payment: 10 %s\n", paymentCoord.GetPaymentTokenName())
+}
+
+// newDemoReputationSystem initializes the reputation feedback manager and batch submitter.
+// Either may be nil if initialization fails; missing registry configuration is fatal.
+func newDemoReputationSystem(rpcURL string) (*subnet.ReputationFeedbackManager, *subnet.ReputationBatchSubmitter) {
+	// Initialize reputation feedback manager
+	fmt.Println("⭐ Initializing Reputation Feedback System...")
+
+	// IdentityRegistry address from environment
+	identityRegistryAddrStr := os.Getenv("IDENTITY_REGISTRY_ADDRESS")
+	if identityRegistryAddrStr == "" {
+		fmt.Printf("❌ IDENTITY_REGISTRY_ADDRESS not set\n")
+		os.Exit(1)
+	}
+	identityRegistryAddr := common.HexToAddress(identityRegistryAddrStr)
+
+	// Get chain ID from environment or default to 31337 (local)
+	chainIDStr := os.Getenv("CHAIN_ID")
+	chainIDValue := uint64(31337) // Default to local
+	if chainIDStr != "" {
+		if parsedChainID, err := strconv.ParseUint(chainIDStr, 10, 64); err == nil {
+			chainIDValue = parsedChainID
+		}
+	}
+
+	// Get miner key from environment or fallback to local
+	minerKey := os.Getenv("MINER_KEY")
+	if minerKey == "" {
+		minerKey = defaultMinerKey(1)
+	}
+
+	// Get client address from environment or fallback to Sepolia
+	clientAddr := os.Getenv("CLIENT_ADDRESS")
+	if clientAddr == "" {
+		clientAddr = "0xfA6EC9Cf1E293A91a8ea2EdCc4A2324d48129821" // Sepolia client with USDC
+	}
+
+	// Get agent ID from environment (set by run-flux-mining.sh after querying blockchain)
+	agentIDStr := os.Getenv("AGENT_ID_DEC")
+	if agentIDStr == "" {
+		fmt.Printf("❌ AGENT_ID_DEC not set - agent must be registered on blockchain first\n")
+		fmt.Printf("   Run the script which queries/registers agent ID from IdentityRegistry\n")
+		os.Exit(1)
+	}
+
+	agentID, err := strconv.ParseUint(agentIDStr, 10, 64)
+	if err != nil {
+		fmt.Printf("❌ Invalid AGENT_ID_DEC: %s - must be a valid number\n", agentIDStr)
+		os.Exit(1)
+	}
+
+	fmt.Printf("   Using Agent ID: %d (from blockchain)\n", agentID)
+
+	reputationMgr, err := subnet.NewReputationFeedbackManager(
+		agentID,                         // Agent ID from environment (e.g., 1168)
+		minerKey,                        // Miner's private key - from environment or local fallback
+		common.HexToAddress(clientAddr), // Client address - from environment or local fallback
+		identityRegistryAddr,
+		chainIDValue, // Use environment chain ID or default
+	)
+	if err != nil {
+		fmt.Printf("⚠️  Reputation init failed: %v\n", err)
+		return nil, nil
+	}
+
+	// Initialize reputation batch submitter (client-side)
+	// ReputationRegistry address from environment or contract_addresses.json
+	reputationRegistryAddrStr := os.Getenv("REPUTATION_REGISTRY_ADDRESS")
+	if reputationRegistryAddrStr == "" {
+		fmt.Printf("❌ REPUTATION_REGISTRY_ADDRESS not set\n")
+		os.Exit(1)
+	}
+	reputationRegistryAddr := common.HexToAddress(reputationRegistryAddrStr)
+
+	// Initialize TaskIndexCounter from blockchain to prevent IndexLimit errors
+	_ = reputationMgr.InitializeFromBlockchain(rpcURL, reputationRegistryAddr)
+
+	// Get client key from environment or fallback to Sepolia
+	clientKey := os.Getenv("CLIENT_KEY")
+	if clientKey == "" {
+		clientKey = "0xdbda1821b80551c9d65939329250298aa3472ba22feea921c0cf5d620ea67b97" // Sepolia client key
+	}
+
+	submitter, err := subnet.NewReputationBatchSubmitter(
+		rpcURL, // Use RPC URL from environment
+		reputationRegistryAddr,
+		clientKey,    // Client's private key - from environment or local fallback
+		chainIDValue, // Use environment chain ID or default
+	)
+	if err != nil {
+		fmt.Printf("⚠️  Batch submitter failed: %v\n", err)
+		return reputationMgr, nil
+	}
+	return reputationMgr, submitter
+}
+
+// EnableDemoMinerPayments turns on escrow payment verification for the n-th miner running in
+// its own process, mirroring what NewDemoCoordinator configures for in-process miners
+func EnableDemoMinerPayments(miner *subnet.CoreMiner, n int) {
+	if !paymentsEnabled() {
+		return
+	}
+	if paymentCoord := newDemoPaymentCoordinator(demoRPCURL()); paymentCoord != nil {
+		configureMinerPayment(miner, paymentCoord, demoMinerAddress(n))
+	}
+}
+
+// demoMinerAgentIDs returns the ERC-8004 agent ID of every miner: miner-1 is primary
+// (AGENT_ID_DEC), miner-N reads MINER_N_AGENT_ID. Reputation cannot be credited to a
+// miner without an identity, so a missing ID is fatal.
+func demoMinerAgentIDs(minerIDs []string, primary *big.Int) map[string]*big.Int {
+	agentIDs := make(map[string]*big.Int, len(minerIDs))
+	for i, minerID := range minerIDs {
+		if i == 0 {
+			agentIDs[minerID] = primary
+			continue
+		}
+		envVar := fmt.Sprintf("MINER_%d_AGENT_ID", i+1)
+		agentID, ok := new(big.Int).SetString(os.Getenv(envVar), 10)
+		if !ok {
+			fmt.Printf("❌ %s not set or invalid - %s must be registered on blockchain first\n", envVar, minerID)
+			os.Exit(1)
+		}
+		agentIDs[minerID] = agentID
+	}
+	return agentIDs
+}
@@ -29,6 +29,11 @@ func NewDemoQualityAssessor() *DemoQualityAssessor {
 	return &DemoQualityAssessor{}
 }
 
+// Name identifies the demo assessor in quality reports
+func (d *DemoQualityAssessor) Name() string {
+	return "demo"
+}
+
 // AssessQuality evaluates miner output using predetermined demo logic.
 // Maps input numbers to specific quality scores and acceptance decisions
 // to create predictable test scenarios for the PoC demonstration.
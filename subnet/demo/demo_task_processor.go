@@ -11,7 +11,10 @@
 package demo
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hetu-project/FLUX-Mining-8004-x402/subnet"
 )
@@ -21,7 +24,8 @@ import (
 //
 // Demo Scenario Map:
 //   Input 1, 2, 5, 7: Immediate high-quality solutions (OutputReady)
-//   Input 3, 6: Request additional context (NeedMoreInfo) then generate solutions
+//   Input 3: Request additional context (NeedMoreInfo) then generate a solution
+//   Input 6: Ask two clarification questions (multi-turn dialog) then generate a solution
 //   Input 4: Generate low-quality solution that validators should reject
 //
 // This enables testing all aspects of the PoCW protocol in a controlled manner.
@@ -69,6 +73,55 @@ func (d *DemoTaskProcessor) ProcessAdditionalInfo(originalInput string, addition
 	return output
 }
 
+// demoChunkDelay simulates token generation latency between streamed words
+const demoChunkDelay = 50 * time.Millisecond
+
+// StreamTask emits the demo output word by word. Tasks that need clarification
+// are answered without streaming.
+func (d *DemoTaskProcessor) StreamTask(ctx context.Context, input string, inputNumber int, emit func(chunk string) error) (*subnet.TaskOutcome, error) {
+	outputType, output, infoRequest := d.ProcessTask(input, inputNumber)
+	if outputType != subnet.OutputReady {
+		return &subnet.TaskOutcome{OutputType: outputType, InfoRequest: infoRequest}, nil
+	}
+
+	words := strings.SplitAfter(output, " ")
+	for _, word := range words {
+		select {
+		case <-time.After(demoChunkDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if err := emit(word); err != nil {
+			return nil, err
+		}
+	}
+	return &subnet.TaskOutcome{OutputType: subnet.OutputReady, Output: output}, nil
+}
+
+// ProcessDialog implements multi-turn clarification for demo scenarios.
+// Input 6 asks a second question after the first answer; every other task
+// finishes once it has one answer.
+func (d *DemoTaskProcessor) ProcessDialog(ctx context.Context, originalInput string, history []subnet.DialogTurn, inputNumber int) (*subnet.TaskOutcome, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if inputNumber == 6 && len(history) == 1 {
+		fmt.Printf("Miner: Input %d - Requesting follow-up clarification (turn 2)\n", inputNumber)
+		return &subnet.TaskOutcome{
+			OutputType:  subnet.NeedMoreInfo,
+			InfoRequest: "Thanks. Which rate limits and response-time targets must the API meet?",
+		}, nil
+	}
+
+	answers := make([]string, 0, len(history))
+	for _, turn := range history {
+		answers = append(answers, turn.Answer)
+	}
+	output := d.ProcessAdditionalInfo(originalInput, strings.Join(answers, " "), inputNumber)
+	return &subnet.TaskOutcome{OutputType: subnet.OutputReady, Output: output}, nil
+}
+
 // generateOutput simulates AI processing and generates demo-specific outputs
 func (d *DemoTaskProcessor) generateOutput(input string, inputNumber int) string {
 	// Simulate different types of outputs based on input number
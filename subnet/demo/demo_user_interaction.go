@@ -6,10 +6,16 @@
 //   - User rejection despite validator approval (demonstrating user sovereignty)
 //   - Realistic feedback messages for different scenarios
 //
-// In production, this would be replaced with actual user interfaces,
-// feedback collection systems, or user preference learning algorithms.
+// With USER_HTTP_ADDR set, the demo uses subnet.HTTPUserHandler instead and waits for a
+// real user over long-poll HTTP.
 package demo
 
+import (
+	"context"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/subnet"
+)
+
 // DemoUserInteractionHandler simulates realistic user feedback patterns for demonstration.
 // Models different user behavior scenarios to test the complete PoCW workflow:
 //
@@ -49,4 +55,28 @@ func (d *DemoUserInteractionHandler) SimulateUserInteraction(inputNumber int, ou
 		// User accepts satisfactory output (typical positive scenario)
 		return true, "This looks good, thank you!"
 	}
-}
\ No newline at end of file
+}
+// ReviewOutput answers a review prompt with the simulated decision, without waiting
+func (d *DemoUserInteractionHandler) ReviewOutput(ctx context.Context, prompt *subnet.UserPrompt) (bool, string, error) {
+	accept, feedback := d.SimulateUserInteraction(prompt.InputNumber, prompt.Output)
+	return accept, feedback, nil
+}
+
+// RequestClarification answers a miner's question with the simulated user's answer for that
+// input and turn:
+//   - Input 3: one answer (cost focus)
+//   - Input 6: two answers (API style, then rate limits and latency)
+//   - Any further turn: proceed with reasonable assumptions
+func (d *DemoUserInteractionHandler) RequestClarification(ctx context.Context, prompt *subnet.UserPrompt) (string, error) {
+	answers := map[int][]string{
+		3: {"Focus on cost optimization and ROI analysis specifically."},
+		6: {
+			"Use REST API with JSON payloads, authentication via OAuth 2.0.",
+			"Allow 100 requests per minute per client with responses under 200 ms.",
+		},
+	}[prompt.InputNumber]
+	if prompt.Turn >= 1 && prompt.Turn <= len(answers) {
+		return answers[prompt.Turn-1], nil
+	}
+	return "No further details; please proceed with reasonable assumptions.", nil
+}
@@ -0,0 +1,184 @@
+// Package subnet - Multi-Turn Clarification Dialogs
+//
+// This file lets a miner ask several follow-up questions before producing output.
+// Each clarification turn is a full VLC exchange:
+//
+//	leader +1 (question relayed, answer leaves) → miner +2 (enter, leave) → leader +1 (enter)
+//
+// The leader sends the whole conversation (History) with every turn so the miner stays
+// stateless across turns. The miner fails the task once a processor keeps asking past
+// the configured turn limit.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultMaxDialogTurns is how many clarification questions a task may ask by default
+const DefaultMaxDialogTurns = 3
+
+// clarificationsHeader separates the original input from the clarifications in a response's Task
+const clarificationsHeader = "\n\nClarifications:"
+
+// ErrDialogTurnLimit is returned when a processor asks for more information after the last allowed turn
+var ErrDialogTurnLimit = errors.New("clarification turn limit reached")
+
+// DialogTurn is one clarification exchange: the miner's question and the user's answer
+type DialogTurn struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// DialogTaskProcessor is implemented by processors that can hold a multi-turn conversation.
+// ProcessDialog receives every answered turn so far and returns either the output
+// (OutputReady) or the next question (NeedMoreInfo).
+type DialogTaskProcessor interface {
+	ProcessDialog(ctx context.Context, originalInput string, history []DialogTurn, inputNumber int) (*TaskOutcome, error)
+}
+
+// SetMaxDialogTurns sets how many clarification questions a task may ask
+func (m *CoreMiner) SetMaxDialogTurns(turns int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxDialogTurns = turns
+}
+
+// GetMaxDialogTurns returns how many clarification questions a task may ask
+func (m *CoreMiner) GetMaxDialogTurns() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.maxDialogTurns <= 0 {
+		return DefaultMaxDialogTurns
+	}
+	return m.maxDialogTurns
+}
+
+// ProcessDialogTurnContext processes one answered clarification turn. history holds every
+// turn so far, the last one carrying the newest answer. The response is the final output,
+// the next question, or TaskFailed if the turn limit is exceeded.
+//
+// Processors without DialogTaskProcessor support see only the concatenated answers through
+// ProcessAdditionalInfo and therefore always finish in one turn.
+func (m *CoreMiner) ProcessDialogTurnContext(ctx context.Context, originalInput string, history []DialogTurn, inputNumber int, requestID string) *MinerResponseMessage {
+	// A retried turn is answered from the cache without advancing the clock
+	cached, release, err := m.admitStep(ctx, requestID, len(history))
+	if err != nil {
+		return m.unprocessedResponse(requestID, inputNumber, TaskFailed, "", fmt.Sprintf("abandoned while a duplicate was processing: %v", err))
+	}
+	if cached != nil {
+		return cached
+	}
+	defer release()
+
+	response := &MinerResponseMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  m.SubnetID,
+			RequestID: requestID,
+			Type:      MinerResponseType,
+			Sender:    m.ID,
+			Timestamp: time.Now().Unix(),
+		},
+		OutputType:  OutputReady,
+		Task:        dialogTask(originalInput, history),
+		InputNumber: inputNumber,
+		Turn:        len(history),
+	}
+	requestClock, err := m.beginRequest(requestID, inputNumber, "Additional info entered from validator")
+	if err != nil {
+		return m.unprocessedResponse(requestID, inputNumber, TaskFailed, "", fmt.Sprintf("miner journal write failed: %v", err))
+	}
+	response.RequestClock = requestClock
+
+	maxTurns := m.GetMaxDialogTurns()
+	switch {
+	case m.dialogProcessor != nil:
+		m.runTask(ctx, response, func(ctx context.Context) (*TaskOutcome, error) {
+			outcome, err := m.dialogProcessor.ProcessDialog(ctx, originalInput, history, inputNumber)
+			if err == nil && outcome != nil && outcome.OutputType == NeedMoreInfo && len(history) >= maxTurns {
+				return nil, fmt.Errorf("%w: %d of %d", ErrDialogTurnLimit, len(history), maxTurns)
+			}
+			return outcome, err
+		})
+	case m.taskProcessor != nil:
+		m.runTask(ctx, response, func(ctx context.Context) (*TaskOutcome, error) {
+			return m.taskProcessor.ProcessAdditionalInfo(ctx, originalInput, dialogAnswers(history), inputNumber)
+		})
+	default:
+		// Default: simple concatenation
+		response.Output = originalInput + " [Additional: " + dialogAnswers(history) + "]"
+	}
+
+	leaveLabel := "Final output leaving to validator"
+	if response.OutputType == NeedMoreInfo {
+		fmt.Printf("Miner %s: Requesting more info (turn %d/%d)\n", m.ID, len(history)+1, maxTurns)
+		leaveLabel = "Follow-up question leaving to validator"
+	}
+	m.completeRequest(response, leaveLabel)
+	return response
+}
+
+// dialogAnswers joins the user's answers for processors without dialog support
+func dialogAnswers(history []DialogTurn) string {
+	answers := make([]string, 0, len(history))
+	for _, turn := range history {
+		answers = append(answers, turn.Answer)
+	}
+	return strings.Join(answers, "; ")
+}
+
+// dialogTask renders the task with every clarification exchange, as assessors see it
+func dialogTask(originalInput string, history []DialogTurn) string {
+	if len(history) == 0 {
+		return originalInput
+	}
+	var b strings.Builder
+	b.WriteString(originalInput)
+	b.WriteString(clarificationsHeader)
+	for _, turn := range history {
+		if turn.Question != "" {
+			b.WriteString("\nQ: " + turn.Question)
+		}
+		b.WriteString("\nA: " + turn.Answer)
+	}
+	return b.String()
+}
+
+// TaskInput returns the original input of a task rendered by the miner, without clarifications
+func TaskInput(task string) string {
+	if i := strings.Index(task, clarificationsHeader); i >= 0 {
+		return task[:i]
+	}
+	return task
+}
+
+// NewDialogTurnMessage builds the signed message that forwards a clarification turn to a miner.
+// history carries the whole conversation; its last answer is also set as AdditionalInfo.
+func (v *CoreValidator) NewDialogTurnMessage(requestID, minerID, originalInput string, history []DialogTurn, inputNumber int) *AdditionalInfoMessage {
+	latest := ""
+	if len(history) > 0 {
+		latest = history[len(history)-1].Answer
+	}
+
+	msg := &AdditionalInfoMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			RequestID: requestID,
+			Type:      AdditionalInfoType,
+			Sender:    v.ID,
+			Receiver:  minerID,
+			Timestamp: time.Now().Unix(),
+		},
+		AdditionalInfo: latest,
+		History:        append([]DialogTurn(nil), history...),
+		OriginalInput:  originalInput,
+		InputNumber:    inputNumber,
+		VLCClock:       v.GetLastMinerClock(),
+	}
+
+	v.sign(msg)
+	return msg
+}
@@ -0,0 +1,240 @@
+// Package subnet - Ensemble Quality Assessment
+//
+// This file implements EnsembleAssessor, a QualityAssessor that runs several assessors
+// (e.g., rubric, judge model and reference comparison) in parallel on the same response
+// and combines their verdicts. Each validator can configure its own mix.
+//
+// Combining:
+//   - Quality = weighted mean of the components that gave a verdict (abstentions are left
+//     out and the remaining weights renormalized)
+//   - Veto rules: a component with veto_on_reject rejects the output when it rejects
+//     ("any hard failure rejects" = veto_on_reject on every component), and one with
+//     veto_below rejects it when its own quality falls below that value
+//   - Accept = no veto and quality >= pass_threshold
+//   - The ensemble abstains when fewer than min_verdicts components gave a verdict and none vetoed
+//
+// Every component's verdict is recorded in the vote's QualityReport for auditing.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultEnsemblePassThreshold is the combined quality an ensemble requires to accept when it sets none
+const DefaultEnsemblePassThreshold = 0.7
+
+// ErrInvalidEnsemble is returned when an ensemble cannot be built from its configuration
+var ErrInvalidEnsemble = errors.New("invalid assessor ensemble")
+
+// EnsembleComponent is one assessor of an ensemble and how its verdict counts
+type EnsembleComponent struct {
+	Name         string          // Label in reports (empty: the assessor's name, if it has one)
+	Assessor     QualityAssessor // Must be safe for concurrent use
+	Weight       float64         // Share of the combined quality
+	VetoOnReject bool            // The component's rejection rejects the output
+	VetoBelow    float64         // The component's quality below this rejects the output (0: no floor)
+}
+
+// EnsembleConfig configures an EnsembleAssessor
+type EnsembleConfig struct {
+	Name          string
+	Components    []EnsembleComponent
+	PassThreshold float64 // Minimum combined quality to accept (zero: DefaultEnsemblePassThreshold)
+	MinVerdicts   int     // Components that must give a verdict, or the ensemble abstains (zero: 1)
+}
+
+// EnsembleSpec is the file form of an EnsembleConfig. Components name their assessor, which
+// the caller resolves (see Build).
+type EnsembleSpec struct {
+	Name          string                  `json:"name"`
+	PassThreshold float64                 `json:"pass_threshold,omitempty"`
+	MinVerdicts   int                     `json:"min_verdicts,omitempty"`
+	Components    []EnsembleComponentSpec `json:"components"`
+}
+
+// EnsembleComponentSpec is the file form of an EnsembleComponent
+type EnsembleComponentSpec struct {
+	Assessor     string  `json:"assessor"`       // Assessor kind, resolved by the caller (e.g., "rubric")
+	Name         string  `json:"name,omitempty"` // Label in reports (empty: the resolved assessor's name)
+	Weight       float64 `json:"weight"`
+	VetoOnReject bool    `json:"veto_on_reject,omitempty"`
+	VetoBelow    float64 `json:"veto_below,omitempty"`
+}
+
+// Build resolves each component's assessor and creates the ensemble
+func (spec EnsembleSpec) Build(resolve func(assessor string) (QualityAssessor, error)) (*EnsembleAssessor, error) {
+	config := EnsembleConfig{
+		Name:          spec.Name,
+		PassThreshold: spec.PassThreshold,
+		MinVerdicts:   spec.MinVerdicts,
+	}
+	for i, component := range spec.Components {
+		assessor, err := resolve(component.Assessor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: component %d (%s): %v", ErrInvalidEnsemble, i+1, component.Assessor, err)
+		}
+		config.Components = append(config.Components, EnsembleComponent{
+			Name:         component.Name,
+			Assessor:     assessor,
+			Weight:       component.Weight,
+			VetoOnReject: component.VetoOnReject,
+			VetoBelow:    component.VetoBelow,
+		})
+	}
+	return NewEnsembleAssessor(config)
+}
+
+// EnsembleAssessor combines several assessors' verdicts. It is safe for concurrent use.
+type EnsembleAssessor struct {
+	config EnsembleConfig
+}
+
+// NewEnsembleAssessor validates the configuration and creates the ensemble
+func NewEnsembleAssessor(config EnsembleConfig) (*EnsembleAssessor, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("%w: no name", ErrInvalidEnsemble)
+	}
+	if len(config.Components) == 0 {
+		return nil, fmt.Errorf("%w: %s has no components", ErrInvalidEnsemble, config.Name)
+	}
+	if config.PassThreshold == 0 {
+		config.PassThreshold = DefaultEnsemblePassThreshold
+	}
+	if config.PassThreshold < 0 || config.PassThreshold > 1 {
+		return nil, fmt.Errorf("%w: %s pass_threshold %v outside [0, 1]", ErrInvalidEnsemble, config.Name, config.PassThreshold)
+	}
+	if config.MinVerdicts == 0 {
+		config.MinVerdicts = 1
+	}
+	if config.MinVerdicts < 0 || config.MinVerdicts > len(config.Components) {
+		return nil, fmt.Errorf("%w: %s min_verdicts %d outside [1, %d]", ErrInvalidEnsemble, config.Name, config.MinVerdicts, len(config.Components))
+	}
+
+	components := make([]EnsembleComponent, len(config.Components))
+	seen := make(map[string]bool)
+	for i, component := range config.Components {
+		if component.Assessor == nil {
+			return nil, fmt.Errorf("%w: component %d has no assessor", ErrInvalidEnsemble, i+1)
+		}
+		if component.Name == "" {
+			component.Name = assessorName(component.Assessor, i)
+		}
+		if seen[component.Name] {
+			return nil, fmt.Errorf("%w: component %q listed twice (give it a name)", ErrInvalidEnsemble, component.Name)
+		}
+		seen[component.Name] = true
+		if component.Weight <= 0 {
+			return nil, fmt.Errorf("%w: component %q needs a positive weight", ErrInvalidEnsemble, component.Name)
+		}
+		if component.VetoBelow < 0 || component.VetoBelow > 1 {
+			return nil, fmt.Errorf("%w: component %q veto_below %v outside [0, 1]", ErrInvalidEnsemble, component.Name, component.VetoBelow)
+		}
+		components[i] = component
+	}
+	config.Components = components
+	return &EnsembleAssessor{config: config}, nil
+}
+
+// assessorName labels an unnamed component by its assessor's own name, or its position
+func assessorName(assessor QualityAssessor, index int) string {
+	if named, ok := assessor.(interface{ Name() string }); ok && named.Name() != "" {
+		return named.Name()
+	}
+	return fmt.Sprintf("component-%d", index+1)
+}
+
+// Name identifies the ensemble in quality reports
+func (e *EnsembleAssessor) Name() string {
+	return "ensemble:" + e.config.Name
+}
+
+// Components returns the ensemble's components with their resolved names
+func (e *EnsembleAssessor) Components() []EnsembleComponent {
+	return append([]EnsembleComponent(nil), e.config.Components...)
+}
+
+// AssessQuality scores a response with every component. It cannot abstain: an ensemble
+// without enough verdicts is reported as a rejection with quality 0. CoreValidator uses
+// AssessDetailed, which abstains instead.
+func (e *EnsembleAssessor) AssessQuality(response *MinerResponseMessage) (float64, bool) {
+	report := e.AssessDetailed(response)
+	if report.AbstainReason != "" {
+		return 0, false
+	}
+	return report.Quality, report.Accept
+}
+
+// AssessOrAbstain scores a response with every component, or abstains without enough verdicts
+func (e *EnsembleAssessor) AssessOrAbstain(response *MinerResponseMessage) (float64, bool, string) {
+	report := e.AssessDetailed(response)
+	return report.Quality, report.Accept, report.AbstainReason
+}
+
+// AssessDetailed runs every component in parallel and combines their verdicts
+func (e *EnsembleAssessor) AssessDetailed(response *MinerResponseMessage) *QualityReport {
+	scores := make([]ComponentScore, len(e.config.Components))
+	var wg sync.WaitGroup
+	for i, component := range e.config.Components {
+		wg.Add(1)
+		go func(i int, component EnsembleComponent) {
+			defer wg.Done()
+			verdict := runQualityAssessor(component.Assessor, response)
+			scores[i] = ComponentScore{
+				Name:          component.Name,
+				Weight:        component.Weight,
+				Quality:       verdict.quality,
+				Accept:        verdict.accept,
+				AbstainReason: verdict.abstainReason,
+				Report:        verdict.report,
+			}
+		}(i, component)
+	}
+	wg.Wait()
+
+	report := &QualityReport{Assessor: e.Name(), Components: scores}
+	var weighted, totalWeight float64
+	var vetoes, abstentions, rationales []string
+	verdicts := 0
+	for i := range scores {
+		score, component := &scores[i], e.config.Components[i]
+		if score.AbstainReason != "" {
+			abstentions = append(abstentions, fmt.Sprintf("%s: %s", score.Name, score.AbstainReason))
+			continue
+		}
+		verdicts++
+		weighted += component.Weight * score.Quality
+		totalWeight += component.Weight
+
+		if (component.VetoOnReject && !score.Accept) || (component.VetoBelow > 0 && score.Quality < component.VetoBelow) {
+			score.Vetoed = true
+			vetoes = append(vetoes, score.Name)
+		}
+		if score.Report != nil && score.Report.Rationale != "" {
+			rationales = append(rationales, fmt.Sprintf("%s: %s", score.Name, score.Report.Rationale))
+		}
+	}
+	report.Rationale = strings.Join(rationales, "; ")
+	if totalWeight > 0 {
+		report.Quality = clampQuality(weighted / totalWeight)
+	}
+
+	switch {
+	case len(vetoes) > 0:
+		// A hard failure is decisive even if other components abstained
+		fmt.Printf("🧩 %s: %s rejected by veto (%s), quality %.2f from %d/%d verdicts\n",
+			e.Name(), response.RequestID, strings.Join(vetoes, ", "), report.Quality, verdicts, len(scores))
+	case verdicts < e.config.MinVerdicts:
+		report.Quality = 0
+		report.AbstainReason = fmt.Sprintf("%d of %d components gave a verdict (%d required): %s",
+			verdicts, len(scores), e.config.MinVerdicts, strings.Join(abstentions, "; "))
+		fmt.Printf("🧩 %s: abstaining on %s - %s\n", e.Name(), response.RequestID, report.AbstainReason)
+	default:
+		report.Accept = report.Quality >= e.config.PassThreshold
+		fmt.Printf("🧩 %s: %s quality %.2f from %d/%d verdicts (accept=%v)\n",
+			e.Name(), response.RequestID, report.Quality, verdicts, len(scores), report.Accept)
+	}
+	return report
+}
@@ -0,0 +1,178 @@
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// fixedAssessor gives the same verdict on every response, or abstains with a reason
+type fixedAssessor struct {
+	name    string
+	quality float64
+	accept  bool
+	abstain string
+}
+
+func (a fixedAssessor) Name() string { return a.name }
+
+func (a fixedAssessor) AssessQuality(response *MinerResponseMessage) (float64, bool) {
+	return a.quality, a.accept
+}
+
+func (a fixedAssessor) AssessOrAbstain(response *MinerResponseMessage) (float64, bool, string) {
+	return a.quality, a.accept, a.abstain
+}
+
+var (
+	strongAssessor  = fixedAssessor{name: "strong", quality: 0.9, accept: true}
+	weakAssessor    = fixedAssessor{name: "weak", quality: 0.5, accept: false}
+	absentAssessor  = fixedAssessor{name: "absent", abstain: "model unavailable"}
+	ensembleRequest = judgedResponse("req-1")
+)
+
+func newTestEnsemble(t *testing.T, config EnsembleConfig) *EnsembleAssessor {
+	t.Helper()
+	if config.Name == "" {
+		config.Name = "test"
+	}
+	ensemble, err := NewEnsembleAssessor(config)
+	if err != nil {
+		t.Fatalf("NewEnsembleAssessor: %v", err)
+	}
+	return ensemble
+}
+
+func TestEnsembleWeightedQuality(t *testing.T) {
+	ensemble := newTestEnsemble(t, EnsembleConfig{Components: []EnsembleComponent{
+		{Assessor: strongAssessor, Weight: 3},
+		{Assessor: weakAssessor, Weight: 1},
+		{Assessor: absentAssessor, Weight: 10}, // Left out, the others renormalized
+	}})
+
+	report := ensemble.AssessDetailed(ensembleRequest)
+	if math.Abs(report.Quality-0.8) > 1e-9 || !report.Accept || report.AbstainReason != "" {
+		t.Fatalf("report = %+v, want quality 0.8 (3×0.9 + 1×0.5 over 4) accepted", report)
+	}
+	if report.Assessor != "ensemble:test" || len(report.Components) != 3 {
+		t.Fatalf("report from %s with %d components, want every component recorded", report.Assessor, len(report.Components))
+	}
+	for i, want := range []string{"strong", "weak", "absent"} {
+		if score := report.Components[i]; score.Name != want || score.Vetoed {
+			t.Fatalf("component %d = %+v, want %s without a veto", i, score, want)
+		}
+	}
+	if report.Components[2].AbstainReason == "" {
+		t.Fatal("abstaining component not recorded as abstaining")
+	}
+
+	strict := newTestEnsemble(t, EnsembleConfig{PassThreshold: 0.85, Components: []EnsembleComponent{
+		{Assessor: strongAssessor, Weight: 3},
+		{Assessor: weakAssessor, Weight: 1},
+	}})
+	if quality, accept := strict.AssessQuality(ensembleRequest); accept || math.Abs(quality-0.8) > 1e-9 {
+		t.Fatalf("quality %v under pass threshold 0.85 = accept %v, want rejected", quality, accept)
+	}
+}
+
+func TestEnsembleVetoes(t *testing.T) {
+	// One hard failure rejects an otherwise passing mix
+	ensemble := newTestEnsemble(t, EnsembleConfig{Components: []EnsembleComponent{
+		{Assessor: strongAssessor, Weight: 3},
+		{Assessor: weakAssessor, Weight: 1, VetoOnReject: true},
+	}})
+	report := ensemble.AssessDetailed(ensembleRequest)
+	if report.Accept || !report.Components[1].Vetoed || report.Components[0].Vetoed {
+		t.Fatalf("report = %+v, want the weak component's rejection to veto", report)
+	}
+
+	// A quality floor vetoes even a component that accepted
+	ensemble = newTestEnsemble(t, EnsembleConfig{Components: []EnsembleComponent{
+		{Assessor: strongAssessor, Weight: 1, VetoBelow: 0.95},
+		{Assessor: fixedAssessor{name: "perfect", quality: 1, accept: true}, Weight: 1},
+	}})
+	if report := ensemble.AssessDetailed(ensembleRequest); report.Accept || !report.Components[0].Vetoed {
+		t.Fatalf("report = %+v, want quality 0.9 below veto_below 0.95 to veto", report)
+	}
+
+	// A veto is decisive even without enough verdicts
+	ensemble = newTestEnsemble(t, EnsembleConfig{MinVerdicts: 2, Components: []EnsembleComponent{
+		{Assessor: weakAssessor, Weight: 1, VetoOnReject: true},
+		{Assessor: absentAssessor, Weight: 1},
+	}})
+	if _, accept, abstain := ensemble.AssessOrAbstain(ensembleRequest); accept || abstain != "" {
+		t.Fatalf("veto with 1/2 verdicts = accept %v, abstain %q, want a rejection", accept, abstain)
+	}
+}
+
+func TestEnsembleAbstainsWithoutVerdicts(t *testing.T) {
+	ensemble := newTestEnsemble(t, EnsembleConfig{MinVerdicts: 2, Components: []EnsembleComponent{
+		{Assessor: strongAssessor, Weight: 1},
+		{Assessor: absentAssessor, Weight: 1},
+	}})
+
+	quality, accept, abstain := ensemble.AssessOrAbstain(ensembleRequest)
+	if abstain == "" || quality != 0 || accept {
+		t.Fatalf("1/2 verdicts with min_verdicts 2 = %v, %v, %q, want an abstention", quality, accept, abstain)
+	}
+
+	// AssessQuality cannot abstain, so it rejects
+	if quality, accept := ensemble.AssessQuality(ensembleRequest); quality != 0 || accept {
+		t.Fatalf("AssessQuality without enough verdicts = %v, %v, want 0, false", quality, accept)
+	}
+}
+
+func TestNewEnsembleAssessorValidates(t *testing.T) {
+	valid := EnsembleComponent{Assessor: strongAssessor, Weight: 1}
+	for name, config := range map[string]EnsembleConfig{
+		"no name":            {Components: []EnsembleComponent{valid}},
+		"no components":      {Name: "test"},
+		"threshold above 1":  {Name: "test", PassThreshold: 1.5, Components: []EnsembleComponent{valid}},
+		"too many verdicts":  {Name: "test", MinVerdicts: 2, Components: []EnsembleComponent{valid}},
+		"no assessor":        {Name: "test", Components: []EnsembleComponent{{Weight: 1}}},
+		"zero weight":        {Name: "test", Components: []EnsembleComponent{{Assessor: strongAssessor}}},
+		"veto_below above 1": {Name: "test", Components: []EnsembleComponent{{Assessor: strongAssessor, Weight: 1, VetoBelow: 2}}},
+		"same name twice":    {Name: "test", Components: []EnsembleComponent{valid, valid}},
+	} {
+		if _, err := NewEnsembleAssessor(config); !errors.Is(err, ErrInvalidEnsemble) {
+			t.Errorf("%s: NewEnsembleAssessor = %v, want %v", name, err, ErrInvalidEnsemble)
+		}
+	}
+
+	// The same assessor twice is fine once the components are named apart
+	ensemble := newTestEnsemble(t, EnsembleConfig{Components: []EnsembleComponent{valid, {Name: "second opinion", Assessor: strongAssessor, Weight: 1}}})
+	if components := ensemble.Components(); components[0].Name != "strong" || components[1].Name != "second opinion" {
+		t.Fatalf("components = %+v, want the assessor's name and the given one", components)
+	}
+}
+
+func TestEnsembleSpecBuild(t *testing.T) {
+	assessors := map[string]QualityAssessor{"strong": strongAssessor, "weak": weakAssessor}
+	resolve := func(kind string) (QualityAssessor, error) {
+		if assessor, ok := assessors[kind]; ok {
+			return assessor, nil
+		}
+		return nil, fmt.Errorf("unknown assessor %q", kind)
+	}
+
+	spec := EnsembleSpec{Name: "file", PassThreshold: 0.6, Components: []EnsembleComponentSpec{
+		{Assessor: "strong", Weight: 1},
+		{Assessor: "weak", Name: "strict", Weight: 1, VetoOnReject: true},
+	}}
+	ensemble, err := spec.Build(resolve)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if components := ensemble.Components(); components[1].Name != "strict" || !components[1].VetoOnReject {
+		t.Fatalf("components = %+v, want the spec's name and veto kept", components)
+	}
+	if report := ensemble.AssessDetailed(ensembleRequest); report.Accept || !report.Components[1].Vetoed {
+		t.Fatalf("report = %+v, want the strict component to veto", report)
+	}
+
+	spec.Components = append(spec.Components, EnsembleComponentSpec{Assessor: "oracle", Weight: 1})
+	if _, err := spec.Build(resolve); !errors.Is(err, ErrInvalidEnsemble) {
+		t.Fatalf("Build with an unknown assessor = %v, want %v", err, ErrInvalidEnsemble)
+	}
+}
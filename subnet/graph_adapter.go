@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,21 +22,41 @@ import (
 // EpochFinalizedCallback is called when an epoch is finalized
 type EpochFinalizedCallback func(epochNumber int, subnetID string, epochData *EpochData)
 
+// RoundCompletedCallback is called with a copy of each round's data when the round completes
+type RoundCompletedCallback func(round RoundData)
+
 // RoundData contains detailed information about a single round
 type RoundData struct {
 	RoundNumber     int                 `json:"roundNumber"`
 	RequestID       string              `json:"requestId"`
+	MinerID         string              `json:"minerId,omitempty"`      // Miner that served the task
+	MinerAddress    string              `json:"minerAddress,omitempty"` // Reward address of that miner
 	UserInput       string              `json:"userInput"`
-	MinerOutput     string              `json:"minerOutput"`
+	MinerOutput     string              `json:"minerOutput"` // Output commitment (output_ready) or failure reason; never the output text
 	MinerOutputType string              `json:"minerOutputType"`
 	InfoRequest     string              `json:"infoRequest,omitempty"`
 	InfoResponse    string              `json:"infoResponse,omitempty"`
+	Dialog          []DialogTurn        `json:"dialog,omitempty"` // Every clarification turn, in order
+	StreamedChunks  int                 `json:"streamedChunks,omitempty"` // Chunks the output was streamed in (0: not streamed)
 	ConsensusResult string              `json:"consensusResult"`
 	UserFeedback    string              `json:"userFeedback"`
 	UserAccept      bool                `json:"userAccept"`
 	FinalResult     string              `json:"finalResult"`
 	VLCClockState   map[int]int         `json:"vlcClockState"`
 	Success         bool                `json:"success"`
+	Certificate     *TaskCertificate    `json:"certificate,omitempty"` // Validator quorum certificate (decided rounds only)
+	Assessments     []VoteAssessment    `json:"assessments,omitempty"` // Each validator's judgement, with its assessor's rationale
+}
+
+// VoteAssessment is one validator's judgement of a round's output, as published with the epoch
+type VoteAssessment struct {
+	ValidatorID   string  `json:"validatorId"`
+	Quality       float64 `json:"quality"`
+	Accept        bool    `json:"accept"`
+	Abstain       bool    `json:"abstain,omitempty"`
+	AbstainReason string  `json:"abstainReason,omitempty"`
+	Assessor      string  `json:"assessor,omitempty"`  // From the vote's quality report, if any
+	Rationale     string  `json:"rationale,omitempty"` // Judge rationale, if the assessor gave one
 }
 
 // EpochData contains the data for a completed epoch
@@ -46,6 +68,8 @@ type EpochData struct {
 	VLCClockState     map[int]int         `json:"vlcClockState"`
 	EpochEventID      string              `json:"epochEventId"`
 	ParentRoundEventID string             `json:"parentRoundEventId"`
+	Certificate       *EpochCertificate   `json:"certificate,omitempty"` // Quorum certificates of the epoch's decided rounds
+	SuccessfulMiners  []string            `json:"successfulMiners,omitempty"` // Reward address per successful round, in round order
 }
 
 // SubnetGraphAdapter adapts PoCW subnet events for causal graph visualization.
@@ -63,7 +87,7 @@ type EpochData struct {
 // VLC Integration:
 //   - Each event includes VLC clock state for causal ordering
 //   - Parent-child relationships reflect VLC causality
-//   - Only events from VLC participants (Miner=1, Validator-1=2) have full VLC data
+//   - Only events from VLC participants (miners=1 and MinerClockIDFor, acting leader validator=2+index) have full VLC data
 type SubnetGraphAdapter struct {
 	EventGraph        *dgraph.EventGraph     // Dgraph event graph for visualization
 	SubnetID          string                 // Subnet identifier
@@ -75,8 +99,10 @@ type SubnetGraphAdapter struct {
 	genesisEventID    string                 // Genesis state event ID
 	roundsInEpoch     int                    // Counter for rounds within current epoch
 	epochCallback     EpochFinalizedCallback // Callback triggered when epoch is finalized
+	roundCallback     RoundCompletedCallback // Callback triggered when a round completes
 	bridgeURL         string                 // URL of the JavaScript bridge service
 	currentRounds     map[string]*RoundData  // Track detailed data for rounds in current epoch
+	minerAddresses    map[string]string      // Miner ID -> reward address
 }
 
 // NewSubnetGraphAdapter creates a new graph adapter for subnet visualization
@@ -92,6 +118,7 @@ func NewSubnetGraphAdapter(subnetID string, nodeID int, nodeAddr string) *Subnet
 		roundsInEpoch:    0,
 		bridgeURL:        "", // No default bridge URL - must be explicitly set
 		currentRounds:    make(map[string]*RoundData),
+		minerAddresses:   make(map[string]string),
 	}
 	
 	// Create Genesis State immediately
@@ -106,6 +133,21 @@ func (sga *SubnetGraphAdapter) SetEpochFinalizedCallback(callback EpochFinalized
 	sga.epochCallback = callback
 }
 
+// SetRoundCompletedCallback sets the callback triggered when a round completes.
+// It runs with the adapter locked and must not call back into it.
+func (sga *SubnetGraphAdapter) SetRoundCompletedCallback(callback RoundCompletedCallback) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	sga.roundCallback = callback
+}
+
+// SetMinerAddress sets the reward address credited for rounds served by a miner
+func (sga *SubnetGraphAdapter) SetMinerAddress(minerID, address string) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+	sga.minerAddresses[minerID] = address
+}
+
 // SetBridgeURL sets the URL for the JavaScript bridge service
 func (sga *SubnetGraphAdapter) SetBridgeURL(url string) {
 	sga.mu.Lock()
@@ -124,6 +166,7 @@ func (sga *SubnetGraphAdapter) sendEpochToBridge(epochData *EpochData) error {
 		"vlcClockState":  epochData.VLCClockState,
 		"epochEventId":   epochData.EpochEventID,
 		"parentRoundEventId": epochData.ParentRoundEventID,
+		"certificate":    epochData.Certificate,
 		"timestamp":      time.Now().Unix(),
 	}
 	
@@ -243,12 +286,20 @@ func (sga *SubnetGraphAdapter) TrackMinerResponse(requestID string, response *Mi
 
 	// Update round data with miner response
 	if round := sga.currentRounds[requestID]; round != nil {
-		if response.OutputType == OutputReady {
-			round.MinerOutput = response.Output
+		round.MinerID = response.Sender
+		round.MinerAddress = sga.minerAddresses[response.Sender]
+		switch response.OutputType {
+		case OutputReady:
+			round.MinerOutput = ResponseCommitment(response) // Published graphs prove the output without revealing it
 			round.MinerOutputType = "output_ready"
-		} else {
+			round.StreamedChunks = len(response.ChunkSizes)
+		case TaskFailed:
+			round.MinerOutput = response.Error
+			round.MinerOutputType = "task_failed"
+		default:
 			round.InfoRequest = response.InfoRequest
 			round.MinerOutputType = "info_request"
+			round.Dialog = append(round.Dialog, DialogTurn{Question: response.InfoRequest})
 		}
 		// Update VLC state
 		for k, v := range vlcToMap(response.VLCClock) {
@@ -258,13 +309,21 @@ func (sga *SubnetGraphAdapter) TrackMinerResponse(requestID string, response *Mi
 
 	// Create semantic event name based on miner response type
 	var eventName, key, value string
-	if response.OutputType == OutputReady {
+	switch response.OutputType {
+	case OutputReady:
 		eventName = "MinerOutput"
 		key = fmt.Sprintf("miner_output_%d", response.InputNumber)
-		value = fmt.Sprintf("Miner provides: %s", response.Output)
-	} else {
+		value = fmt.Sprintf("Miner provides output %s", ResponseCommitment(response))
+	case TaskFailed:
+		eventName = "MinerTaskFailed"
+		key = fmt.Sprintf("miner_failure_%d", response.InputNumber)
+		value = fmt.Sprintf("Miner failed: %s", response.Error)
+	default:
 		eventName = "InfoRequest"
 		key = fmt.Sprintf("info_request_%d", response.InputNumber)
+		if response.Turn > 0 {
+			key = fmt.Sprintf("info_request_%d_turn_%d", response.InputNumber, response.Turn+1)
+		}
 		value = fmt.Sprintf("Miner requests: %s", response.InfoRequest)
 	}
 
@@ -287,14 +346,20 @@ func (sga *SubnetGraphAdapter) TrackMinerResponse(requestID string, response *Mi
 	return eventID
 }
 
-// TrackInfoResponse records user providing additional context (validator VLC increment)
+// TrackInfoResponse records user providing additional context (validator VLC increment).
+// In multi-turn dialogs it is called once per answered question.
 func (sga *SubnetGraphAdapter) TrackInfoResponse(requestID string, additionalInfo string, validatorClock *vlc.Clock, parentEventID string) string {
 	sga.mu.Lock()
 	defer sga.mu.Unlock()
 
 	// Update round data with info response
+	turn := 1
 	if round := sga.currentRounds[requestID]; round != nil {
 		round.InfoResponse = additionalInfo
+		if n := len(round.Dialog); n > 0 {
+			round.Dialog[n-1].Answer = additionalInfo
+			turn = n
+		}
 		// Update VLC state
 		for k, v := range vlcToMap(validatorClock) {
 			round.VLCClockState[k] = v
@@ -303,6 +368,9 @@ func (sga *SubnetGraphAdapter) TrackInfoResponse(requestID string, additionalInf
 
 	eventName := "InfoResponse"
 	key := fmt.Sprintf("info_response_%s", requestID)
+	if turn > 1 {
+		key = fmt.Sprintf("info_response_%s_turn_%d", requestID, turn)
+	}
 	value := fmt.Sprintf("User clarifies: %s", additionalInfo)
 
 	clockMap := vlcToMap(validatorClock)
@@ -323,6 +391,44 @@ func (sga *SubnetGraphAdapter) TrackInfoResponse(requestID string, additionalInf
 	return eventID
 }
 
+// RecordTaskCertificate attaches the validators' quorum certificate to a round in the current epoch
+func (sga *SubnetGraphAdapter) RecordTaskCertificate(requestID string, cert *TaskCertificate) {
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+
+	if round := sga.currentRounds[requestID]; round != nil {
+		round.Certificate = cert
+	}
+}
+
+// RecordVoteAssessments attaches the validators' judgements to a round in the current epoch,
+// ordered by validator ID
+func (sga *SubnetGraphAdapter) RecordVoteAssessments(requestID string, votes []*ValidatorVoteMessage) {
+	assessments := make([]VoteAssessment, 0, len(votes))
+	for _, vote := range votes {
+		assessment := VoteAssessment{
+			ValidatorID:   vote.ValidatorID,
+			Quality:       vote.Quality,
+			Accept:        vote.Accept,
+			Abstain:       vote.Abstain,
+			AbstainReason: vote.AbstainReason,
+		}
+		if vote.Report != nil {
+			assessment.Assessor = vote.Report.Assessor
+			assessment.Rationale = vote.Report.Rationale
+		}
+		assessments = append(assessments, assessment)
+	}
+	sort.Slice(assessments, func(i, j int) bool { return assessments[i].ValidatorID < assessments[j].ValidatorID })
+
+	sga.mu.Lock()
+	defer sga.mu.Unlock()
+
+	if round := sga.currentRounds[requestID]; round != nil {
+		round.Assessments = assessments
+	}
+}
+
 // TrackRoundComplete records round completion with comprehensive workflow result (validator VLC increment)
 func (sga *SubnetGraphAdapter) TrackRoundComplete(requestID string, roundNum int, validatorClock *vlc.Clock, consensusResult string, userFeedback string, userAccept bool, finalResult string, parentEventID string) string {
 	sga.mu.Lock()
@@ -339,6 +445,15 @@ func (sga *SubnetGraphAdapter) TrackRoundComplete(requestID string, roundNum int
 		for k, v := range vlcToMap(validatorClock) {
 			round.VLCClockState[k] = v
 		}
+
+		if sga.roundCallback != nil {
+			completed := *round
+			completed.VLCClockState = make(map[int]int, len(round.VLCClockState))
+			for k, v := range round.VLCClockState {
+				completed.VLCClockState[k] = v
+			}
+			sga.roundCallback(completed)
+		}
 	}
 
 	// Determine semantic event name based on final outcome
@@ -390,6 +505,22 @@ func (sga *SubnetGraphAdapter) TrackRoundComplete(requestID string, roundNum int
 	}
 }
 
+// ImportRound records a round that a remote leader ran and chains its completion into this
+// adapter's epoch, so the epoch submission includes it. The round's detailed events stay in
+// the leader's graph; the miner is credited at the reward address known here.
+func (sga *SubnetGraphAdapter) ImportRound(round RoundData, roundNum int, validatorClock *vlc.Clock) string {
+	sga.mu.Lock()
+	round.RoundNumber = sga.roundsInEpoch + 1
+	round.MinerAddress = sga.minerAddresses[round.MinerID]
+	round.VLCClockState = vlcToMap(validatorClock)
+	sga.currentRounds[round.RequestID] = &round
+	parentEventID := sga.lastEventInChain
+	sga.mu.Unlock()
+
+	return sga.TrackRoundComplete(round.RequestID, roundNum, validatorClock, round.ConsensusResult,
+		round.UserFeedback, round.UserAccept, round.FinalResult, parentEventID)
+}
+
 // createNextRoundConnector creates transition nodes between rounds within an epoch
 func (sga *SubnetGraphAdapter) createNextRoundConnector(validatorClock *vlc.Clock, parentRoundEventID string) string {
 	eventName := "NextRound"
@@ -416,10 +547,10 @@ func (sga *SubnetGraphAdapter) createEpochFinalization(validatorClock *vlc.Clock
 	eventName := "EpochFinalized"
 	key := fmt.Sprintf("epoch_%d_finalized", sga.epochCount)
 	
-	value := fmt.Sprintf("Epoch %d: Finalized with 3 rounds | VLC State: Miner=%d, Validator=%d", 
-		sga.epochCount, 
-		validatorClock.Values[1], 
-		validatorClock.Values[2])
+	value := fmt.Sprintf("Epoch %d: Finalized with 3 rounds | VLC State: Miners=%s, Validators=%s",
+		sga.epochCount,
+		minerClockSummary(validatorClock),
+		validatorClockSummary(validatorClock))
 	
 	clockMap := vlcToMap(validatorClock)
 	
@@ -454,6 +585,12 @@ func (sga *SubnetGraphAdapter) createEpochFinalization(validatorClock *vlc.Clock
 			}
 		}
 		
+		// Bundle round certificates in round order into the epoch certificate
+		epochData.Certificate = newEpochCertificateFromRounds(sga.SubnetID, sga.epochCount, epochData.DetailedRounds)
+
+		// Credit each successful round to the miner that served it
+		epochData.SuccessfulMiners = successfulMinersFromRounds(epochData.DetailedRounds)
+
 		// Copy VLC clock state
 		for nodeID, value := range validatorClock.Values {
 			epochData.VLCClockState[int(nodeID)] = int(value)
@@ -481,6 +618,38 @@ func (sga *SubnetGraphAdapter) createEpochFinalization(validatorClock *vlc.Clock
 	return epochEventID
 }
 
+// newEpochCertificateFromRounds builds an epoch certificate from the rounds that carry one
+func newEpochCertificateFromRounds(subnetID string, epochNumber int, rounds []RoundData) *EpochCertificate {
+	ordered := make([]RoundData, len(rounds))
+	copy(ordered, rounds)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].RoundNumber < ordered[j].RoundNumber })
+
+	tasks := make([]TaskCertificate, 0, len(ordered))
+	for _, round := range ordered {
+		if round.Certificate != nil {
+			tasks = append(tasks, *round.Certificate)
+		}
+	}
+	return NewEpochCertificate(subnetID, epochNumber, tasks)
+}
+
+// successfulMinersFromRounds lists the reward address of every successful round in round order.
+// A miner appears once per successful round, so PoCWVerifier (which splits the epoch's
+// successful tasks evenly across the list) credits each miner exactly its own tasks.
+func successfulMinersFromRounds(rounds []RoundData) []string {
+	ordered := make([]RoundData, len(rounds))
+	copy(ordered, rounds)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].RoundNumber < ordered[j].RoundNumber })
+
+	var miners []string
+	for _, round := range ordered {
+		if round.Success && round.MinerAddress != "" {
+			miners = append(miners, round.MinerAddress)
+		}
+	}
+	return miners
+}
+
 // CommitGraph commits all tracked events to Dgraph for visualization
 func (sga *SubnetGraphAdapter) CommitGraph() error {
 	sga.mu.Lock()
@@ -509,6 +678,33 @@ func (sga *SubnetGraphAdapter) GetEventCount() int {
 	return len(sga.EventGraph.Events)
 }
 
+// validatorClockSummary formats the validator entries of a clock (e.g., "V1=4 V2=6")
+func validatorClockSummary(clock *vlc.Clock) string {
+	var entries []string
+	for id, value := range vlcToMap(clock) {
+		if uint64(id) > MinerClockID && !IsMinerClockID(uint64(id)) {
+			entries = append(entries, fmt.Sprintf("V%d=%d", uint64(id)-MinerClockID, value))
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, " ")
+}
+
+// minerClockSummary formats the miner entries of a clock (e.g., "M1=4 M2=6")
+func minerClockSummary(clock *vlc.Clock) string {
+	var entries []string
+	for id, value := range vlcToMap(clock) {
+		switch {
+		case uint64(id) == MinerClockID:
+			entries = append(entries, fmt.Sprintf("M1=%d", value))
+		case IsMinerClockID(uint64(id)):
+			entries = append(entries, fmt.Sprintf("M%d=%d", uint64(id)-MinerClockIDBase+1, value))
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, " ")
+}
+
 // vlcToMap converts VLC clock to map format for JSON serialization
 func vlcToMap(clock *vlc.Clock) map[int]int {
 	if clock == nil {
@@ -0,0 +1,260 @@
+// Package subnet - HTTP Network Transport
+//
+// This file implements Transport over HTTP so a miner and its validators can run as
+// separate OS processes (on one host or many). Each process listens on one address and
+// serves all participants registered locally; peers are addressed by participant ID
+// through a static peer table (participant ID -> base URL).
+package subnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// subnetMessagePath is the HTTP endpoint that accepts subnet envelopes
+	subnetMessagePath = "/subnet/message"
+
+	// maxEnvelopeBytes bounds an envelope (request or reply) read from the network
+	maxEnvelopeBytes = 16 << 20
+
+	// dialTimeout bounds connecting to a peer; the exchange itself is bounded by the caller's context
+	dialTimeout = 10 * time.Second
+)
+
+// HTTPTransport exchanges subnet envelopes as JSON over HTTP POST.
+//
+// Routing:
+//   - Participants registered locally are served by this process and short-circuited on Send
+//   - Remote participants are reached at peers[participantID] + "/subnet/message"
+//
+// Envelopes larger than 16 MiB are refused. The envelope is bound to the message it carries
+// (see Envelope.DecodeMessage) and must be addressed to a participant served here.
+// A handler error is returned to the caller as an HTTP 502 with the error text.
+// Exchanges have no transport-wide deadline: a miner task or a delegated round may run for
+// minutes, so each Send is bounded by its context.
+type HTTPTransport struct {
+	listenAddr string       // Address to listen on (e.g., ":7001"); empty for client-only use
+	client     *http.Client // HTTP client for outgoing requests
+
+	mu       sync.RWMutex
+	local    map[string]MessageHandler // Locally served participants
+	peers    map[string]string         // Remote participant ID -> base URL
+	server   *http.Server              // Started lazily on first Register
+	listener net.Listener
+}
+
+// NewHTTPTransport creates an HTTP transport that serves local participants on listenAddr
+func NewHTTPTransport(listenAddr string) *HTTPTransport {
+	return &HTTPTransport{
+		listenAddr: listenAddr,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				DialContext:         (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext,
+				TLSHandshakeTimeout: dialTimeout,
+				MaxIdleConnsPerHost: 8,
+			},
+		},
+		local: make(map[string]MessageHandler),
+		peers: make(map[string]string),
+	}
+}
+
+// AddPeer registers the base URL (e.g., "http://localhost:7002") for a remote participant
+func (t *HTTPTransport) AddPeer(participantID, baseURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.peers[participantID] = baseURL
+}
+
+// Addr returns the address the transport is listening on (empty before the server starts)
+func (t *HTTPTransport) Addr() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.listener == nil {
+		return ""
+	}
+	return t.listener.Addr().String()
+}
+
+// Register serves a participant from this process, starting the HTTP listener if needed
+func (t *HTTPTransport) Register(participantID string, handler MessageHandler) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.local[participantID]; exists {
+		return fmt.Errorf("%w: %s", ErrParticipantRegistered, participantID)
+	}
+	t.local[participantID] = handler
+
+	if t.server == nil && t.listenAddr != "" {
+		if err := t.startLocked(); err != nil {
+			delete(t.local, participantID)
+			return err
+		}
+	}
+	return nil
+}
+
+// startLocked binds the listener and starts serving (caller holds t.mu)
+func (t *HTTPTransport) startLocked() error {
+	listener, err := net.Listen("tcp", t.listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", t.listenAddr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(subnetMessagePath, t.handleMessage)
+	mux.HandleFunc("/subnet/health", t.handleHealth)
+
+	t.listener = listener
+	t.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := t.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("❌ Subnet transport on %s stopped: %v\n", t.listenAddr, err)
+		}
+	}()
+
+	fmt.Printf("🌐 Subnet transport listening on %s\n", listener.Addr().String())
+	return nil
+}
+
+// Send delivers an envelope to a local or remote participant
+func (t *HTTPTransport) Send(ctx context.Context, participantID string, env *Envelope) (*Envelope, error) {
+	t.mu.RLock()
+	handler, isLocal := t.local[participantID]
+	baseURL, isPeer := t.peers[participantID]
+	t.mu.RUnlock()
+
+	if isLocal {
+		reply, err := handler(ctx, copyEnvelope(env))
+		if err != nil {
+			return nil, err
+		}
+		return copyEnvelope(reply), nil
+	}
+	if !isPeer {
+		return nil, fmt.Errorf("%w: %s (no peer address)", ErrParticipantUnreachable, participantID)
+	}
+
+	outgoing := copyEnvelope(env)
+	outgoing.To = participantID
+
+	body, err := json.Marshal(outgoing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal envelope: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+subnetMessagePath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrParticipantUnreachable, participantID, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxEnvelopeBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reply from %s: %v", participantID, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil, nil
+	case http.StatusOK:
+		var reply Envelope
+		if err := json.Unmarshal(respBody, &reply); err != nil {
+			return nil, fmt.Errorf("invalid reply from %s: %v", participantID, err)
+		}
+		return &reply, nil
+	default:
+		return nil, fmt.Errorf("%s returned status %d: %s", participantID, resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+}
+
+// handleMessage dispatches an incoming envelope to the addressed local participant
+func (t *HTTPTransport) handleMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var env Envelope
+	r.Body = http.MaxBytesReader(w, r.Body, maxEnvelopeBytes)
+	if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Envelope too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid envelope", http.StatusBadRequest)
+		return
+	}
+
+	t.mu.RLock()
+	handler, exists := t.local[env.To]
+	t.mu.RUnlock()
+	if !exists {
+		http.Error(w, fmt.Sprintf("unknown participant %q", env.To), http.StatusNotFound)
+		return
+	}
+
+	reply, err := handler(r.Context(), &env)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if reply == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// handleHealth reports which participants this process serves
+func (t *HTTPTransport) handleHealth(w http.ResponseWriter, r *http.Request) {
+	t.mu.RLock()
+	participants := make([]string, 0, len(t.local))
+	for id := range t.local {
+		participants = append(participants, id)
+	}
+	t.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":       "healthy",
+		"participants": participants,
+	})
+}
+
+// Close shuts down the HTTP listener
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	server := t.server
+	t.server = nil
+	t.listener = nil
+	t.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
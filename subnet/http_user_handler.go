@@ -0,0 +1,366 @@
+// Package subnet - HTTP Long-Poll User Handler
+//
+// This file implements HTTPUserHandler, an InteractiveUserHandler that puts clarification
+// questions and miner outputs to a real end user over HTTP. A client (web page, CLI, chat
+// bridge) long-polls for open prompts and posts the user's replies; the round waits for
+// each reply up to a timeout.
+//
+// Endpoints:
+//   - GET  /user/prompts?wait=30s: open prompts as a JSON array, waiting up to wait (max 60s)
+//     for one to appear; an empty array when none did
+//   - POST /user/reply: a UserReply; 404 for unknown or expired prompts, 409 if already answered
+//
+// With a Token configured, every request must carry "Authorization: Bearer <token>".
+// Start refuses to serve a non-loopback address without one, and reply bodies are capped
+// at maxUserReplyBytes.
+package subnet
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	userPromptsPath = "/user/prompts"
+	userReplyPath   = "/user/reply"
+
+	// DefaultUserResponseTimeout is how long a round waits for the user by default
+	DefaultUserResponseTimeout = 5 * time.Minute
+
+	maxUserPollWait   = 60 * time.Second // Longest a poll may wait for a prompt
+	maxUserReplyBytes = 64 << 10         // Largest reply body accepted
+)
+
+// ErrUnauthenticatedUserEndpoint is returned by Start for a non-loopback address without a token
+var ErrUnauthenticatedUserEndpoint = errors.New("user endpoint reachable beyond loopback needs a token")
+
+// HTTPUserConfig configures an HTTPUserHandler
+type HTTPUserConfig struct {
+	ListenAddr string        // Address to serve on (e.g., ":8090"); empty to mount Handler() elsewhere
+	Token      string        // Bearer token clients must present (empty: no authentication, loopback only)
+	Timeout    time.Duration // How long a round waits for each reply (zero: DefaultUserResponseTimeout)
+}
+
+// HTTPUserHandler relays prompts to the end user over long-poll HTTP. Safe for concurrent use;
+// one handler can serve every local validator, since only the round leader prompts the user.
+type HTTPUserHandler struct {
+	config HTTPUserConfig
+
+	mu       sync.Mutex
+	pending  map[string]*pendingPrompt // Open prompts by ID
+	sequence uint64                    // Orders prompts for polling
+	changed  chan struct{}             // Closed (and replaced) when a prompt is opened
+	server   *http.Server
+	listener net.Listener
+}
+
+// pendingPrompt is a prompt waiting for the user's reply
+type pendingPrompt struct {
+	prompt   UserPrompt
+	sequence uint64
+	reply    chan UserReply // Buffered; receives exactly one reply
+}
+
+// NewHTTPUserHandler creates the handler; call Start to serve on config.ListenAddr
+func NewHTTPUserHandler(config HTTPUserConfig) *HTTPUserHandler {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultUserResponseTimeout
+	}
+	return &HTTPUserHandler{
+		config:  config,
+		pending: make(map[string]*pendingPrompt),
+		changed: make(chan struct{}),
+	}
+}
+
+// Handler returns the HTTP handler serving the user endpoints
+func (h *HTTPUserHandler) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(userPromptsPath, h.handlePrompts)
+	mux.HandleFunc(userReplyPath, h.handleReply)
+	return mux
+}
+
+// Start serves the user endpoints on config.ListenAddr. Without a token the address must be
+// loopback, so only local clients can answer for the user.
+func (h *HTTPUserHandler) Start() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.server != nil {
+		return nil
+	}
+	if h.config.Token == "" && !isLoopbackAddr(h.config.ListenAddr) {
+		return fmt.Errorf("%w: set a token or listen on 127.0.0.1 instead of %q", ErrUnauthenticatedUserEndpoint, h.config.ListenAddr)
+	}
+	listener, err := net.Listen("tcp", h.config.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", h.config.ListenAddr, err)
+	}
+	h.listener = listener
+	h.server = &http.Server{Handler: h.Handler()}
+
+	go func() {
+		if err := h.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("❌ User endpoint on %s stopped: %v\n", h.config.ListenAddr, err)
+		}
+	}()
+
+	fmt.Printf("🙋 User endpoint listening on %s (GET %s, POST %s)\n", listener.Addr().String(), userPromptsPath, userReplyPath)
+	return nil
+}
+
+// Addr returns the address the handler is listening on (empty before Start)
+func (h *HTTPUserHandler) Addr() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.listener == nil {
+		return ""
+	}
+	return h.listener.Addr().String()
+}
+
+// Close stops serving; rounds still waiting time out as usual
+func (h *HTTPUserHandler) Close() error {
+	h.mu.Lock()
+	server := h.server
+	h.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	return server.Close()
+}
+
+// SimulateUserInteraction asks the user to review an output. A user who does not answer in
+// time is taken to reject it.
+func (h *HTTPUserHandler) SimulateUserInteraction(inputNumber int, output string) (bool, string) {
+	prompt := &UserPrompt{
+		Kind:        PromptReview,
+		RequestID:   fmt.Sprintf("input-%d", inputNumber),
+		InputNumber: inputNumber,
+		Output:      output,
+	}
+	accept, feedback, err := h.ReviewOutput(context.Background(), prompt)
+	if err != nil {
+		return false, err.Error()
+	}
+	return accept, feedback
+}
+
+// ReviewOutput waits for the user to accept or reject a miner output
+func (h *HTTPUserHandler) ReviewOutput(ctx context.Context, prompt *UserPrompt) (bool, string, error) {
+	reply, err := h.ask(ctx, prompt)
+	if err != nil {
+		return false, "", err
+	}
+	return *reply.Accept, reply.Feedback, nil
+}
+
+// RequestClarification waits for the user to answer a miner's question
+func (h *HTTPUserHandler) RequestClarification(ctx context.Context, prompt *UserPrompt) (string, error) {
+	reply, err := h.ask(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	return reply.Answer, nil
+}
+
+// ask opens a prompt and waits for its reply, the handler's timeout or ctx
+func (h *HTTPUserHandler) ask(ctx context.Context, prompt *UserPrompt) (*UserReply, error) {
+	open := *prompt
+	if open.ID == "" {
+		open.ID = promptID(&open)
+	}
+
+	h.mu.Lock()
+	if _, exists := h.pending[open.ID]; exists {
+		h.mu.Unlock()
+		return nil, fmt.Errorf("prompt %s is already open", open.ID)
+	}
+	h.sequence++
+	pending := &pendingPrompt{prompt: open, sequence: h.sequence, reply: make(chan UserReply, 1)}
+	h.pending[open.ID] = pending
+	close(h.changed) // Wake pollers
+	h.changed = make(chan struct{})
+	h.mu.Unlock()
+
+	fmt.Printf("🙋 Waiting up to %v for the user to answer %s\n", h.config.Timeout, open.ID)
+	timer := time.NewTimer(h.config.Timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case reply := <-pending.reply:
+		h.closePrompt(open.ID)
+		return &reply, nil
+	case <-timer.C:
+		err = fmt.Errorf("%w: %s unanswered after %v", ErrNoUserResponse, open.ID, h.config.Timeout)
+	case <-ctx.Done():
+		err = fmt.Errorf("%w: %s: %v", ErrNoUserResponse, open.ID, ctx.Err())
+	}
+
+	// A reply accepted before the prompt closed still counts
+	h.closePrompt(open.ID)
+	select {
+	case reply := <-pending.reply:
+		return &reply, nil
+	default:
+		return nil, err
+	}
+}
+
+// closePrompt stops accepting replies to a prompt
+func (h *HTTPUserHandler) closePrompt(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.pending, id)
+}
+
+// openPrompts returns the open prompts in the order they were asked, and a channel closed
+// when another is opened
+func (h *HTTPUserHandler) openPrompts() ([]UserPrompt, <-chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	open := make([]*pendingPrompt, 0, len(h.pending))
+	for _, pending := range h.pending {
+		open = append(open, pending)
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].sequence < open[j].sequence })
+
+	prompts := make([]UserPrompt, len(open))
+	for i, pending := range open {
+		prompts[i] = pending.prompt
+	}
+	return prompts, h.changed
+}
+
+// handlePrompts long-polls for open prompts
+func (h *HTTPUserHandler) handlePrompts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var wait time.Duration
+	if value := r.URL.Query().Get("wait"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, fmt.Sprintf("invalid wait %q", value), http.StatusBadRequest)
+			return
+		}
+		wait = min(parsed, maxUserPollWait)
+	}
+
+	prompts, changed := h.openPrompts()
+	if len(prompts) == 0 && wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-changed:
+			prompts, _ = h.openPrompts()
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prompts)
+}
+
+// handleReply delivers the user's reply to the waiting round
+func (h *HTTPUserHandler) handleReply(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var reply UserReply
+	r.Body = http.MaxBytesReader(w, r.Body, maxUserReplyBytes)
+	if err := json.NewDecoder(r.Body).Decode(&reply); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Reply too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid reply", http.StatusBadRequest)
+		return
+	}
+
+	// Deliver under h.mu so the prompt cannot close between lookup and delivery
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pending, exists := h.pending[reply.PromptID]
+	if !exists {
+		http.Error(w, fmt.Sprintf("no open prompt %q", reply.PromptID), http.StatusNotFound)
+		return
+	}
+	if err := validateUserReply(pending.prompt.Kind, &reply); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case pending.reply <- reply:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, fmt.Sprintf("prompt %q already answered", reply.PromptID), http.StatusConflict)
+	}
+}
+
+// validateUserReply checks that a reply answers its prompt's kind
+func validateUserReply(kind UserPromptKind, reply *UserReply) error {
+	switch kind {
+	case PromptReview:
+		if reply.Accept == nil {
+			return errors.New("review reply needs accept (true or false)")
+		}
+	case PromptClarification:
+		reply.Answer = strings.TrimSpace(reply.Answer)
+		if reply.Answer == "" {
+			return errors.New("clarification reply needs an answer")
+		}
+	}
+	return nil
+}
+
+// authorized checks the request's bearer token, if one is configured
+func (h *HTTPUserHandler) authorized(r *http.Request) bool {
+	if h.config.Token == "" {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.config.Token)) == 1
+}
+
+// isLoopbackAddr reports whether a listen address only accepts local connections
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
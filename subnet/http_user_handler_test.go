@@ -0,0 +1,53 @@
+package subnet
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPUserHandlerStartRequiresTokenBeyondLoopback(t *testing.T) {
+	for _, addr := range []string{":0", "0.0.0.0:0", "[::]:0"} {
+		handler := NewHTTPUserHandler(HTTPUserConfig{ListenAddr: addr})
+		if err := handler.Start(); !errors.Is(err, ErrUnauthenticatedUserEndpoint) {
+			handler.Close()
+			t.Fatalf("Start on %s without a token = %v, want %v", addr, err, ErrUnauthenticatedUserEndpoint)
+		}
+	}
+
+	for _, config := range []HTTPUserConfig{
+		{ListenAddr: "127.0.0.1:0"},
+		{ListenAddr: "localhost:0"},
+		{ListenAddr: ":0", Token: "secret"},
+	} {
+		handler := NewHTTPUserHandler(config)
+		if err := handler.Start(); err != nil {
+			t.Fatalf("Start(%+v): %v", config, err)
+		}
+		handler.Close()
+	}
+}
+
+func TestHTTPUserHandlerRejectsOversizedReply(t *testing.T) {
+	handler := NewHTTPUserHandler(HTTPUserConfig{})
+	body := `{"prompt_id": "req-1/review", "answer": "` + strings.Repeat("x", maxUserReplyBytes) + `"}`
+
+	recorder := httptest.NewRecorder()
+	handler.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, userReplyPath, strings.NewReader(body)))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized reply answered %d, want %d", recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestReviewWithUserRequiresUserInterface(t *testing.T) {
+	validator := NewCoreValidator("validator-2", "subnet-test", ConsensusValidator, 0.25)
+	validator.SetUserInteractionHandler(NewHTTPUserHandler(HTTPUserConfig{}))
+
+	_, _, err := validator.ReviewWithUser(context.Background(), &UserPrompt{RequestID: "req-1", Output: "output"})
+	if !errors.Is(err, ErrNoUserResponse) {
+		t.Fatalf("ReviewWithUser on a consensus validator = %v, want %v", err, ErrNoUserResponse)
+	}
+}
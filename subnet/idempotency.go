@@ -0,0 +1,162 @@
+// Package subnet - Idempotent Request Handling
+//
+// This file makes a miner's request handling idempotent per requestID, so a retried
+// request (client retry, leader retry after a transport error, miner restart) never
+// advances the VLC twice or runs - and charges for - a task twice.
+//
+//   - Every completed step (initial task = turn 0, clarification turn N) is cached by
+//     requestID and turn for the retention window; a duplicate gets the cached, signed
+//     response and the clock does not move
+//   - A duplicate that arrives while the original is still processing waits for it
+//   - Before executing a paid task the miner claims its payment; a payment that already
+//     funded an execution is refused, even after the cached response expired. The miner's
+//     own claims are journaled and survive a restart; claims across miners are only as
+//     strong as the payment verifier's ExecutionClaimer (see PaymentCoordinator.ClaimExecution)
+//   - Responses recovered from the journal (see miner_journal.go) are cached as well
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultResponseRetention is how long a miner answers duplicates of a completed request
+// from its cache
+const DefaultResponseRetention = 10 * time.Minute
+
+// ErrPaymentAlreadyClaimed is returned when a payment already funded an execution
+var ErrPaymentAlreadyClaimed = errors.New("payment already funded an execution")
+
+// ExecutionClaimer is implemented by payment verifiers that guarantee one payment funds
+// at most one execution
+type ExecutionClaimer interface {
+	// ClaimExecution records that taskID's payment funds executorID's execution.
+	// Fails with ErrPaymentAlreadyClaimed if the payment already funded one.
+	ClaimExecution(taskID, executorID string) error
+}
+
+// cachedResponse is a completed step kept for answering duplicates
+type cachedResponse struct {
+	response  *MinerResponseMessage
+	completed time.Time
+}
+
+// responseKey identifies one step of a request: the initial task is turn 0
+func responseKey(requestID string, turn int) string {
+	return fmt.Sprintf("%s#%d", requestID, turn)
+}
+
+// SetResponseRetention sets how long completed responses are kept for answering duplicates
+func (m *CoreMiner) SetResponseRetention(retention time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responseRetention = retention
+}
+
+// GetResponseRetention returns how long completed responses are kept for answering duplicates
+func (m *CoreMiner) GetResponseRetention() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.responseRetentionLocked()
+}
+
+// responseRetentionLocked returns the retention window (caller holds m.mu)
+func (m *CoreMiner) responseRetentionLocked() time.Duration {
+	if m.responseRetention <= 0 {
+		return DefaultResponseRetention
+	}
+	return m.responseRetention
+}
+
+// admitStep deduplicates one step of a request. It returns the cached response if the step
+// already completed, waiting first if it is in progress. Otherwise the caller becomes the
+// step's executor and must call release once the step has finished.
+func (m *CoreMiner) admitStep(ctx context.Context, requestID string, turn int) (cached *MinerResponseMessage, release func(), err error) {
+	key := responseKey(requestID, turn)
+	for {
+		m.mu.Lock()
+		m.pruneResponsesLocked(time.Now())
+		if entry, ok := m.responseCache[key]; ok {
+			response := *entry.response
+			response.VLCClock = entry.response.VLCClock.Copy()
+			m.mu.Unlock()
+
+			fmt.Printf("♻️  Miner %s: Duplicate %s (turn %d) - returning cached response, VLC unchanged %v\n",
+				m.ID, requestID, turn, response.VLCClock.Values)
+			if response.Signature == "" {
+				m.signResponse(&response) // Recovered from the journal before a signer was set
+			}
+			return &response, nil, nil
+		}
+
+		wait, busy := m.inFlight[key]
+		if !busy {
+			done := make(chan struct{})
+			m.inFlight[key] = done
+			m.mu.Unlock()
+			return nil, func() {
+				m.mu.Lock()
+				delete(m.inFlight, key)
+				m.mu.Unlock()
+				close(done)
+			}, nil
+		}
+		m.mu.Unlock()
+
+		fmt.Printf("⏳ Miner %s: Duplicate %s (turn %d) still in progress - waiting for it\n", m.ID, requestID, turn)
+		select {
+		case <-wait:
+			// Completed (now cached) or abandoned without a response (caller executes it)
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// cacheResponseLocked keeps a completed step for answering duplicates (caller holds m.mu)
+func (m *CoreMiner) cacheResponseLocked(response *MinerResponseMessage, completed time.Time) {
+	m.responseCache[responseKey(response.RequestID, response.Turn)] = &cachedResponse{
+		response:  response,
+		completed: completed,
+	}
+}
+
+// pruneResponsesLocked drops cached responses older than the retention window (caller holds m.mu)
+func (m *CoreMiner) pruneResponsesLocked(now time.Time) {
+	cutoff := now.Add(-m.responseRetentionLocked())
+	for key, entry := range m.responseCache {
+		if entry.completed.Before(cutoff) {
+			delete(m.responseCache, key)
+		}
+	}
+}
+
+// claimExecution claims a paid task's payment for this miner. The claim is journaled before
+// it takes effect, so this miner refuses the payment again after a restart, and is then made
+// at the payment verifier if it supports claims. The execution a restart interrupted keeps
+// its claim and may resume.
+func (m *CoreMiner) claimExecution(requestID string) error {
+	m.mu.Lock()
+	if m.claims[requestID] {
+		_, resuming := m.interrupted[requestID]
+		m.mu.Unlock()
+		if resuming {
+			return nil // Claimed for the execution a restart interrupted, which is resumed now
+		}
+		return fmt.Errorf("%w: task %s already executed by %s", ErrPaymentAlreadyClaimed, requestID, m.ID)
+	}
+	if err := m.journalLocked(JournalClaimed, requestID, 0, m.VLCClock.Copy(), nil); err != nil {
+		m.mu.Unlock()
+		return fmt.Errorf("failed to journal payment claim: %w", err)
+	}
+	m.claims[requestID] = true
+	m.mu.Unlock()
+
+	claimer, ok := m.paymentVerifier.(ExecutionClaimer)
+	if !ok {
+		return nil
+	}
+	return claimer.ClaimExecution(requestID, m.ID)
+}
@@ -0,0 +1,149 @@
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDuplicateWaitsForInFlightStep(t *testing.T) {
+	processor := gatedProcessor{
+		started: make(chan string, 2), // Room for a second execution, which must not happen
+		gates:   map[string]chan struct{}{"task": make(chan struct{})},
+	}
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(processor)
+
+	responses := make(chan *MinerResponseMessage, 2)
+	go func() { responses <- miner.ProcessInput("task", 1, "req-1") }()
+	<-processor.started
+	go func() { responses <- miner.ProcessInput("task", 1, "req-1") }()
+	time.Sleep(20 * time.Millisecond) // Let the duplicate reach the miner while the original runs
+
+	close(processor.gates["task"])
+	first, second := <-responses, <-responses
+	if len(processor.started) != 0 {
+		t.Fatal("duplicate executed the task a second time")
+	}
+	if first.OutputCommitment != second.OutputCommitment || first.VLCClock.Values[MinerClockID] != 2 || second.VLCClock.Values[MinerClockID] != 2 {
+		t.Fatalf("responses %v and %v, want the same response at clock 2", first.VLCClock.Values, second.VLCClock.Values)
+	}
+	if got := miner.GetCurrentClock().Values[MinerClockID]; got != 2 {
+		t.Fatalf("miner clock = %d, want 2", got)
+	}
+}
+
+func TestDuplicateAbandonedWhileWaiting(t *testing.T) {
+	processor := gatedProcessor{
+		started: make(chan string, 1),
+		gates:   map[string]chan struct{}{"task": make(chan struct{})},
+	}
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(processor)
+
+	original := make(chan *MinerResponseMessage, 1)
+	go func() { original <- miner.ProcessInput("task", 1, "req-1") }()
+	<-processor.started
+
+	// The caller gives up before the original finishes: no step, nothing cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	abandoned := miner.ProcessInputContext(ctx, "task", 1, "req-1")
+	if !abandoned.Unprocessed || abandoned.OutputType != TaskFailed || abandoned.VLCClock.Values[MinerClockID] != 1 {
+		t.Fatalf("abandoned duplicate = %+v, want an unprocessed failure at the unchanged clock", abandoned)
+	}
+
+	close(processor.gates["task"])
+	if response := <-original; response.OutputType != OutputReady || response.VLCClock.Values[MinerClockID] != 2 {
+		t.Fatalf("original = %+v, want it completed at clock 2", response)
+	}
+}
+
+func TestDuplicateAfterRetention(t *testing.T) {
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(sleepyProcessor{})
+
+	first := miner.ProcessInput("task", 1, "req-1")
+	if cached := miner.ProcessInput("task", 1, "req-1"); cached.Signature != first.Signature || cached.VLCClock.Values[MinerClockID] != 2 {
+		t.Fatalf("duplicate = %+v, want the cached response", cached)
+	}
+
+	// Once the response ages out, an unpaid task is simply processed again
+	miner.SetResponseRetention(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if again := miner.ProcessInput("task", 1, "req-1"); again.VLCClock.Values[MinerClockID] != 4 {
+		t.Fatalf("retry after retention = %v, want a new step at clock 4", again.VLCClock.Values)
+	}
+}
+
+// claimingVerifier reports every payment locked and lets each fund one execution, across
+// every miner sharing it
+type claimingVerifier struct {
+	mu      sync.Mutex
+	claimed map[string]string // taskID -> executor
+}
+
+func (v *claimingVerifier) VerifyPaymentLocked(taskID string, agentAddr common.Address, minAmount *big.Int) (bool, error) {
+	return true, nil
+}
+
+func (v *claimingVerifier) ClaimExecution(taskID, executorID string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if executor, ok := v.claimed[taskID]; ok {
+		return fmt.Errorf("%w: %s funded %s", ErrPaymentAlreadyClaimed, taskID, executor)
+	}
+	v.claimed[taskID] = executorID
+	return nil
+}
+
+func TestClaimExecution(t *testing.T) {
+	verifier := &claimingVerifier{claimed: make(map[string]string)}
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(sleepyProcessor{})
+	miner.SetPaymentVerifier(verifier, "0x0000000000000000000000000000000000000001", "1")
+
+	if err := miner.claimExecution("req-1"); err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if err := miner.claimExecution("req-1"); !errors.Is(err, ErrPaymentAlreadyClaimed) {
+		t.Fatalf("second claim by the same miner = %v, want %v", err, ErrPaymentAlreadyClaimed)
+	}
+
+	// Another miner sharing the verifier cannot spend the same payment
+	other := NewCoreMiner("miner-2", "subnet-test")
+	other.SetPaymentVerifier(verifier, "0x0000000000000000000000000000000000000001", "1")
+	if err := other.claimExecution("req-1"); !errors.Is(err, ErrPaymentAlreadyClaimed) {
+		t.Fatalf("claim by another miner = %v, want %v", err, ErrPaymentAlreadyClaimed)
+	}
+	if err := other.claimExecution("req-2"); err != nil {
+		t.Fatalf("claim for an unfunded task: %v", err)
+	}
+}
+
+func TestPaidTaskRunsOnceAfterRetention(t *testing.T) {
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(sleepyProcessor{})
+	miner.SetPaymentVerifier(&claimingVerifier{claimed: make(map[string]string)}, "0x0000000000000000000000000000000000000001", "1")
+
+	if response := miner.ProcessInput("task", 1, "req-1"); response.OutputType != OutputReady || response.Unprocessed {
+		t.Fatalf("paid task not executed: %+v", response)
+	}
+	miner.SetResponseRetention(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	refused := miner.ProcessInput("task", 1, "req-1")
+	if !refused.Unprocessed || !strings.HasPrefix(refused.Output, "PAYMENT_ALREADY_CLAIMED") {
+		t.Fatalf("retry after retention = %+v, want the payment refused", refused)
+	}
+	if got := miner.GetCurrentClock().Values[MinerClockID]; got != 2 {
+		t.Fatalf("miner clock = %d, want 2", got)
+	}
+}
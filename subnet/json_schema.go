@@ -0,0 +1,313 @@
+// Package subnet - JSON Schema Subset
+//
+// This file implements the subset of JSON Schema that rubric criteria use to check that a
+// miner output is structured data of the expected shape. Validation is deterministic and
+// has no external dependencies, so every validator reaches the same verdict.
+//
+// Supported keywords:
+//   - type (string or list): object, array, string, number, integer, boolean, null
+//   - enum, const
+//   - object: properties, required, additionalProperties (boolean)
+//   - array: items, minItems, maxItems
+//   - string: minLength, maxLength, pattern
+//   - number: minimum, maximum
+//
+// Unsupported keywords are rejected when the schema is compiled rather than ignored.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrSchemaViolation is returned when a document does not conform to a schema
+var ErrSchemaViolation = errors.New("JSON schema violation")
+
+// JSONSchema is a compiled schema
+type JSONSchema struct {
+	Types                []string
+	Enum                 []interface{}
+	Const                interface{}
+	HasConst             bool
+	Properties           map[string]*JSONSchema
+	Required             []string
+	AdditionalProperties *bool
+	Items                *JSONSchema
+	MinItems, MaxItems   *int
+	MinLength, MaxLength *int
+	Pattern              *regexp.Regexp
+	Minimum, Maximum     *float64
+}
+
+// CompileJSONSchema compiles a schema document
+func CompileJSONSchema(raw json.RawMessage) (*JSONSchema, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("schema is not a JSON object: %v", err)
+	}
+	return compileSchema(doc, "$")
+}
+
+// compileSchema compiles one schema object at path
+func compileSchema(doc map[string]interface{}, path string) (*JSONSchema, error) {
+	schema := &JSONSchema{}
+	for key, value := range doc {
+		var err error
+		switch key {
+		case "$schema", "$id", "title", "description":
+		case "type":
+			schema.Types, err = schemaTypes(value)
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok {
+				err = errors.New("must be an array")
+			}
+			schema.Enum = values
+		case "const":
+			schema.Const, schema.HasConst = value, true
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				err = errors.New("must be an object")
+				break
+			}
+			schema.Properties = make(map[string]*JSONSchema, len(properties))
+			for name, sub := range properties {
+				subDoc, ok := sub.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s.properties.%s: must be a schema object", path, name)
+				}
+				if schema.Properties[name], err = compileSchema(subDoc, path+"."+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			names, ok := value.([]interface{})
+			if !ok {
+				err = errors.New("must be an array of names")
+				break
+			}
+			for _, name := range names {
+				s, ok := name.(string)
+				if !ok {
+					err = errors.New("must be an array of names")
+					break
+				}
+				schema.Required = append(schema.Required, s)
+			}
+		case "additionalProperties":
+			allowed, ok := value.(bool)
+			if !ok {
+				err = errors.New("only a boolean is supported")
+			}
+			schema.AdditionalProperties = &allowed
+		case "items":
+			itemDoc, ok := value.(map[string]interface{})
+			if !ok {
+				err = errors.New("must be a schema object")
+				break
+			}
+			schema.Items, err = compileSchema(itemDoc, path+"[]")
+		case "minItems":
+			schema.MinItems, err = schemaCount(value)
+		case "maxItems":
+			schema.MaxItems, err = schemaCount(value)
+		case "minLength":
+			schema.MinLength, err = schemaCount(value)
+		case "maxLength":
+			schema.MaxLength, err = schemaCount(value)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				err = errors.New("must be a string")
+				break
+			}
+			schema.Pattern, err = regexp.Compile(pattern)
+		case "minimum":
+			schema.Minimum, err = schemaNumber(value)
+		case "maximum":
+			schema.Maximum, err = schemaNumber(value)
+		default:
+			err = errors.New("unsupported keyword")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", path, key, err)
+		}
+	}
+	return schema, nil
+}
+
+// schemaTypes parses a type keyword
+func schemaTypes(value interface{}) ([]string, error) {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		for _, t := range v {
+			s, ok := t.(string)
+			if !ok {
+				return nil, errors.New("must be a type name or a list of names")
+			}
+			types = append(types, s)
+		}
+	default:
+		return nil, errors.New("must be a type name or a list of names")
+	}
+	for _, t := range types {
+		switch t {
+		case "object", "array", "string", "number", "integer", "boolean", "null":
+		default:
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return types, nil
+}
+
+// schemaCount parses a non-negative integer keyword
+func schemaCount(value interface{}) (*int, error) {
+	n, ok := value.(float64)
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	count := int(n)
+	return &count, nil
+}
+
+// schemaNumber parses a numeric keyword
+func schemaNumber(value interface{}) (*float64, error) {
+	n, ok := value.(float64)
+	if !ok {
+		return nil, errors.New("must be a number")
+	}
+	return &n, nil
+}
+
+// ValidateJSON checks a JSON document against the schema
+func (s *JSONSchema) ValidateJSON(document string) error {
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(document)), &value); err != nil {
+		return fmt.Errorf("%w: not valid JSON: %v", ErrSchemaViolation, err)
+	}
+	return s.validate(value, "$")
+}
+
+// validate checks a decoded value at path
+func (s *JSONSchema) validate(value interface{}, path string) error {
+	if len(s.Types) > 0 && !s.matchesType(value) {
+		return fmt.Errorf("%w: %s is %s, want %s", ErrSchemaViolation, path, jsonTypeName(value), strings.Join(s.Types, " or "))
+	}
+	if s.HasConst && !reflect.DeepEqual(value, s.Const) {
+		return fmt.Errorf("%w: %s must equal %v", ErrSchemaViolation, path, s.Const)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(value, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %s is not one of %v", ErrSchemaViolation, path, s.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%w: %s is missing required property %q", ErrSchemaViolation, path, name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names) // Report the first violation deterministically
+		for _, name := range names {
+			sub, known := s.Properties[name]
+			if !known {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%w: %s has unexpected property %q", ErrSchemaViolation, path, name)
+				}
+				continue
+			}
+			if err := sub.validate(v[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			return fmt.Errorf("%w: %s has %d items, want at least %d", ErrSchemaViolation, path, len(v), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			return fmt.Errorf("%w: %s has %d items, want at most %d", ErrSchemaViolation, path, len(v), *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fmt.Errorf("%w: %s is %d characters, want at least %d", ErrSchemaViolation, path, length, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fmt.Errorf("%w: %s is %d characters, want at most %d", ErrSchemaViolation, path, length, *s.MaxLength)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(v) {
+			return fmt.Errorf("%w: %s does not match %s", ErrSchemaViolation, path, s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			return fmt.Errorf("%w: %s is %v, want at least %v", ErrSchemaViolation, path, v, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return fmt.Errorf("%w: %s is %v, want at most %v", ErrSchemaViolation, path, v, *s.Maximum)
+		}
+	}
+	return nil
+}
+
+// matchesType reports whether value has one of the schema's types
+func (s *JSONSchema) matchesType(value interface{}) bool {
+	actual := jsonTypeName(value)
+	for _, t := range s.Types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeName returns the JSON Schema type of a decoded value
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,291 @@
+// Package subnet - Leader Election
+//
+// This file rotates the user-interface (leader) role across the validator set per epoch.
+// The leader orchestrates rounds: it talks to users, forwards tasks to the miner,
+// advances its own VLC entry and finalizes payments.
+//
+// Election:
+//   - Leader(epoch) = validators[keccak256(subnetID|epoch) mod n], so every participant
+//     derives the same leader without communication
+//   - Failover walks the validator list from the elected index; a validator marked
+//     failed for an epoch is skipped for the rest of that epoch
+//
+// Handover:
+//   - Candidates are probed with signed heartbeats over the transport, wherever they run
+//   - A LeaderHandoverMessage tells a remote validator it leads the epoch (it merges the
+//     carried round-end clock) or that it no longer does; only registered validators may send one
+//   - The gateway that fronts users delegates each round of the epoch to a remote leader
+//     with a RoundRequestMessage and records the RoundResultMessage it gets back
+//
+// VLC node IDs: the first miner is 1 and the validator at index i is ValidatorClockID(i) = 2+i,
+// so the clock entry that advances always identifies the validator acting as leader.
+// Additional miners use MinerClockIDFor(k) = MinerClockIDBase+k (see task_router.go).
+package subnet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// ErrNotValidator is returned when a leader election message comes from a participant
+// that is not a registered validator
+var ErrNotValidator = errors.New("sender is not a registered validator")
+
+// MinerClockID is the VLC node ID of the subnet's first (or only) miner
+const MinerClockID uint64 = 1
+
+// MinerClockIDBase offsets the VLC node IDs of additional miners so they never collide
+// with validator IDs; a subnet may therefore have at most MinerClockIDBase-2 validators
+const MinerClockIDBase uint64 = 1000
+
+// MinerClockIDFor returns the VLC node ID of the miner at index (0-based) in the miner set:
+// the first miner keeps MinerClockID, the others use MinerClockIDBase+index
+func MinerClockIDFor(index int) uint64 {
+	if index == 0 {
+		return MinerClockID
+	}
+	return MinerClockIDBase + uint64(index)
+}
+
+// IsMinerClockID reports whether a VLC node ID belongs to a miner
+func IsMinerClockID(id uint64) bool {
+	return id == MinerClockID || id > MinerClockIDBase
+}
+
+// ValidatorClockID returns the VLC node ID of the validator at index (0-based) in the validator set
+func ValidatorClockID(index int) uint64 {
+	return MinerClockID + 1 + uint64(index)
+}
+
+// LeaderSchedule deterministically assigns a leader to each epoch with failover
+type LeaderSchedule struct {
+	SubnetID   string   // Seed for the election hash
+	Validators []string // Validator IDs in registry order
+
+	mu     sync.Mutex
+	failed map[int]map[string]bool // epoch -> validators that stopped responding
+}
+
+// NewLeaderSchedule creates a schedule over the given validator set
+func NewLeaderSchedule(subnetID string, validatorIDs []string) *LeaderSchedule {
+	return &LeaderSchedule{
+		SubnetID:   subnetID,
+		Validators: append([]string(nil), validatorIDs...),
+		failed:     make(map[int]map[string]bool),
+	}
+}
+
+// electedIndex returns keccak256(subnetID|epoch) mod n
+func (s *LeaderSchedule) electedIndex(epoch int) int {
+	seed := crypto.Keccak256([]byte(s.SubnetID + "|" + strconv.Itoa(epoch)))
+	return int(binary.BigEndian.Uint64(seed[len(seed)-8:]) % uint64(len(s.Validators)))
+}
+
+// Candidates returns the failover order for an epoch: the elected leader first,
+// then the following validators in registry order (wrapping around)
+func (s *LeaderSchedule) Candidates(epoch int) []string {
+	if len(s.Validators) == 0 {
+		return nil
+	}
+	start := s.electedIndex(epoch)
+	candidates := make([]string, len(s.Validators))
+	for i := range s.Validators {
+		candidates[i] = s.Validators[(start+i)%len(s.Validators)]
+	}
+	return candidates
+}
+
+// Leader returns the acting leader for an epoch: the first candidate not marked failed.
+// Returns "" when every validator has failed.
+func (s *LeaderSchedule) Leader(epoch int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, candidate := range s.Candidates(epoch) {
+		if !s.failed[epoch][candidate] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// MarkFailed records that a validator stopped responding during an epoch,
+// moving leadership to the next candidate for the rest of that epoch
+func (s *LeaderSchedule) MarkFailed(epoch int, validatorID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failed[epoch] == nil {
+		s.failed[epoch] = make(map[string]bool)
+	}
+	s.failed[epoch][validatorID] = true
+}
+
+// IsFailed reports whether a validator was marked failed for an epoch
+func (s *LeaderSchedule) IsFailed(epoch int, validatorID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failed[epoch][validatorID]
+}
+
+// Index returns a validator's position in the validator set, or -1 if unknown
+func (s *LeaderSchedule) Index(validatorID string) int {
+	for i, id := range s.Validators {
+		if id == validatorID {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetClockID sets the VLC node ID this validator advances when acting as leader
+func (v *CoreValidator) SetClockID(clockID uint64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.ClockID = clockID
+}
+
+// SetRole changes the validator's role (e.g., at a leader handover)
+func (v *CoreValidator) SetRole(role ValidatorRole) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.Role = role
+}
+
+// IsUserInterface reports whether the validator currently holds the leader role
+func (v *CoreValidator) IsUserInterface() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.Role == UserInterfaceValidator
+}
+
+// TakeLeadership makes this validator the round orchestrator. The previous leader's
+// clock is merged first so rounds continue causally after the previous leader's last
+// operation, and the miner's clock entry keeps its expected +2 progression.
+func (v *CoreValidator) TakeLeadership(handoverClock *vlc.Clock) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if handoverClock != nil {
+		v.MinerClock.Merge([]*vlc.Clock{handoverClock})
+	}
+	v.Role = UserInterfaceValidator
+	fmt.Printf("👑 Validator %s: Took leadership (VLC node %d) - %v\n", v.ID, v.ClockID, v.MinerClock.Values)
+}
+
+// NewHeartbeatMessage builds the signed heartbeat reply carrying this validator's clock
+func (v *CoreValidator) NewHeartbeatMessage(receiver string, epoch int) *HeartbeatMessage {
+	msg := &HeartbeatMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			Type:      HeartbeatType,
+			Sender:    v.ID,
+			Receiver:  receiver,
+			Timestamp: time.Now().Unix(),
+		},
+		Epoch:    epoch,
+		VLCClock: v.GetLastMinerClock(),
+	}
+
+	v.sign(msg)
+	return msg
+}
+
+// NewLeaderHandoverMessage builds the signed message naming leader as the epoch's leader,
+// carrying the previous leader's round-end clock
+func (v *CoreValidator) NewLeaderHandoverMessage(receiver, leader string, epoch int, handoverClock *vlc.Clock) *LeaderHandoverMessage {
+	msg := &LeaderHandoverMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			Type:      LeaderHandoverType,
+			Sender:    v.ID,
+			Receiver:  receiver,
+			Timestamp: time.Now().Unix(),
+		},
+		Epoch:    epoch,
+		Leader:   leader,
+		VLCClock: handoverClock,
+	}
+
+	v.sign(msg)
+	return msg
+}
+
+// ApplyLeaderHandover takes over leadership if the message names this validator, and steps
+// down otherwise. The sender must be a registered validator.
+func (v *CoreValidator) ApplyLeaderHandover(msg *LeaderHandoverMessage) error {
+	if err := v.checkValidatorSender(msg.Sender); err != nil {
+		return err
+	}
+
+	if msg.Leader == v.ID {
+		v.TakeLeadership(msg.VLCClock)
+		return nil
+	}
+	if v.IsUserInterface() {
+		v.SetRole(ConsensusValidator)
+		fmt.Printf("🔄 Validator %s: Stepped down - %s leads epoch %d\n", v.ID, msg.Leader, msg.Epoch)
+	}
+	return nil
+}
+
+// checkValidatorSender rejects leader election messages from anyone but a registered validator
+func (v *CoreValidator) checkValidatorSender(senderID string) error {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, participant := range v.participants {
+		if participant.Kind == ParticipantValidator && participant.Name == senderID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotValidator, senderID)
+}
+
+// NewRoundRequestMessage builds the signed request asking leader to run a round
+func (v *CoreValidator) NewRoundRequestMessage(leader string, epoch, inputNumber int, input string) *RoundRequestMessage {
+	msg := &RoundRequestMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			Type:      RoundRequestType,
+			Sender:    v.ID,
+			Receiver:  leader,
+			Timestamp: time.Now().Unix(),
+		},
+		Epoch:       epoch,
+		InputNumber: inputNumber,
+		Input:       input,
+	}
+
+	v.sign(msg)
+	return msg
+}
+
+// NewRoundResultMessage builds the signed result of a round this validator led
+func (v *CoreValidator) NewRoundResultMessage(request *RoundRequestMessage, round *RoundData) *RoundResultMessage {
+	msg := &RoundResultMessage{
+		SubnetMessage: SubnetMessage{
+			SubnetID:  v.SubnetID,
+			Type:      RoundResultType,
+			Sender:    v.ID,
+			Receiver:  request.Sender,
+			Timestamp: time.Now().Unix(),
+		},
+		InputNumber: request.InputNumber,
+		Round:       round,
+		VLCClock:    v.GetLastMinerClock(),
+	}
+	if round != nil {
+		msg.RequestID = round.RequestID
+	}
+
+	v.sign(msg)
+	return msg
+}
@@ -0,0 +1,172 @@
+package subnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+func TestLeaderScheduleIsDeterministic(t *testing.T) {
+	schedule := NewLeaderSchedule("subnet-test", testValidatorIDs)
+	other := NewLeaderSchedule("subnet-test", testValidatorIDs)
+
+	leaders := make(map[string]bool)
+	for epoch := 1; epoch <= 32; epoch++ {
+		leader := schedule.Leader(epoch)
+		if leader != other.Leader(epoch) {
+			t.Fatalf("epoch %d: schedules disagree on the leader (%s, %s)", epoch, leader, other.Leader(epoch))
+		}
+		leaders[leader] = true
+
+		// Failover order is the registry order starting at the elected leader
+		candidates := schedule.Candidates(epoch)
+		start := schedule.Index(candidates[0])
+		for i, candidate := range candidates {
+			if want := testValidatorIDs[(start+i)%len(testValidatorIDs)]; candidate != want {
+				t.Fatalf("epoch %d: candidate %d = %s, want %s", epoch, i, candidate, want)
+			}
+		}
+		if candidates[0] != leader {
+			t.Fatalf("epoch %d: leader %s is not the first candidate %s", epoch, leader, candidates[0])
+		}
+	}
+	if len(leaders) < 2 {
+		t.Fatalf("one validator led every epoch: %v", leaders)
+	}
+}
+
+func TestLeaderScheduleFailover(t *testing.T) {
+	schedule := NewLeaderSchedule("subnet-test", testValidatorIDs)
+	candidates := schedule.Candidates(1)
+
+	schedule.MarkFailed(1, candidates[0])
+	if got := schedule.Leader(1); got != candidates[1] {
+		t.Fatalf("leader after %s failed = %s, want %s", candidates[0], got, candidates[1])
+	}
+	if !schedule.IsFailed(1, candidates[0]) || schedule.IsFailed(2, candidates[0]) {
+		t.Fatal("failure not scoped to its epoch")
+	}
+	if got := schedule.Leader(2); got != schedule.Candidates(2)[0] {
+		t.Fatalf("epoch 2 leader = %s, want its elected %s", got, schedule.Candidates(2)[0])
+	}
+
+	for _, candidate := range candidates {
+		schedule.MarkFailed(1, candidate)
+	}
+	if got := schedule.Leader(1); got != "" {
+		t.Fatalf("leader with every validator failed = %q, want none", got)
+	}
+	if got := schedule.Index("validator-9"); got != -1 {
+		t.Fatalf("index of an unknown validator = %d, want -1", got)
+	}
+}
+
+func TestClockIDsNeverCollide(t *testing.T) {
+	if MinerClockIDFor(0) != MinerClockID || ValidatorClockID(0) != 2 {
+		t.Fatalf("first miner %d, first validator %d; want 1 and 2", MinerClockIDFor(0), ValidatorClockID(0))
+	}
+	for i := 0; i < 8; i++ {
+		if IsMinerClockID(ValidatorClockID(i)) {
+			t.Fatalf("validator %d's node ID %d is a miner's", i, ValidatorClockID(i))
+		}
+		if !IsMinerClockID(MinerClockIDFor(i)) {
+			t.Fatalf("miner %d's node ID %d is not a miner's", i, MinerClockIDFor(i))
+		}
+	}
+}
+
+// newHandoverValidators serves the test validators over an in-memory transport, each
+// knowing the others' keys and clock entries
+func newHandoverValidators(t *testing.T) ([]*CoreValidator, *InMemoryTransport) {
+	t.Helper()
+	registry := NewSignerRegistry()
+	transport := NewInMemoryTransport()
+	var validators []*CoreValidator
+	for i, validatorID := range testValidatorIDs {
+		signer, err := GenerateMessageSigner()
+		if err != nil {
+			t.Fatal(err)
+		}
+		registry.Register(validatorID, signer.Address())
+
+		validator := NewCoreValidator(validatorID, "subnet-test", ConsensusValidator, 0.25)
+		validator.SetClockID(ValidatorClockID(i))
+		validator.SetMessageSigner(signer)
+		validator.SetSignerRegistry(registry)
+		validators = append(validators, validator)
+		if err := transport.Register(validatorID, NewValidatorHandler(validator)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, validator := range validators {
+		for i, validatorID := range testValidatorIDs {
+			validator.RegisterParticipant(SequenceParticipant{ClockID: ValidatorClockID(i), Kind: ParticipantValidator, Name: validatorID})
+		}
+	}
+	return validators, transport
+}
+
+func TestLeaderHandoverOverTransport(t *testing.T) {
+	validators, transport := newHandoverValidators(t)
+	previous, next := validators[0], validators[1]
+	previous.TakeLeadership(nil)
+
+	// The previous leader's round-end clock moves to the new leader
+	handoverClock := vlc.New()
+	handoverClock.Values[MinerClockID] = 6
+	handoverClock.Values[previous.ClockID] = 9
+	ctx := context.Background()
+	heartbeat, err := SendLeaderHandover(ctx, transport, next.ID, previous.NewLeaderHandoverMessage(next.ID, next.ID, 2, handoverClock))
+	if err != nil {
+		t.Fatalf("SendLeaderHandover: %v", err)
+	}
+	if !next.IsUserInterface() || heartbeat.Sender != next.ID || heartbeat.Epoch != 2 {
+		t.Fatalf("new leader did not take over: heartbeat %+v", heartbeat)
+	}
+	if order := handoverClock.Compare(heartbeat.VLCClock); order != vlc.Equal && order != vlc.Less {
+		t.Fatalf("new leader's clock %v does not include the handover clock %v", heartbeat.VLCClock.Values, handoverClock.Values)
+	}
+
+	// Told that another validator leads, the previous leader steps down
+	if _, err := SendLeaderHandover(ctx, transport, previous.ID, next.NewLeaderHandoverMessage(previous.ID, next.ID, 2, nil)); err != nil {
+		t.Fatalf("SendLeaderHandover: %v", err)
+	}
+	if previous.IsUserInterface() {
+		t.Fatal("previous leader kept the leader role")
+	}
+
+	if reply, err := SendHeartbeat(ctx, transport, validators[2].ID, next.NewHeartbeatMessage(validators[2].ID, 2)); err != nil || reply.Sender != validators[2].ID {
+		t.Fatalf("heartbeat = %+v, %v, want a reply from %s", reply, err, validators[2].ID)
+	}
+}
+
+func TestLeaderHandoverRejectsOutsiders(t *testing.T) {
+	validators, transport := newHandoverValidators(t)
+
+	// Signed, but not by a registered validator
+	outsider := NewCoreValidator("validator-9", "subnet-test", ConsensusValidator, 0.25)
+	signer, err := GenerateMessageSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outsider.SetMessageSigner(signer)
+	claim := outsider.NewLeaderHandoverMessage(validators[1].ID, validators[1].ID, 2, nil)
+	if _, err := SendLeaderHandover(context.Background(), transport, validators[1].ID, claim); err == nil {
+		t.Fatal("handover from an unregistered validator accepted")
+	}
+	if err := validators[1].ApplyLeaderHandover(claim); !errors.Is(err, ErrNotValidator) {
+		t.Fatalf("ApplyLeaderHandover from an outsider = %v, want %v", err, ErrNotValidator)
+	}
+	if validators[1].IsUserInterface() {
+		t.Fatal("outsider's handover made a leader")
+	}
+
+	// Altered after a registered validator signed it
+	forged := validators[0].NewLeaderHandoverMessage(validators[1].ID, validators[2].ID, 2, nil)
+	forged.Leader = validators[1].ID
+	if _, err := SendLeaderHandover(context.Background(), transport, validators[1].ID, forged); err == nil || validators[1].IsUserInterface() {
+		t.Fatalf("forged handover = %v, want it rejected", err)
+	}
+}
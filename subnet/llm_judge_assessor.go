@@ -0,0 +1,236 @@
+// Package subnet - LLM-as-Judge Quality Assessment
+//
+// This file implements LLMJudgeAssessor, a QualityAssessor that asks a judge model behind
+// an OpenAI-compatible chat-completions endpoint to score a miner output against the task
+// it answers (MinerResponseMessage.Task). Point BaseURL at a local stub server to exercise
+// it without a model.
+//
+// Judge reply:
+//   - A JSON object {"score": 0.0-1.0, "rationale": "..."} (a Markdown code fence is tolerated)
+//   - Accept = score >= pass threshold, decided by the validator rather than the judge
+//
+// The assessor abstains rather than guessing when the judge times out, is unreachable,
+// answers with an error status, or replies with anything but a well-formed score and
+// rationale. The rationale is carried in the vote's QualityReport and published with the
+// round data.
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultJudgeTimeout bounds one judgement when the configuration sets none
+	DefaultJudgeTimeout = 30 * time.Second
+
+	// DefaultJudgePassThreshold is the judge score required to accept when the configuration sets none
+	DefaultJudgePassThreshold = 0.7
+
+	maxJudgeRationale = 1000 // Rationales are truncated to this many bytes
+
+	defaultJudgePrompt = "You are a strict reviewer grading an AI agent's response to a user's task. " +
+		"Judge whether the response completes the task correctly, completely and clearly. " +
+		"Do not quote the response in your rationale."
+
+	judgeReplyInstructions = "Respond only with a JSON object: " +
+		`{"score": <number from 0.0 (useless) to 1.0 (excellent)>, "rationale": "<one or two sentences>"}`
+)
+
+// LLMJudgeConfig configures an LLMJudgeAssessor
+type LLMJudgeConfig struct {
+	BaseURL       string        // API root including version, e.g. "http://localhost:8000/v1"
+	APIKey        string        // Bearer token (optional for local servers)
+	Model         string        // Judge model name
+	Prompt        string        // Scoring instructions (empty: a generic grading prompt)
+	Timeout       time.Duration // Deadline for one judgement (zero: DefaultJudgeTimeout)
+	PassThreshold float64       // Minimum score to accept (zero: DefaultJudgePassThreshold)
+	MaxTokens     int           // Completion token limit (zero: server default)
+	HTTPClient    *http.Client  // Client used for requests (nil: http.DefaultClient)
+}
+
+// LLMJudgeConfigFromEnv reads the judge configuration:
+//   - JUDGE_BASE_URL (default http://localhost:8000/v1), JUDGE_API_KEY, JUDGE_MODEL
+//   - JUDGE_PROMPT, JUDGE_MAX_TOKENS
+//   - JUDGE_TIMEOUT (e.g., "20s"), JUDGE_PASS_THRESHOLD (0.0-1.0)
+func LLMJudgeConfigFromEnv() (LLMJudgeConfig, error) {
+	config := LLMJudgeConfig{
+		BaseURL: os.Getenv("JUDGE_BASE_URL"),
+		APIKey:  os.Getenv("JUDGE_API_KEY"),
+		Model:   os.Getenv("JUDGE_MODEL"),
+		Prompt:  os.Getenv("JUDGE_PROMPT"),
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "http://localhost:8000/v1"
+	}
+
+	if value := os.Getenv("JUDGE_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return config, fmt.Errorf("invalid JUDGE_TIMEOUT %q", value)
+		}
+		config.Timeout = timeout
+	}
+	if value := os.Getenv("JUDGE_PASS_THRESHOLD"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return config, fmt.Errorf("invalid JUDGE_PASS_THRESHOLD %q", value)
+		}
+		config.PassThreshold = threshold
+	}
+	if value := os.Getenv("JUDGE_MAX_TOKENS"); value != "" {
+		maxTokens, err := strconv.Atoi(value)
+		if err != nil || maxTokens < 0 {
+			return config, fmt.Errorf("invalid JUDGE_MAX_TOKENS %q", value)
+		}
+		config.MaxTokens = maxTokens
+	}
+	return config, nil
+}
+
+// LLMJudgeAssessor scores miner outputs with a judge model. It is safe for concurrent use.
+type LLMJudgeAssessor struct {
+	config LLMJudgeConfig
+	client *http.Client
+}
+
+// NewLLMJudgeAssessor validates the configuration and creates the assessor
+func NewLLMJudgeAssessor(config LLMJudgeConfig) (*LLMJudgeAssessor, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("judge base URL is required")
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("judge model is required")
+	}
+	if config.Timeout < 0 {
+		return nil, fmt.Errorf("judge timeout must not be negative")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultJudgeTimeout
+	}
+	if config.PassThreshold == 0 {
+		config.PassThreshold = DefaultJudgePassThreshold
+	}
+	if config.PassThreshold < 0 || config.PassThreshold > 1 {
+		return nil, fmt.Errorf("judge pass threshold %v outside [0, 1]", config.PassThreshold)
+	}
+	if config.Prompt == "" {
+		config.Prompt = defaultJudgePrompt
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &LLMJudgeAssessor{config: config, client: client}, nil
+}
+
+// Name identifies the judge model in quality reports
+func (j *LLMJudgeAssessor) Name() string {
+	return "llm-judge:" + j.config.Model
+}
+
+// AssessQuality scores a response with the judge. It cannot abstain: a judgement that
+// could not be made is reported as a rejection with quality 0. CoreValidator uses
+// AssessDetailed, which abstains instead.
+func (j *LLMJudgeAssessor) AssessQuality(response *MinerResponseMessage) (float64, bool) {
+	report := j.AssessDetailed(response)
+	if report.AbstainReason != "" {
+		return 0, false
+	}
+	return report.Quality, report.Accept
+}
+
+// AssessOrAbstain scores a response with the judge, or abstains if the judge gave no usable verdict
+func (j *LLMJudgeAssessor) AssessOrAbstain(response *MinerResponseMessage) (float64, bool, string) {
+	report := j.AssessDetailed(response)
+	return report.Quality, report.Accept, report.AbstainReason
+}
+
+// AssessDetailed asks the judge for a score and rationale. The report's AbstainReason is set
+// when the judge timed out, was unreachable or gave no well-formed verdict.
+func (j *LLMJudgeAssessor) AssessDetailed(response *MinerResponseMessage) *QualityReport {
+	report := &QualityReport{Assessor: j.Name()}
+	if strings.TrimSpace(response.Task) == "" {
+		report.AbstainReason = "response carries no task to judge against"
+		fmt.Printf("⚖️  %s: abstaining on %s - %s\n", j.Name(), response.RequestID, report.AbstainReason)
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), j.config.Timeout)
+	defer cancel()
+
+	reply, err := postChatCompletion(ctx, j.client, j.config.BaseURL, j.config.APIKey, j.newJudgeRequest(response))
+	if err == nil {
+		var score float64
+		score, report.Rationale, err = parseJudgeVerdict(reply.Content)
+		report.Quality = score
+	}
+	if err != nil {
+		report.AbstainReason = judgeAbstainReason(err, j.config.Timeout)
+		fmt.Printf("⚖️  %s: abstaining on %s - %s\n", j.Name(), response.RequestID, report.AbstainReason)
+		return report
+	}
+
+	report.Accept = report.Quality >= j.config.PassThreshold
+	fmt.Printf("⚖️  %s: %s quality %.2f (accept=%v) - %s\n",
+		j.Name(), response.RequestID, report.Quality, report.Accept, report.Rationale)
+	return report
+}
+
+// newJudgeRequest builds the conversation: scoring prompt, then the task and the response
+func (j *LLMJudgeAssessor) newJudgeRequest(response *MinerResponseMessage) *chatRequest {
+	temperature := 0.0 // Judgements should be as repeatable as the server allows
+	return &chatRequest{
+		Model: j.config.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: j.config.Prompt + "\n\n" + judgeReplyInstructions},
+			{Role: "user", Content: "Task:\n" + response.Task + "\n\nResponse:\n" + response.Output},
+		},
+		MaxTokens:      j.config.MaxTokens,
+		Temperature:    &temperature,
+		ResponseFormat: map[string]string{"type": "json_object"},
+	}
+}
+
+// parseJudgeVerdict extracts the score and rationale from a judge reply
+func parseJudgeVerdict(content string) (float64, string, error) {
+	var verdict struct {
+		Score     *float64 `json:"score"`
+		Rationale string   `json:"rationale"`
+	}
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &verdict); err != nil {
+		return 0, "", fmt.Errorf("%w: verdict is not the requested JSON object: %v", ErrModelResponse, err)
+	}
+
+	switch {
+	case verdict.Score == nil:
+		return 0, "", fmt.Errorf("%w: verdict has no score", ErrModelResponse)
+	case math.IsNaN(*verdict.Score) || *verdict.Score < 0 || *verdict.Score > 1:
+		return 0, "", fmt.Errorf("%w: score %v outside [0, 1]", ErrModelResponse, *verdict.Score)
+	case strings.TrimSpace(verdict.Rationale) == "":
+		return 0, "", fmt.Errorf("%w: verdict has no rationale", ErrModelResponse)
+	}
+	return *verdict.Score, truncateString(strings.TrimSpace(verdict.Rationale), maxJudgeRationale), nil
+}
+
+// judgeAbstainReason describes why the judge gave no usable verdict
+func judgeAbstainReason(err error, timeout time.Duration) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Sprintf("judge timed out after %v", timeout)
+	case errors.Is(err, ErrModelResponse):
+		return fmt.Sprintf("unusable judge reply: %v", err)
+	default:
+		return fmt.Sprintf("judge unavailable: %v", err)
+	}
+}
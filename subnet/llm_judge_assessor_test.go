@@ -0,0 +1,175 @@
+package subnet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+func newTestJudge(t *testing.T, server *httptest.Server, timeout time.Duration) *LLMJudgeAssessor {
+	t.Helper()
+	judge, err := NewLLMJudgeAssessor(LLMJudgeConfig{
+		BaseURL:    server.URL + "/v1",
+		Model:      "stub-judge",
+		Timeout:    timeout,
+		HTTPClient: server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("NewLLMJudgeAssessor: %v", err)
+	}
+	return judge
+}
+
+// verdict replies with a judge message whose content is the given text
+func verdict(content string) http.HandlerFunc {
+	return completion(`{"role": "assistant", "content": ` + strconv.Quote(content) + `}`)
+}
+
+func judgedResponse(requestID string) *MinerResponseMessage {
+	return &MinerResponseMessage{
+		SubnetMessage: SubnetMessage{RequestID: requestID, Sender: "miner-1"},
+		Task:          "Summarise the report",
+		Output:        "The report says revenue grew.",
+		OutputType:    OutputReady,
+	}
+}
+
+func TestLLMJudgeAssessorVerdicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantAccept bool
+		wantScore  float64
+	}{
+		{"passing score", `{"score": 0.9, "rationale": "Accurate and complete."}`, true, 0.9},
+		{"failing score", `{"score": 0.2, "rationale": "Misses the question."}`, false, 0.2},
+		{"fenced verdict", "```json\n{\"score\": 0.7, \"rationale\": \"Good enough.\"}\n```", true, 0.7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newStubModelServer(t, verdict(tt.content))
+			judge := newTestJudge(t, server, time.Second)
+
+			report := judge.AssessDetailed(judgedResponse("req-1"))
+			if report.AbstainReason != "" {
+				t.Fatalf("judge abstained: %s", report.AbstainReason)
+			}
+			if report.Quality != tt.wantScore || report.Accept != tt.wantAccept || report.Rationale == "" {
+				t.Fatalf("report = %+v, want score %v accept %v with a rationale", report, tt.wantScore, tt.wantAccept)
+			}
+			if report.Assessor != "llm-judge:stub-judge" {
+				t.Fatalf("assessor = %q", report.Assessor)
+			}
+
+			messages := (*requests)[0].Messages
+			if len(messages) != 2 || !strings.Contains(messages[1].Content, "Summarise the report") ||
+				!strings.Contains(messages[1].Content, "revenue grew") {
+				t.Fatalf("judge was sent %+v, want the task and the output", messages)
+			}
+		})
+	}
+}
+
+func TestLLMJudgeAssessorAbstains(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   http.HandlerFunc
+		timeout time.Duration
+		reason  string
+	}{
+		{
+			name:    "timeout",
+			reply:   func(w http.ResponseWriter, r *http.Request) { <-r.Context().Done() },
+			timeout: 50 * time.Millisecond,
+			reason:  "judge timed out",
+		},
+		{
+			name:   "error status",
+			reply:  func(w http.ResponseWriter, r *http.Request) { http.Error(w, "busy", http.StatusTooManyRequests) },
+			reason: "status 429",
+		},
+		{
+			name:   "malformed score",
+			reply:  verdict(`{"score": "excellent", "rationale": "Looks right."}`),
+			reason: "not the requested JSON object",
+		},
+		{
+			name:   "not JSON",
+			reply:  verdict("I would give this an 8/10."),
+			reason: "not the requested JSON object",
+		},
+		{
+			name:   "missing score",
+			reply:  verdict(`{"rationale": "Looks right."}`),
+			reason: "no score",
+		},
+		{
+			name:   "score out of range",
+			reply:  verdict(`{"score": 8, "rationale": "Looks right."}`),
+			reason: "outside [0, 1]",
+		},
+		{
+			name:   "missing rationale",
+			reply:  verdict(`{"score": 0.9, "rationale": "  "}`),
+			reason: "no rationale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newStubModelServer(t, tt.reply)
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Second
+			}
+			judge := newTestJudge(t, server, timeout)
+
+			report := judge.AssessDetailed(judgedResponse("req-1"))
+			if !strings.Contains(report.AbstainReason, tt.reason) {
+				t.Fatalf("abstain reason = %q, want it to mention %q", report.AbstainReason, tt.reason)
+			}
+			if report.Accept || report.Quality != 0 {
+				t.Fatalf("abstaining report carries a verdict: %+v", report)
+			}
+
+			// A validator using the judge abstains instead of voting
+			validator := NewCoreValidator("validator-2", "subnet-test", ConsensusValidator, 0.25)
+			validator.SetQualityAssessor(judge)
+			vote := validator.VoteOnOutput(judgedResponse("req-1"))
+			if !vote.Abstain || vote.Accept || !strings.Contains(vote.AbstainReason, tt.reason) {
+				t.Fatalf("vote = abstain %v accept %v reason %q, want an abstention", vote.Abstain, vote.Accept, vote.AbstainReason)
+			}
+		})
+	}
+}
+
+func TestLLMJudgeRationaleReachesRoundData(t *testing.T) {
+	server, _ := newStubModelServer(t, verdict(`{"score": 0.85, "rationale": "Covers every point."}`))
+	judge := newTestJudge(t, server, time.Second)
+
+	validator := NewCoreValidator("validator-2", "subnet-test", ConsensusValidator, 0.25)
+	validator.SetQualityAssessor(judge)
+	vote := validator.VoteOnOutput(judgedResponse("req-1"))
+
+	adapter := NewSubnetGraphAdapter("subnet-test", 0, "")
+	var rounds []RoundData
+	adapter.SetRoundCompletedCallback(func(round RoundData) { rounds = append(rounds, round) })
+	adapter.TrackUserInput("req-1", "Summarise the report", vlc.New(), "")
+	adapter.RecordVoteAssessments("req-1", []*ValidatorVoteMessage{vote})
+	adapter.TrackRoundComplete("req-1", 1, vlc.New(), "ACCEPTED", "accepted", true, "DELIVERED", "")
+
+	if len(rounds) != 1 || len(rounds[0].Assessments) != 1 {
+		t.Fatalf("round data = %+v, want one round with one assessment", rounds)
+	}
+	assessment := rounds[0].Assessments[0]
+	if assessment.ValidatorID != "validator-2" || assessment.Assessor != "llm-judge:stub-judge" ||
+		assessment.Rationale != "Covers every point." || assessment.Quality != 0.85 || !assessment.Accept {
+		t.Fatalf("assessment = %+v, want the judge's verdict and rationale", assessment)
+	}
+}
@@ -0,0 +1,245 @@
+// Package subnet - Message Signing and Verification
+//
+// This file implements secp256k1 signatures for every PoCW subnet message.
+// Each participant (miner or validator) holds a MessageSigner and signs a canonical
+// encoding of the messages it emits, including any VLC clock they carry. Receivers
+// check signatures against a SignerRegistry that maps participant IDs to the
+// Ethereum addresses allowed to speak for them.
+package subnet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// messageSigningPrefix domain-separates subnet message digests from Ethereum
+// transactions and EIP-712 payloads signed with the same keys.
+const messageSigningPrefix = "\x19PoCW Subnet Message:\n"
+
+var (
+	// ErrUnsignedMessage is returned when a message arrives without a signature
+	ErrUnsignedMessage = errors.New("message is not signed")
+	// ErrUnknownSigner is returned when the message sender has no registered address
+	ErrUnknownSigner = errors.New("sender is not a registered participant")
+	// ErrInvalidSignature is returned when the signature was not produced by the sender's key
+	ErrInvalidSignature = errors.New("signature does not match sender")
+)
+
+// Signable is implemented by every subnet message type through the embedded SubnetMessage.
+// It gives signing code access to the common header without knowing the concrete type.
+type Signable interface {
+	Header() *SubnetMessage
+}
+
+// Header returns the common message header (promoted to all embedding message types)
+func (m *SubnetMessage) Header() *SubnetMessage {
+	return m
+}
+
+// CanonicalMessageBytes returns the deterministic encoding of a message used for signing.
+//
+// Encoding Rules:
+//   - The message is serialized with its JSON tags (the same form used on the wire)
+//   - The "signature" field is removed so a signature never covers itself
+//   - Object keys are sorted and numbers keep their original textual form
+//
+// Because VLC clocks serialize as JSON objects, the clock is covered by the signature
+// and re-ordering of clock entries in transit does not invalidate it.
+func CanonicalMessageBytes(msg Signable) ([]byte, error) {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to decode message fields: %v", err)
+	}
+	delete(fields, "signature")
+
+	// encoding/json writes map keys in sorted order, which makes this canonical
+	canonical, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode canonical message: %v", err)
+	}
+	return canonical, nil
+}
+
+// MessageDigest returns the Keccak-256 digest that participants sign for a message
+func MessageDigest(msg Signable) ([]byte, error) {
+	canonical, err := CanonicalMessageBytes(msg)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256([]byte(messageSigningPrefix), canonical), nil
+}
+
+// MessageSigner holds a participant's secp256k1 key and signs outgoing messages
+type MessageSigner struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+}
+
+// NewMessageSigner creates a signer from a hex-encoded private key (with or without 0x prefix)
+func NewMessageSigner(privateKeyHex string) (*MessageSigner, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	return &MessageSigner{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}, nil
+}
+
+// GenerateMessageSigner creates a signer with a fresh random key.
+// Useful for ephemeral participants that are registered at runtime.
+func GenerateMessageSigner() (*MessageSigner, error) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return &MessageSigner{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}, nil
+}
+
+// Address returns the Ethereum address corresponding to the signer's key
+func (s *MessageSigner) Address() common.Address {
+	return s.address
+}
+
+// SignDigest signs a 32-byte digest and returns the 65-byte [R || S || V] signature (V = 27/28)
+func (s *MessageSigner) SignDigest(digest []byte) ([]byte, error) {
+	signature, err := crypto.Sign(digest, s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	// Adjust v value for Ethereum (0/1 -> 27/28)
+	signature[64] += 27
+	return signature, nil
+}
+
+// Sign computes the message digest and stores the hex-encoded signature in the header
+func (s *MessageSigner) Sign(msg Signable) error {
+	digest, err := MessageDigest(msg)
+	if err != nil {
+		return err
+	}
+
+	signature, err := s.SignDigest(digest)
+	if err != nil {
+		return err
+	}
+
+	msg.Header().Signature = fmt.Sprintf("0x%x", signature)
+	return nil
+}
+
+// RecoverDigestSigner recovers the address that produced a hex signature over digest
+func RecoverDigestSigner(digest []byte, signatureHex string) (common.Address, error) {
+	signature := common.FromHex(signatureHex)
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: expected %d bytes, got %d",
+			ErrInvalidSignature, crypto.SignatureLength, len(signature))
+	}
+
+	// Copy before normalizing v so the caller's bytes are untouched
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// RecoverMessageSigner recovers the address that signed a message
+func RecoverMessageSigner(msg Signable) (common.Address, error) {
+	header := msg.Header()
+	if header.Signature == "" {
+		return common.Address{}, ErrUnsignedMessage
+	}
+
+	digest, err := MessageDigest(msg)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverDigestSigner(digest, header.Signature)
+}
+
+// SignerRegistry maps participant IDs (message Sender values) to their signing addresses.
+// Validators use it to authenticate miner responses, and the round coordinator uses it
+// to authenticate validator votes before they count toward consensus.
+type SignerRegistry struct {
+	mu      sync.RWMutex
+	signers map[string]common.Address // participant ID -> signing address
+}
+
+// NewSignerRegistry creates an empty signer registry
+func NewSignerRegistry() *SignerRegistry {
+	return &SignerRegistry{
+		signers: make(map[string]common.Address),
+	}
+}
+
+// Register associates a participant ID with the address that signs its messages
+func (r *SignerRegistry) Register(participantID string, address common.Address) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.signers[participantID] = address
+}
+
+// Address returns the registered signing address for a participant
+func (r *SignerRegistry) Address(participantID string) (common.Address, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	address, exists := r.signers[participantID]
+	return address, exists
+}
+
+// Verify checks that a message is signed by the key registered for its sender.
+//
+// Returns:
+//   - ErrUnsignedMessage if the message carries no signature
+//   - ErrUnknownSigner if the sender is not registered
+//   - ErrInvalidSignature if the signature is malformed or belongs to another key
+func (r *SignerRegistry) Verify(msg Signable) error {
+	header := msg.Header()
+	if header.Signature == "" {
+		return fmt.Errorf("%w: %s from %s", ErrUnsignedMessage, header.Type, header.Sender)
+	}
+
+	expected, exists := r.Address(header.Sender)
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownSigner, header.Sender)
+	}
+
+	recovered, err := RecoverMessageSigner(msg)
+	if err != nil {
+		return err
+	}
+
+	if recovered != expected {
+		return fmt.Errorf("%w: %s signed by %s, expected %s",
+			ErrInvalidSignature, header.Sender, recovered.Hex(), expected.Hex())
+	}
+	return nil
+}
@@ -22,6 +22,23 @@ const (
 	InfoRequestType    SubnetMessageType = "info_request"    // Request for additional user context
 	AdditionalInfoType SubnetMessageType = "additional_info" // User-provided additional context
 	FinalOutputType    SubnetMessageType = "final_output"    // Final consensus result delivery
+
+	// Commit-reveal voting (see commit_reveal.go)
+	VoteCommitRequestType SubnetMessageType = "vote_commit_request" // Ask a validator to seal its vote on a miner response
+	VoteCommitType        SubnetMessageType = "vote_commit"         // Validator's sealed vote (hash only)
+	VoteRevealType        SubnetMessageType = "vote_reveal"         // Ask a validator to open its sealed vote
+
+	// Leader election (see leader_election.go)
+	HeartbeatType      SubnetMessageType = "heartbeat"       // Liveness probe; validators reply with their clock
+	LeaderHandoverType SubnetMessageType = "leader_handover" // Names an epoch's leader; the leader takes over, others step down
+	RoundRequestType   SubnetMessageType = "round_request"   // Gateway asks the epoch's (remote) leader to run a round
+	RoundResultType    SubnetMessageType = "round_result"    // Leader's record of a round it ran
+
+	// Streaming output (see streaming.go)
+	MinerChunkType SubnetMessageType = "miner_chunk" // Incremental piece of a miner output (one-way, no VLC event)
+
+	// Independent miner clock verification (see clock_checkpoints.go)
+	ClockCheckpointType SubnetMessageType = "clock_checkpoint" // Miner response a validator accepted, gossiped to its peers (one-way)
 )
 
 // MinerOutputType specifies the type of response a miner can generate.
@@ -31,6 +48,7 @@ type MinerOutputType string
 const (
 	OutputReady  MinerOutputType = "output_ready"   // Miner has generated a solution ready for validation
 	NeedMoreInfo MinerOutputType = "need_more_info" // Miner needs additional context from user
+	TaskFailed   MinerOutputType = "task_failed"    // Processing failed or timed out; see Error
 )
 
 // SubnetMessage is the base message structure for subnet communication
@@ -51,6 +69,8 @@ type UserInputMessage struct {
 	InputNumber        int                   `json:"input_number"`
 	PaymentAuth        *PaymentAuthorization `json:"payment_auth,omitempty"`        // Payment signature (if responding to 402)
 	IsPaymentResponse  bool                  `json:"is_payment_response,omitempty"` // True if this includes payment
+	VLCClock           *vlc.Clock            `json:"vlc_clock,omitempty"`           // Sender's clock, merged by the miner for causal sync
+	Stream             bool                  `json:"stream,omitempty"`              // Ask the miner to stream output chunks to the sender
 }
 
 // MinerResponseMessage represents a miner's response to user input or additional information.
@@ -59,23 +79,147 @@ type UserInputMessage struct {
 // For x402 payments, can include a PaymentRequest indicating payment is required before processing.
 type MinerResponseMessage struct {
 	SubnetMessage
-	OutputType     MinerOutputType   `json:"output_type"`              // Type of response (ready vs need info)
-	Output         string            `json:"output,omitempty"`         // Generated solution (if OutputReady)
-	InfoRequest    string            `json:"info_request,omitempty"`   // Question for user (if NeedMoreInfo)
-	VLCClock       *vlc.Clock        `json:"vlc_clock"`                // Vector clock for causal ordering
-	InputNumber    int               `json:"input_number"`             // Sequential input identifier for tracking
-	PaymentRequest *PaymentRequest   `json:"payment_request,omitempty"` // x402 payment requirement (if payment needed)
-	PaymentPending bool              `json:"payment_pending,omitempty"` // True if awaiting payment before processing
+	OutputType       MinerOutputType `json:"output_type"`                 // Type of response (ready vs need info)
+	Output           string          `json:"output,omitempty"`            // Generated solution (if OutputReady)
+	Task             string          `json:"task,omitempty"`              // Task the response answers, with any clarifications (for assessors)
+	OutputCommitment string          `json:"output_commitment,omitempty"` // Commitment to (request, input, output, clock); see OutputCommitment
+	InfoRequest      string          `json:"info_request,omitempty"`      // Question for user (if NeedMoreInfo)
+	Error            string          `json:"error,omitempty"`             // Failure reason (if TaskFailed)
+	Unprocessed      bool            `json:"unprocessed,omitempty"`       // Refused or abandoned without a VLC step; the clock is unchanged
+	StreamDigest     string          `json:"stream_digest,omitempty"`     // Rolling digest over the streamed chunks (if streamed)
+	ChunkSizes       []int           `json:"chunk_sizes,omitempty"`       // Byte length of each streamed chunk, in order
+	VLCClock         *vlc.Clock      `json:"vlc_clock"`                   // Vector clock for causal ordering
+	RequestClock     *vlc.Clock      `json:"request_clock,omitempty"`     // Miner clock when this request was received
+	InputNumber      int             `json:"input_number"`                // Sequential input identifier for tracking
+	Turn             int             `json:"turn,omitempty"`              // Clarification turns answered before this response
+	PaymentRequest   *PaymentRequest `json:"payment_request,omitempty"`   // x402 payment requirement (if payment needed)
+	PaymentPending   bool            `json:"payment_pending,omitempty"`   // True if awaiting payment before processing
 }
 
 // ValidatorVoteMessage represents validator's vote on miner output
 type ValidatorVoteMessage struct {
 	SubnetMessage
-	ValidatorID    string     `json:"validator_id"`
-	Quality        float64    `json:"quality"` // 0.0 to 1.0
-	Accept         bool       `json:"accept"`
-	Weight         float64    `json:"weight"` // 0.25 for each validator
-	LastMinerClock *vlc.Clock `json:"last_miner_clock"`
+	ValidatorID      string         `json:"validator_id"`
+	Quality          float64        `json:"quality"` // 0.0 to 1.0
+	Accept           bool           `json:"accept"`
+	Weight           float64        `json:"weight"` // 0.25 for each validator
+	LastMinerClock   *vlc.Clock     `json:"last_miner_clock"`
+	Abstain          bool           `json:"abstain,omitempty"`           // Validator declined to judge; counts toward neither side
+	AbstainReason    string         `json:"abstain_reason,omitempty"`    // Why the validator abstained
+	Salt             string         `json:"salt,omitempty"`              // Commit-reveal salt (revealed votes only)
+	OutputCommitment string         `json:"output_commitment,omitempty"` // Commitment of the miner response being judged
+	AttestedClock    *vlc.Clock     `json:"attested_clock,omitempty"`    // Clock of the miner response being judged
+	Attestation      string         `json:"attestation,omitempty"`       // Signature over AttestationDigest, for quorum certificates
+	Report           *QualityReport `json:"report,omitempty"`            // Per-criterion breakdown (DetailedQualityAssessor only)
+}
+
+// QualityReport explains a quality score: which assessor produced it and how each
+// criterion contributed
+type QualityReport struct {
+	Assessor      string           `json:"assessor"` // Assessor name and version (e.g., rubric "docs-v2")
+	Quality       float64          `json:"quality"`
+	Accept        bool             `json:"accept"`
+	Criteria      []CriterionScore `json:"criteria,omitempty"`
+	Rationale     string           `json:"rationale,omitempty"`      // Free-text justification (e.g., from a judge model)
+	AbstainReason string           `json:"abstain_reason,omitempty"` // Set when the assessor could not judge; the vote abstains
+	Components    []ComponentScore `json:"components,omitempty"`     // Each assessor's verdict (ensembles only)
+}
+
+// ComponentScore is one ensemble component's verdict and how it was combined
+type ComponentScore struct {
+	Name          string         `json:"name"`
+	Weight        float64        `json:"weight"`
+	Quality       float64        `json:"quality"`
+	Accept        bool           `json:"accept"`
+	AbstainReason string         `json:"abstain_reason,omitempty"` // The component abstained and was left out
+	Vetoed        bool           `json:"vetoed,omitempty"`         // The component's veto rule rejected the output
+	Report        *QualityReport `json:"report,omitempty"`         // The component's own breakdown, if it gives one
+}
+
+// CriterionScore is one criterion's contribution to a quality score
+type CriterionScore struct {
+	Name     string  `json:"name"`
+	Weight   float64 `json:"weight"`
+	Score    float64 `json:"score"` // 0.0 to 1.0
+	Passed   bool    `json:"passed"`
+	Required bool    `json:"required,omitempty"` // A failed required criterion rejects the output
+	Detail   string  `json:"detail,omitempty"`
+}
+
+// VoteCommitRequestMessage asks a validator to assess a miner response and commit to its vote
+type VoteCommitRequestMessage struct {
+	SubnetMessage
+	Response *MinerResponseMessage `json:"response"`
+}
+
+// VoteCommitMessage carries a validator's sealed vote: a hash binding the vote to a secret salt
+type VoteCommitMessage struct {
+	SubnetMessage
+	ValidatorID string `json:"validator_id"`
+	Commitment  string `json:"commitment"` // 0x-prefixed VoteCommitment hash
+}
+
+// VoteRevealMessage asks a validator to reveal its sealed vote once a quorum of commitments is in
+type VoteRevealMessage struct {
+	SubnetMessage
+	Commitments int                  `json:"commitments"` // Number of commitments collected before reveal opened
+	Sealed      []*VoteCommitMessage `json:"sealed"`      // The signed commitments, proving the quorum
+}
+
+// MinerChunkMessage carries one incremental piece of a streamed miner output.
+// Digest is the rolling digest after this chunk, so the final response's StreamDigest
+// commits to every chunk in order.
+type MinerChunkMessage struct {
+	SubnetMessage
+	InputNumber int    `json:"input_number"`
+	Sequence    int    `json:"sequence"` // 0-based chunk index
+	Chunk       string `json:"chunk"`
+	Digest      string `json:"digest"`
+}
+
+// HeartbeatMessage probes a validator's liveness; the reply carries the validator's clock
+// so a new leader can merge it at handover
+type HeartbeatMessage struct {
+	SubnetMessage
+	Epoch    int        `json:"epoch"`
+	VLCClock *vlc.Clock `json:"vlc_clock,omitempty"`
+}
+
+// LeaderHandoverMessage names the leader of an epoch. The named validator takes over with
+// VLCClock (the previous leader's round-end clock); any other receiver steps down.
+type LeaderHandoverMessage struct {
+	SubnetMessage
+	Epoch    int        `json:"epoch"`
+	Leader   string     `json:"leader"`
+	VLCClock *vlc.Clock `json:"vlc_clock,omitempty"`
+}
+
+// RoundRequestMessage asks the epoch's leader to run one round for a user input
+type RoundRequestMessage struct {
+	SubnetMessage
+	Epoch       int    `json:"epoch"`
+	InputNumber int    `json:"input_number"`
+	Input       string `json:"input"`
+}
+
+// RoundResultMessage reports a round run by the epoch's leader: the round's record for the
+// epoch (nil if the round was dropped) and the leader's clock at the end of the round
+type RoundResultMessage struct {
+	SubnetMessage
+	InputNumber int        `json:"input_number"`
+	Round       *RoundData `json:"round,omitempty"`
+	VLCClock    *vlc.Clock `json:"vlc_clock,omitempty"`
+}
+
+// ClockCheckpointMessage tells peer validators which miner response the sender accepted at a
+// miner clock entry. The embedded response is miner-signed, so peers check its clock themselves
+// and two conflicting checkpoints for one entry prove the miner forked its history.
+type ClockCheckpointMessage struct {
+	SubnetMessage
+	MinerID  string                `json:"miner_id"`
+	ClockID  uint64                `json:"clock_id"` // Miner's VLC node ID
+	Entry    uint64                `json:"entry"`    // Miner's entry in the response clock
+	Response *MinerResponseMessage `json:"response"`
 }
 
 // InfoRequestMessage represents validator requesting more info from user
@@ -87,36 +231,48 @@ type InfoRequestMessage struct {
 // AdditionalInfoMessage represents user providing additional information
 type AdditionalInfoMessage struct {
 	SubnetMessage
-	AdditionalInfo string `json:"additional_info"`
+	AdditionalInfo string       `json:"additional_info"`
+	History        []DialogTurn `json:"history,omitempty"`        // Every clarification turn so far (multi-turn dialogs)
+	OriginalInput  string     `json:"original_input,omitempty"` // Task the clarification refers to
+	InputNumber    int        `json:"input_number,omitempty"`   // Sequential input identifier for tracking
+	VLCClock       *vlc.Clock `json:"vlc_clock,omitempty"`      // Sender's clock, merged by the miner for causal sync
 }
 
 // FinalOutputMessage represents the final output delivered to user
 type FinalOutputMessage struct {
 	SubnetMessage
-	Output       string  `json:"output"`
-	Accepted     bool    `json:"accepted"`
-	UserRejected bool    `json:"user_rejected,omitempty"`
-	Consensus    float64 `json:"consensus"` // Total acceptance weight
+	Output       string     `json:"output"`
+	Accepted     bool       `json:"accepted"`
+	UserRejected bool       `json:"user_rejected,omitempty"`
+	Consensus    float64    `json:"consensus"`              // Total acceptance weight
+	InputNumber  int        `json:"input_number,omitempty"` // Sequential input identifier for tracking
+	VLCClock     *vlc.Clock `json:"vlc_clock,omitempty"`    // Round-end clock, merged by the miner
 }
 
 // QualityAssessment tracks and aggregates validator consensus on miner output quality.
 // Implements Byzantine Fault Tolerant (BFT) consensus by accumulating weighted votes.
-// Consensus is reached when sufficient validators have voted (determined by total weight).
+// Consensus is reached when one side's weight reaches the quorum threshold of the
+// registered validator weight (see ConsensusConfig).
 type QualityAssessment struct {
-	RequestID   string  // Unique identifier for the request being assessed
-	TotalWeight float64 // Sum of all validator weights that have voted
-	AcceptVotes float64 // Sum of weights from validators who accepted the output
-	RejectVotes float64 // Sum of weights from validators who rejected the output
-	VoteCount   int     // Total number of validator votes received
-	Consensus   bool    // Whether sufficient votes have been received for consensus
+	RequestID     string          // Unique identifier for the request being assessed
+	Config        ConsensusConfig // Quorum rules (zero value: 2/3 of weight 1.0)
+	TotalWeight   float64         // Sum of all validator weights that have voted accept or reject
+	AcceptVotes   float64         // Sum of weights from validators who accepted the output
+	RejectVotes   float64         // Sum of weights from validators who rejected the output
+	AbstainWeight float64         // Sum of weights from validators who abstained
+	VoteCount     int             // Total number of validator votes received (including abstentions)
+	Consensus     bool            // Whether accept or reject weight has reached the quorum
+	Closed        bool            // Whether voting has been closed (no further votes expected)
+	Scores        []WeightedScore // Quality scores of non-abstaining votes, for aggregation
 }
 
 // AddVote incorporates a validator's vote into the consensus assessment.
 // Accumulates voting weights and determines if consensus threshold is reached.
 //
 // Consensus Logic:
-//   - Consensus achieved when >50% of total voting weight participates
-//   - Acceptance requires >50% of participating weight to vote "accept"
+//   - Consensus achieved when accept or reject weight reaches Quorum × RegisteredWeight
+//   - Validators that have not voted count toward neither side, so a quorum of 2/3
+//     needs 2/3 of the whole validator set, not 2/3 of those who showed up
 //   - This implements Byzantine Fault Tolerant consensus for quality assessment
 //
 // Parameters:
@@ -132,18 +288,82 @@ func (qa *QualityAssessment) AddVote(weight float64, accept bool) {
 		qa.RejectVotes += weight
 	}
 
-	// Consensus reached if > 50% weight votes (BFT threshold)
-	qa.Consensus = qa.AcceptVotes > 0.5 || qa.RejectVotes > 0.5
+	qa.updateConsensus()
 }
 
-// IsAccepted returns true if the consensus assessment indicates output acceptance.
-// Requires both consensus achievement and majority acceptance votes.
+// AddQualityVote incorporates a vote together with the validator's quality score.
+// The score feeds Aggregate(); the accept decision counts toward the quorum as in AddVote.
+func (qa *QualityAssessment) AddQualityVote(weight float64, accept bool, quality float64) {
+	qa.Scores = append(qa.Scores, WeightedScore{Quality: quality, Weight: weight})
+	qa.AddVote(weight, accept)
+}
+
+// Aggregate returns robust statistics over the quality scores received so far
+func (qa *QualityAssessment) Aggregate() QualityAggregate {
+	return AggregateQuality(qa.Scores, DefaultTrimFraction)
+}
+
+// QualityScore returns the consensus quality: the weighted median of validator scores.
+// Used for payment release and reputation, so a minority of outlier validators cannot
+// inflate or deflate the result.
+func (qa *QualityAssessment) QualityScore() float64 {
+	return qa.Aggregate().WeightedMedian
+}
+
+// AddAbstention records a validator that participated but declined to judge the output.
+// Abstained weight counts toward neither accept nor reject and does not lower the
+// quorum threshold, so enough abstentions lead to OutcomeQuorumNotReached.
+func (qa *QualityAssessment) AddAbstention(weight float64) {
+	qa.AbstainWeight += weight
+	qa.VoteCount++
+	qa.updateConsensus()
+}
+
+// Close marks voting as finished; an undecided assessment becomes OutcomeQuorumNotReached
+func (qa *QualityAssessment) Close() {
+	qa.Closed = true
+}
+
+// updateConsensus recomputes whether either side has reached the quorum threshold
+func (qa *QualityAssessment) updateConsensus() {
+	threshold := qa.Config.normalized().Threshold() - quorumEpsilon
+	qa.Consensus = qa.AcceptVotes >= threshold || qa.RejectVotes >= threshold
+}
+
+// Outcome returns the current consensus decision.
 //
-// Returns true only if:
-//   1. Consensus threshold reached (>50% validator weight participated)
-//   2. Majority of participating validators voted to accept (>50% of votes)
+// Returns:
+//   - OutcomeAccepted / OutcomeRejected once either side reaches the threshold
+//   - OutcomeQuorumNotReached if voting is closed or the weight still outstanding
+//     cannot lift either side to the threshold
+//   - OutcomePending otherwise
+func (qa *QualityAssessment) Outcome() ConsensusOutcome {
+	config := qa.Config.normalized()
+	threshold := config.Threshold() - quorumEpsilon
+
+	switch {
+	case qa.AcceptVotes >= threshold:
+		return OutcomeAccepted
+	case qa.RejectVotes >= threshold:
+		return OutcomeRejected
+	case qa.Closed:
+		return OutcomeQuorumNotReached
+	}
+
+	outstanding := config.RegisteredWeight - qa.TotalWeight - qa.AbstainWeight
+	if outstanding < 0 {
+		outstanding = 0
+	}
+	if qa.AcceptVotes+outstanding < threshold && qa.RejectVotes+outstanding < threshold {
+		return OutcomeQuorumNotReached
+	}
+	return OutcomePending
+}
+
+// IsAccepted returns true if the consensus assessment indicates output acceptance.
+// Requires accept weight to reach Quorum × RegisteredWeight.
 func (qa *QualityAssessment) IsAccepted() bool {
-	return qa.Consensus && qa.AcceptVotes > 0.5
+	return qa.Outcome() == OutcomeAccepted
 }
 
 // ============================================================================
@@ -0,0 +1,338 @@
+// Package subnet - Crash-Safe Miner State Journal
+//
+// This file gives a miner a write-ahead journal (JSONL, fsync'd per entry) of every state
+// transition that touches its VLC clock, so a restarted miner resumes where it stopped
+// instead of at zero, which validators would reject.
+//
+// Journaled transitions:
+//   - received: a request is admitted, its entering increment applied and the next miner
+//     entry reserved for its leaving one. Written first; if the write fails, the clock
+//     does not move and the request is not processed
+//   - completed: the leaving increment of one step, on its reserved entry, with the
+//     resulting response. Steps may complete out of admission order.
+//     Written before the increment is applied or the response leaves the miner; if the
+//     write fails, the clock does not move and the step yields a TaskFailed response
+//   - claimed: a paid task's payment was claimed for execution (no clock change). Written
+//     before the task runs; if the write fails, the task is refused
+//   - merged: a validator clock merged in (UpdateValidatorClock)
+//   - reset: the clock was reset (ResetClock)
+//
+// Recovery replays the entries into the clock, processing history, request traces and
+// payment claims. A request admitted but never completed keeps its journaled admission:
+// when the leader retries it, the miner resumes that step instead of admitting it again,
+// so the retried response still lands exactly 2 after the previous one.
+// A torn final line (crash mid-write) is rolled back; anything else unreadable is an error.
+// Recovered responses are cached (see idempotency.go): one journaled but lost in transit is
+// re-sent, unchanged, when the leader retries the request, so validators still see the miner
+// entry advance by exactly 2.
+package subnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// JournalEntryKind identifies a journaled miner state transition
+type JournalEntryKind string
+
+const (
+	JournalReceived  JournalEntryKind = "received"  // Request admitted, entering increment applied, leaving one reserved
+	JournalCompleted JournalEntryKind = "completed" // Leaving increment applied; carries the response
+	JournalClaimed   JournalEntryKind = "claimed"   // Payment claimed for an execution
+	JournalMerged    JournalEntryKind = "merged"    // Validator clock merged in
+	JournalReset     JournalEntryKind = "reset"     // Clock reset to zero
+)
+
+var (
+	// ErrJournalCorrupt is returned when a journal contains an unreadable entry before its last line
+	ErrJournalCorrupt = errors.New("miner journal corrupt")
+	// ErrJournalMismatch is returned when a journal was written by a different miner or VLC node ID
+	ErrJournalMismatch = errors.New("miner journal belongs to another miner")
+)
+
+// JournalEntry is one line of a miner journal
+type JournalEntry struct {
+	Seq         uint64                `json:"seq"`
+	Kind        JournalEntryKind      `json:"kind"`
+	MinerID     string                `json:"miner_id"`
+	ClockID     uint64                `json:"clock_id"`
+	RequestID   string                `json:"request_id,omitempty"`
+	InputNumber int                   `json:"input_number,omitempty"`
+	Clock       *vlc.Clock            `json:"clock"`              // Miner clock after the transition; for completed, the step's left clock
+	Response    *MinerResponseMessage `json:"response,omitempty"` // Completed step's response (unsigned)
+	At          time.Time             `json:"at"`
+}
+
+// JournalRecovery summarizes the state a miner rebuilt from its journal
+type JournalRecovery struct {
+	Entries     int        // Entries replayed
+	Completed   int        // Steps completed since the last reset
+	Clock       *vlc.Clock // Recovered miner clock
+	Interrupted []string   // Requests admitted but never completed (in flight at the crash; resumed when retried)
+}
+
+// MinerJournal is an append-only, fsync'd JSONL log of miner state transitions.
+// Safe for concurrent use.
+type MinerJournal struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+	size int64  // Bytes of complete entries; a failed append is truncated back to this
+	seq  uint64 // Sequence number of the last entry
+}
+
+// OpenMinerJournal opens (or creates) the journal at path and returns its entries.
+// A torn final line is truncated away.
+func OpenMinerJournal(path string) (*MinerJournal, []JournalEntry, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	entries, size, err := parseJournal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	if size < int64(len(data)) {
+		fmt.Printf("⚠️  Miner journal %s: discarding %d byte(s) of a torn final entry\n", path, int64(len(data))-size)
+		if err := file.Truncate(size); err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to roll back torn journal entry: %w", err)
+		}
+	}
+	if _, err := file.Seek(size, 0); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to seek journal: %w", err)
+	}
+
+	journal := &MinerJournal{path: path, file: file, size: size}
+	if len(entries) > 0 {
+		journal.seq = entries[len(entries)-1].Seq
+	}
+	return journal, entries, nil
+}
+
+// parseJournal decodes journal lines and returns the entries and the length of the
+// well-formed prefix. Only the final line may be incomplete.
+func parseJournal(data []byte) ([]JournalEntry, int64, error) {
+	var entries []JournalEntry
+	var offset int64
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return entries, offset, nil // Torn final line: never fsync'd as a whole
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(data[:end], &entry); err != nil {
+			return nil, 0, fmt.Errorf("%w: entry %d: %v", ErrJournalCorrupt, len(entries)+1, err)
+		}
+		if entry.Seq != uint64(len(entries))+1 {
+			return nil, 0, fmt.Errorf("%w: entry %d has sequence %d", ErrJournalCorrupt, len(entries)+1, entry.Seq)
+		}
+		entries = append(entries, entry)
+		offset += int64(end) + 1
+		data = data[end+1:]
+	}
+	return entries, offset, nil
+}
+
+// Append assigns the entry's sequence number and durably writes it. On failure the
+// partial write is rolled back and the journal is unchanged.
+func (j *MinerJournal) Append(entry *JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return errors.New("miner journal closed")
+	}
+	entry.Seq = j.seq + 1
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := j.file.Write(line); err != nil {
+		j.rollbackLocked()
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		j.rollbackLocked()
+		return fmt.Errorf("failed to sync journal entry: %w", err)
+	}
+	j.seq = entry.Seq
+	j.size += int64(len(line))
+	return nil
+}
+
+// rollbackLocked truncates a failed append (caller holds j.mu)
+func (j *MinerJournal) rollbackLocked() {
+	if err := j.file.Truncate(j.size); err != nil {
+		fmt.Printf("⚠️  Miner journal %s: failed to roll back partial entry: %v\n", j.path, err)
+	}
+	j.file.Seek(j.size, 0)
+}
+
+// Path returns the journal's file path
+func (j *MinerJournal) Path() string {
+	return j.path
+}
+
+// Close closes the journal file
+func (j *MinerJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// EnableJournal opens the journal at path, rebuilds the miner's clock, processing history
+// and request traces from it, and journals every later state transition. Call before the
+// miner serves requests, after SetClockID.
+func (m *CoreMiner) EnableJournal(path string) (*JournalRecovery, error) {
+	journal, entries, err := OpenMinerJournal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	recovery, err := m.replayJournalLocked(entries)
+	if err != nil {
+		journal.Close()
+		return nil, err
+	}
+	m.journal = journal
+	return recovery, nil
+}
+
+// replayJournalLocked rebuilds miner state from journal entries (caller holds m.mu).
+// State is built aside and assigned only once every entry has replayed, so a journal that
+// fails to replay leaves the miner untouched.
+func (m *CoreMiner) replayJournalLocked(entries []JournalEntry) (*JournalRecovery, error) {
+	clock := vlc.New()
+	processed := make(map[int]*MinerResponseMessage)
+	traces := make(map[string]*RequestTrace)
+	cache := make(map[string]*cachedResponse)
+	claims := make(map[string]bool)
+	var reserved uint64
+	open := make(map[string]*vlc.Clock) // requestID -> received clock of a step admitted but not completed
+	completed := 0
+
+	for _, entry := range entries {
+		if entry.MinerID != m.ID || entry.ClockID != m.ClockID {
+			return nil, fmt.Errorf("%w: entry %d is %s on VLC node %d, this miner is %s on %d",
+				ErrJournalMismatch, entry.Seq, entry.MinerID, entry.ClockID, m.ID, m.ClockID)
+		}
+		if entry.Clock == nil {
+			return nil, fmt.Errorf("%w: entry %d carries no clock", ErrJournalCorrupt, entry.Seq)
+		}
+
+		switch entry.Kind {
+		case JournalReceived:
+			received := entry.Clock.Copy()
+			received.Values[m.ClockID]--
+			replayEvent(traces, m.ClockID, entry, RequestReceived, received)
+			replayEvent(traces, m.ClockID, entry, RequestEntered, entry.Clock)
+			open[entry.RequestID] = received
+			if next := entry.Clock.Values[m.ClockID] + 1; next > reserved {
+				reserved = next
+			}
+		case JournalCompleted:
+			if entry.Response == nil {
+				return nil, fmt.Errorf("%w: completed entry %d carries no response", ErrJournalCorrupt, entry.Seq)
+			}
+			replayEvent(traces, m.ClockID, entry, RequestLeft, entry.Clock)
+			processed[entry.InputNumber] = entry.Response
+			cache[responseKey(entry.RequestID, entry.Response.Turn)] = &cachedResponse{response: entry.Response, completed: entry.At}
+			delete(open, entry.RequestID)
+			completed++
+		case JournalClaimed:
+			claims[entry.RequestID] = true
+		case JournalMerged:
+		case JournalReset:
+			// Everything before a reset refers to the discarded clock (see ResetClock);
+			// payment claims do not depend on the clock and are kept
+			processed = make(map[int]*MinerResponseMessage)
+			traces = make(map[string]*RequestTrace)
+			cache = make(map[string]*cachedResponse)
+			open = make(map[string]*vlc.Clock)
+			completed = 0
+			reserved = 0
+			clock = entry.Clock.Copy()
+			continue
+		default:
+			return nil, fmt.Errorf("%w: entry %d has unknown kind %q", ErrJournalCorrupt, entry.Seq, entry.Kind)
+		}
+		// Completions may be journaled out of admission order; the clock only moves forward
+		clock.Merge([]*vlc.Clock{entry.Clock})
+	}
+
+	m.VLCClock = clock
+	m.processedInputs = processed
+	m.requestTraces = traces
+	m.responseCache = cache
+	m.claims = claims
+	m.interrupted = open
+	m.reservedEntry = reserved
+
+	recovery := &JournalRecovery{Entries: len(entries), Completed: completed, Clock: clock.Copy()}
+	for requestID := range open {
+		recovery.Interrupted = append(recovery.Interrupted, requestID)
+	}
+	return recovery, nil
+}
+
+// replayEvent adds a journaled event to its request's trace
+func replayEvent(traces map[string]*RequestTrace, clockID uint64, entry JournalEntry, kind RequestEventKind, clock *vlc.Clock) {
+	trace, ok := traces[entry.RequestID]
+	if !ok {
+		trace = &RequestTrace{RequestID: entry.RequestID, ClockID: clockID}
+		traces[entry.RequestID] = trace
+	}
+	trace.Events = append(trace.Events, RequestEvent{
+		Kind:        kind,
+		InputNumber: entry.InputNumber,
+		Clock:       clock.Copy(),
+		At:          entry.At,
+	})
+}
+
+// journalLocked appends a state transition to the miner's journal, if enabled (caller holds m.mu)
+func (m *CoreMiner) journalLocked(kind JournalEntryKind, requestID string, inputNumber int, clock *vlc.Clock, response *MinerResponseMessage) error {
+	if m.journal == nil {
+		return nil
+	}
+	return m.journal.Append(&JournalEntry{
+		Kind:        kind,
+		MinerID:     m.ID,
+		ClockID:     m.ClockID,
+		RequestID:   requestID,
+		InputNumber: inputNumber,
+		Clock:       clock,
+		Response:    response,
+		At:          time.Now(),
+	})
+}
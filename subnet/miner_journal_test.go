@@ -0,0 +1,244 @@
+package subnet
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newJournaledMiner creates a miner journaling to path and returns what it recovered
+func newJournaledMiner(t *testing.T, path string) (*CoreMiner, *JournalRecovery) {
+	t.Helper()
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(sleepyProcessor{})
+	recovery, err := miner.EnableJournal(path)
+	if err != nil {
+		t.Fatalf("EnableJournal: %v", err)
+	}
+	t.Cleanup(func() { miner.journal.Close() })
+	return miner, recovery
+}
+
+func TestMinerJournalRecoversFromTornEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "miner-1.jsonl")
+	miner, _ := newJournaledMiner(t, path)
+	first := miner.ProcessInput("task 1", 1, "req-1")
+	miner.ProcessInput("task 2", 2, "req-2")
+	miner.journal.Close()
+
+	// Crash while the last entry (req-2's completion) was being written
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastLine := bytes.LastIndexByte(data[:len(data)-1], '\n') + 1
+	torn := data[:lastLine+(len(data)-lastLine)/2]
+	if err := os.WriteFile(path, torn, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, recovery := newJournaledMiner(t, path)
+	// req-1 completed (2), req-2 was admitted (+1) but its completion was torn away
+	if got := recovery.Clock.Values[MinerClockID]; got != 3 {
+		t.Fatalf("recovered clock = %d, want 3", got)
+	}
+	if got := restarted.GetCurrentClock().Values[MinerClockID]; got != 3 {
+		t.Fatalf("miner clock = %d, want 3", got)
+	}
+	if recovery.Completed != 1 || len(recovery.Interrupted) != 1 || recovery.Interrupted[0] != "req-2" {
+		t.Fatalf("recovery = %+v, want req-1 completed and req-2 interrupted", recovery)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(lastLine) {
+		t.Fatalf("journal not rolled back to %d bytes: %v, %v", lastLine, info.Size(), err)
+	}
+
+	// The journaled response is re-sent unchanged, without advancing the clock
+	cached := restarted.ProcessInput("task 1", 1, "req-1")
+	if cached.Output != first.Output || cached.OutputCommitment != first.OutputCommitment ||
+		cached.VLCClock.Values[MinerClockID] != 2 {
+		t.Fatalf("retried req-1 = %+v, want the journaled response", cached)
+	}
+	if got := restarted.GetCurrentClock().Values[MinerClockID]; got != 3 {
+		t.Fatalf("clock after cached retry = %d, want 3", got)
+	}
+	if trace := restarted.GetRequestTrace("req-1"); trace == nil || trace.Verify() != nil {
+		t.Fatalf("req-1 trace not recovered: %+v", trace)
+	}
+
+	// The interrupted request resumes its journaled admission instead of entering again
+	retried := restarted.ProcessInput("task 2", 2, "req-2")
+	if err := VerifyRequestCausality(retried, MinerClockID); err != nil {
+		t.Fatal(err)
+	}
+	if got := retried.VLCClock.Values[MinerClockID]; got != 4 {
+		t.Fatalf("retried req-2 clock = %d, want 4", got)
+	}
+	if trace := restarted.GetRequestTrace("req-2"); trace == nil || trace.Verify() != nil {
+		t.Fatalf("req-2 trace not completed: %+v", trace)
+	}
+
+	// A validator that saw req-1 accepts the retried response as the miner's next step
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+	validator.RegisterMiner(restarted.ID, MinerClockID)
+	if violation := validator.CheckSequence(MinerResponseType, first.VLCClock, MinerClockID); violation != nil {
+		t.Fatalf("req-1 rejected: %v", violation)
+	}
+	if violation := validator.CheckSequence(MinerResponseType, retried.VLCClock, MinerClockID); violation != nil {
+		t.Fatalf("retried req-2 rejected: %v", violation)
+	}
+}
+
+func TestMinerJournalResetClearsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "miner-1.jsonl")
+	miner, _ := newJournaledMiner(t, path)
+	miner.ProcessInput("validation", 1, "vlc-validation-test-1")
+	miner.ResetClock()
+	miner.ProcessInput("task 2", 2, "req-2")
+	miner.journal.Close()
+
+	restarted, recovery := newJournaledMiner(t, path)
+	if got := recovery.Clock.Values[MinerClockID]; got != 2 || recovery.Completed != 1 {
+		t.Fatalf("recovery = %+v, want clock 2 and one completed step", recovery)
+	}
+	processed := restarted.GetProcessedInputs()
+	if _, ok := processed[1]; ok || len(processed) != 1 {
+		t.Fatalf("processed inputs = %v, want only input 2", processed)
+	}
+	if restarted.GetRequestTrace("vlc-validation-test-1") != nil {
+		t.Fatal("trace from before the reset survived recovery")
+	}
+}
+
+func TestMinerJournalFailedReplayLeavesMinerUntouched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "miner-1.jsonl")
+	miner, _ := newJournaledMiner(t, path)
+	miner.ProcessInput("task 1", 1, "req-1")
+	miner.journal.Close()
+
+	// Valid entries followed by one from another miner: replay fails partway through
+	other := NewCoreMiner("miner-2", "subnet-test")
+	journal, _, err := OpenMinerJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Append(&JournalEntry{Kind: JournalMerged, MinerID: other.ID, ClockID: other.ClockID, Clock: other.GetCurrentClock()}); err != nil {
+		t.Fatal(err)
+	}
+	journal.Close()
+
+	fresh := NewCoreMiner("miner-1", "subnet-test")
+	if _, err := fresh.EnableJournal(path); !errors.Is(err, ErrJournalMismatch) {
+		t.Fatalf("EnableJournal error = %v, want %v", err, ErrJournalMismatch)
+	}
+	if got := fresh.GetCurrentClock().Values[MinerClockID]; got != 0 {
+		t.Fatalf("clock after failed replay = %d, want 0", got)
+	}
+	if len(fresh.GetProcessedInputs()) != 0 || fresh.GetRequestTrace("req-1") != nil {
+		t.Fatal("failed replay left partial state behind")
+	}
+}
+
+// paidVerifier reports every payment as locked in escrow
+type paidVerifier struct{}
+
+func (paidVerifier) VerifyPaymentLocked(taskID string, agentAddr common.Address, minAmount *big.Int) (bool, error) {
+	return true, nil
+}
+
+func TestMinerJournalKeepsPaymentClaims(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "miner-1.jsonl")
+	miner, _ := newJournaledMiner(t, path)
+	miner.SetPaymentVerifier(paidVerifier{}, "0x0000000000000000000000000000000000000001", "1")
+	if response := miner.ProcessInput("task 1", 1, "req-1"); response.OutputType != OutputReady || response.Output == "" {
+		t.Fatalf("paid task not executed: %+v", response)
+	}
+	miner.ResetClock() // Drops the cached response; claims do not depend on the clock
+	miner.journal.Close()
+
+	// Restarted with no cached response, the payment still funds no second execution
+	restarted, _ := newJournaledMiner(t, path)
+	restarted.SetPaymentVerifier(paidVerifier{}, "0x0000000000000000000000000000000000000001", "1")
+	before := restarted.GetCurrentClock().Values[MinerClockID]
+
+	response := restarted.ProcessInput("task 1", 1, "req-1")
+	if !strings.HasPrefix(response.Output, "PAYMENT_ALREADY_CLAIMED") {
+		t.Fatalf("re-executed a claimed payment: %+v", response)
+	}
+	if got := restarted.GetCurrentClock().Values[MinerClockID]; got != before {
+		t.Fatalf("refused task advanced the clock from %d to %d", before, got)
+	}
+	if err := restarted.claimExecution("req-2"); err != nil {
+		t.Fatalf("unclaimed payment refused: %v", err)
+	}
+}
+
+func TestMinerJournalResumesInterruptedPaidTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "miner-1.jsonl")
+	miner, _ := newJournaledMiner(t, path)
+	miner.SetPaymentVerifier(paidVerifier{}, "0x0000000000000000000000000000000000000001", "1")
+	miner.ProcessInput("task 1", 1, "req-1")
+	miner.journal.Close()
+
+	// Crash before the completion was written: the payment was claimed, the step never finished
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:bytes.LastIndexByte(data[:len(data)-1], '\n')+1], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, _ := newJournaledMiner(t, path)
+	restarted.SetPaymentVerifier(paidVerifier{}, "0x0000000000000000000000000000000000000001", "1")
+	response := restarted.ProcessInput("task 1", 1, "req-1")
+	if response.OutputType != OutputReady || response.Output != "done: task 1" {
+		t.Fatalf("interrupted paid task not resumed: %+v", response)
+	}
+	if got := response.VLCClock.Values[MinerClockID]; got != 2 {
+		t.Fatalf("resumed req-1 clock = %d, want 2", got)
+	}
+
+	// Resuming used up the interruption; the payment funds nothing more
+	if again := restarted.claimExecution("req-1"); !errors.Is(again, ErrPaymentAlreadyClaimed) {
+		t.Fatalf("claim after resuming = %v, want %v", again, ErrPaymentAlreadyClaimed)
+	}
+}
+
+func TestMinerJournalReplaysOverlappingSteps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "miner-1.jsonl")
+	miner, _ := newJournaledMiner(t, path)
+	processor := gatedProcessor{
+		started: make(chan string),
+		gates:   map[string]chan struct{}{"first": make(chan struct{}), "second": make(chan struct{})},
+	}
+	miner.SetContextTaskProcessor(processor)
+
+	first := make(chan *MinerResponseMessage, 1)
+	go func() { first <- miner.ProcessInput("first", 1, "req-1") }()
+	<-processor.started
+	second := make(chan *MinerResponseMessage, 1)
+	go func() { second <- miner.ProcessInput("second", 2, "req-2") }()
+	<-processor.started
+
+	// req-2 completes (4) before req-1 (2); req-1's completion must not move the clock back
+	close(processor.gates["second"])
+	<-second
+	close(processor.gates["first"])
+	<-first
+	miner.journal.Close()
+
+	restarted, recovery := newJournaledMiner(t, path)
+	if got := recovery.Clock.Values[MinerClockID]; got != 4 || recovery.Completed != 2 {
+		t.Fatalf("recovery = %+v, want clock 4 and two completed steps", recovery)
+	}
+	next := restarted.ProcessInput("task 3", 3, "req-3")
+	if got := next.VLCClock.Values[MinerClockID]; got != 6 {
+		t.Fatalf("req-3 clock = %d, want 6", got)
+	}
+}
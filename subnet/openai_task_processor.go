@@ -0,0 +1,328 @@
+// Package subnet - OpenAI-Compatible Task Processor
+//
+// This file implements OpenAITaskProcessor, a ContextTaskProcessor and DialogTaskProcessor
+// backed by any server exposing the OpenAI chat-completions API (OpenAI, vLLM, llama.cpp,
+// Ollama, LiteLLM, ...). Point BaseURL at a local stub server to exercise it without a model.
+//
+// Deciding NeedMoreInfo vs OutputReady:
+//   - DecisionTool (default): the model is offered a request_clarification tool; calling
+//     it asks the user a question, answering in plain text delivers the output
+//   - DecisionJSON: the model must answer with a JSON object
+//     {"status": "ready" | "need_more_info", "output": "...", "question": "..."}
+//     for servers without tool-calling support
+//
+// Configuration comes from LLM_* environment variables (see OpenAIConfigFromEnv).
+package subnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Decision modes for OpenAIConfig.DecisionMode
+const (
+	DecisionTool = "tool" // Clarification via a tool call
+	DecisionJSON = "json" // Clarification via a structured JSON answer
+)
+
+const (
+	clarificationToolName = "request_clarification"
+	chatCompletionsPath   = "/chat/completions"
+
+	defaultLLMSystemPrompt = "You are an AI agent completing tasks for users. " +
+		"Answer the task directly and completely. Ask the user a clarifying question only " +
+		"when the task cannot be completed well without more information."
+
+	jsonDecisionInstructions = "Respond only with a JSON object: " +
+		`{"status": "ready", "output": "<your answer>"} when you can complete the task, or ` +
+		`{"status": "need_more_info", "question": "<one question for the user>"} when you need clarification.`
+)
+
+// ErrModelResponse is returned when the model server's reply cannot be used
+var ErrModelResponse = errors.New("unusable model response")
+
+// OpenAIConfig configures an OpenAITaskProcessor
+type OpenAIConfig struct {
+	BaseURL      string       // API root including version, e.g. "http://localhost:8000/v1"
+	APIKey       string       // Bearer token (optional for local servers)
+	Model        string       // Model name sent with every request
+	SystemPrompt string       // System message (empty: a generic task-completion prompt)
+	MaxTokens    int          // Completion token limit (zero: server default)
+	Temperature  *float64     // Sampling temperature (nil: server default)
+	DecisionMode string       // DecisionTool or DecisionJSON (empty: DecisionTool)
+	HTTPClient   *http.Client // Client used for requests (nil: http.DefaultClient; deadlines come from the task context)
+}
+
+// OpenAIConfigFromEnv reads the processor configuration:
+//   - LLM_BASE_URL (default http://localhost:8000/v1), LLM_API_KEY, LLM_MODEL
+//   - LLM_SYSTEM_PROMPT, LLM_MAX_TOKENS, LLM_TEMPERATURE
+//   - LLM_DECISION_MODE: "tool" or "json"
+func OpenAIConfigFromEnv() (OpenAIConfig, error) {
+	config := OpenAIConfig{
+		BaseURL:      os.Getenv("LLM_BASE_URL"),
+		APIKey:       os.Getenv("LLM_API_KEY"),
+		Model:        os.Getenv("LLM_MODEL"),
+		SystemPrompt: os.Getenv("LLM_SYSTEM_PROMPT"),
+		DecisionMode: os.Getenv("LLM_DECISION_MODE"),
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "http://localhost:8000/v1"
+	}
+
+	if value := os.Getenv("LLM_MAX_TOKENS"); value != "" {
+		maxTokens, err := strconv.Atoi(value)
+		if err != nil || maxTokens < 0 {
+			return config, fmt.Errorf("invalid LLM_MAX_TOKENS %q", value)
+		}
+		config.MaxTokens = maxTokens
+	}
+	if value := os.Getenv("LLM_TEMPERATURE"); value != "" {
+		temperature, err := strconv.ParseFloat(value, 64)
+		if err != nil || temperature < 0 {
+			return config, fmt.Errorf("invalid LLM_TEMPERATURE %q", value)
+		}
+		config.Temperature = &temperature
+	}
+	return config, nil
+}
+
+// OpenAITaskProcessor processes tasks with a chat-completions model server.
+// It is safe for concurrent use.
+type OpenAITaskProcessor struct {
+	config OpenAIConfig
+	client *http.Client
+}
+
+// NewOpenAITaskProcessor validates the configuration and creates the processor
+func NewOpenAITaskProcessor(config OpenAIConfig) (*OpenAITaskProcessor, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("LLM base URL is required")
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("LLM model is required")
+	}
+	switch config.DecisionMode {
+	case "":
+		config.DecisionMode = DecisionTool
+	case DecisionTool, DecisionJSON:
+	default:
+		return nil, fmt.Errorf("unknown LLM decision mode %q (expected %s or %s)", config.DecisionMode, DecisionTool, DecisionJSON)
+	}
+	if config.SystemPrompt == "" {
+		config.SystemPrompt = defaultLLMSystemPrompt
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OpenAITaskProcessor{config: config, client: client}, nil
+}
+
+// DecisionMode returns how the processor tells NeedMoreInfo from OutputReady
+func (p *OpenAITaskProcessor) DecisionMode() string {
+	return p.config.DecisionMode
+}
+
+// ProcessTask asks the model to complete a task or ask a clarifying question
+func (p *OpenAITaskProcessor) ProcessTask(ctx context.Context, input string, inputNumber int) (*TaskOutcome, error) {
+	return p.ProcessDialog(ctx, input, nil, inputNumber)
+}
+
+// ProcessAdditionalInfo completes a task with one answered clarification
+func (p *OpenAITaskProcessor) ProcessAdditionalInfo(ctx context.Context, originalInput string, additionalInfo string, inputNumber int) (*TaskOutcome, error) {
+	return p.ProcessDialog(ctx, originalInput, []DialogTurn{{Answer: additionalInfo}}, inputNumber)
+}
+
+// ProcessDialog sends the task and every clarification turn so far to the model
+func (p *OpenAITaskProcessor) ProcessDialog(ctx context.Context, originalInput string, history []DialogTurn, inputNumber int) (*TaskOutcome, error) {
+	request := p.newChatRequest(originalInput, history)
+
+	reply, err := p.complete(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.config.DecisionMode == DecisionJSON {
+		return parseJSONDecision(reply.Content)
+	}
+	return parseToolDecision(reply)
+}
+
+// chatMessage is one message of a chat-completions conversation
+type chatMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	ToolCalls []chatToolCall `json:"tool_calls,omitempty"`
+}
+
+// chatToolCall is a tool invocation requested by the model
+type chatToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON-encoded arguments
+	} `json:"function"`
+}
+
+// chatRequest is the chat-completions request body
+type chatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	MaxTokens      int               `json:"max_tokens,omitempty"`
+	Temperature    *float64          `json:"temperature,omitempty"`
+	Tools          []json.RawMessage `json:"tools,omitempty"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+// chatResponse is the subset of the chat-completions response the processor reads
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// clarificationTool is the tool definition offered in DecisionTool mode
+var clarificationTool = json.RawMessage(`{
+	"type": "function",
+	"function": {
+		"name": "` + clarificationToolName + `",
+		"description": "Ask the user one question when the task cannot be completed without more information.",
+		"parameters": {
+			"type": "object",
+			"properties": {"question": {"type": "string", "description": "The question to ask the user"}},
+			"required": ["question"]
+		}
+	}
+}`)
+
+// newChatRequest builds the conversation: system prompt, task, then each question/answer pair
+func (p *OpenAITaskProcessor) newChatRequest(originalInput string, history []DialogTurn) *chatRequest {
+	systemPrompt := p.config.SystemPrompt
+	if p.config.DecisionMode == DecisionJSON {
+		systemPrompt += "\n\n" + jsonDecisionInstructions
+	}
+
+	messages := []chatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: originalInput},
+	}
+	for _, turn := range history {
+		if turn.Question != "" {
+			messages = append(messages, chatMessage{Role: "assistant", Content: turn.Question})
+		}
+		messages = append(messages, chatMessage{Role: "user", Content: turn.Answer})
+	}
+
+	request := &chatRequest{
+		Model:       p.config.Model,
+		Messages:    messages,
+		MaxTokens:   p.config.MaxTokens,
+		Temperature: p.config.Temperature,
+	}
+	if p.config.DecisionMode == DecisionJSON {
+		request.ResponseFormat = map[string]string{"type": "json_object"}
+	} else {
+		request.Tools = []json.RawMessage{clarificationTool}
+	}
+	return request
+}
+
+// complete posts a chat-completions request and returns the first choice's message
+func (p *OpenAITaskProcessor) complete(ctx context.Context, request *chatRequest) (*chatMessage, error) {
+	return postChatCompletion(ctx, p.client, p.config.BaseURL, p.config.APIKey, request)
+}
+
+// postChatCompletion posts a chat-completions request to baseURL and returns the first choice's message
+func postChatCompletion(ctx context.Context, client *http.Client, baseURL, apiKey string, request *chatRequest) (*chatMessage, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chat request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+chatCompletionsPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("model server unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d: %s", ErrModelResponse, resp.StatusCode, truncateString(string(bytes.TrimSpace(respBody)), 200))
+	}
+
+	var completion chatResponse
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrModelResponse, err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("%w: no choices", ErrModelResponse)
+	}
+	return &completion.Choices[0].Message, nil
+}
+
+// parseToolDecision maps a DecisionTool reply to an outcome
+func parseToolDecision(reply *chatMessage) (*TaskOutcome, error) {
+	for _, call := range reply.ToolCalls {
+		if call.Function.Name != clarificationToolName {
+			continue
+		}
+		var args struct {
+			Question string `json:"question"`
+		}
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil || strings.TrimSpace(args.Question) == "" {
+			return nil, fmt.Errorf("%w: malformed %s arguments %q", ErrModelResponse, clarificationToolName, call.Function.Arguments)
+		}
+		return &TaskOutcome{OutputType: NeedMoreInfo, InfoRequest: strings.TrimSpace(args.Question)}, nil
+	}
+
+	output := strings.TrimSpace(reply.Content)
+	if output == "" {
+		return nil, fmt.Errorf("%w: empty answer", ErrModelResponse)
+	}
+	return &TaskOutcome{OutputType: OutputReady, Output: output}, nil
+}
+
+// parseJSONDecision maps a DecisionJSON reply to an outcome
+func parseJSONDecision(content string) (*TaskOutcome, error) {
+	var decision struct {
+		Status   string `json:"status"`
+		Output   string `json:"output"`
+		Question string `json:"question"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &decision); err != nil {
+		return nil, fmt.Errorf("%w: answer is not the requested JSON object: %v", ErrModelResponse, err)
+	}
+
+	switch {
+	case decision.Status == "need_more_info" && strings.TrimSpace(decision.Question) != "":
+		return &TaskOutcome{OutputType: NeedMoreInfo, InfoRequest: strings.TrimSpace(decision.Question)}, nil
+	case decision.Status == "ready" && strings.TrimSpace(decision.Output) != "":
+		return &TaskOutcome{OutputType: OutputReady, Output: strings.TrimSpace(decision.Output)}, nil
+	default:
+		return nil, fmt.Errorf("%w: status %q without matching output or question", ErrModelResponse, decision.Status)
+	}
+}
@@ -0,0 +1,214 @@
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newStubModelServer serves chat completions from reply and records each request it
+// receives; read the requests once the processor has returned
+func newStubModelServer(t *testing.T, reply http.HandlerFunc) (*httptest.Server, *[]chatRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1"+chatCompletionsPath {
+			http.NotFound(w, r)
+			return
+		}
+		var request chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, request)
+		mu.Unlock()
+		reply(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// completion replies with a single choice holding message
+func completion(message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"message": ` + message + `, "finish_reason": "stop"}]}`))
+	}
+}
+
+func newTestOpenAIProcessor(t *testing.T, server *httptest.Server, mode string) *OpenAITaskProcessor {
+	t.Helper()
+	processor, err := NewOpenAITaskProcessor(OpenAIConfig{
+		BaseURL:      server.URL + "/v1/",
+		Model:        "stub-model",
+		DecisionMode: mode,
+		HTTPClient:   server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("NewOpenAITaskProcessor: %v", err)
+	}
+	return processor
+}
+
+func TestOpenAITaskProcessorOutcomes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		message  string
+		want     TaskOutcome
+		wantTool bool
+	}{
+		{
+			name:     "output ready",
+			mode:     DecisionTool,
+			message:  `{"role": "assistant", "content": "  the answer  "}`,
+			want:     TaskOutcome{OutputType: OutputReady, Output: "the answer"},
+			wantTool: true,
+		},
+		{
+			name: "need more info via tool call",
+			mode: DecisionTool,
+			message: `{"role": "assistant", "content": "", "tool_calls": [{"id": "call-1", "type": "function",
+				"function": {"name": "request_clarification", "arguments": "{\"question\": \"Which format?\"}"}}]}`,
+			want:     TaskOutcome{OutputType: NeedMoreInfo, InfoRequest: "Which format?"},
+			wantTool: true,
+		},
+		{
+			name:    "output ready via structured output",
+			mode:    DecisionJSON,
+			message: `{"role": "assistant", "content": "{\"status\": \"ready\", \"output\": \"the answer\"}"}`,
+			want:    TaskOutcome{OutputType: OutputReady, Output: "the answer"},
+		},
+		{
+			name:    "need more info via structured output",
+			mode:    DecisionJSON,
+			message: `{"role": "assistant", "content": "{\"status\": \"need_more_info\", \"question\": \"Which format?\"}"}`,
+			want:    TaskOutcome{OutputType: NeedMoreInfo, InfoRequest: "Which format?"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newStubModelServer(t, completion(tt.message))
+			processor := newTestOpenAIProcessor(t, server, tt.mode)
+
+			outcome, err := processor.ProcessTask(context.Background(), "write a report", 1)
+			if err != nil {
+				t.Fatalf("ProcessTask: %v", err)
+			}
+			if *outcome != tt.want {
+				t.Fatalf("outcome = %+v, want %+v", *outcome, tt.want)
+			}
+
+			if len(*requests) != 1 {
+				t.Fatalf("server saw %d requests, want 1", len(*requests))
+			}
+			request := (*requests)[0]
+			if request.Model != "stub-model" || len(request.Messages) != 2 || request.Messages[1].Content != "write a report" {
+				t.Fatalf("unexpected request %+v", request)
+			}
+			if got := len(request.Tools) == 1; got != tt.wantTool {
+				t.Fatalf("clarification tool offered = %v, want %v", got, tt.wantTool)
+			}
+			if !tt.wantTool && request.ResponseFormat["type"] != "json_object" {
+				t.Fatalf("response format = %v, want json_object", request.ResponseFormat)
+			}
+		})
+	}
+}
+
+func TestOpenAITaskProcessorDialogHistory(t *testing.T) {
+	server, requests := newStubModelServer(t, completion(`{"role": "assistant", "content": "done"}`))
+	processor := newTestOpenAIProcessor(t, server, DecisionTool)
+
+	history := []DialogTurn{{Question: "Which format?", Answer: "PDF"}}
+	if _, err := processor.ProcessDialog(context.Background(), "write a report", history, 1); err != nil {
+		t.Fatalf("ProcessDialog: %v", err)
+	}
+	messages := (*requests)[0].Messages
+	if len(messages) != 4 || messages[2].Role != "assistant" || messages[2].Content != "Which format?" ||
+		messages[3].Role != "user" || messages[3].Content != "PDF" {
+		t.Fatalf("dialog sent as %+v", messages)
+	}
+}
+
+func TestOpenAITaskProcessorErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		reply http.HandlerFunc
+	}{
+		{
+			name: "non-200 reply",
+			mode: DecisionTool,
+			reply: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "model overloaded", http.StatusServiceUnavailable)
+			},
+		},
+		{
+			name: "malformed JSON body",
+			mode: DecisionTool,
+			reply: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"choices": [`))
+			},
+		},
+		{
+			name:  "no choices",
+			mode:  DecisionTool,
+			reply: func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(`{"choices": []}`)) },
+		},
+		{
+			name:  "malformed tool arguments",
+			mode:  DecisionTool,
+			reply: completion(`{"role": "assistant", "tool_calls": [{"function": {"name": "request_clarification", "arguments": "{"}}]}`),
+		},
+		{
+			name:  "malformed structured output",
+			mode:  DecisionJSON,
+			reply: completion(`{"role": "assistant", "content": "Sure! Here is the answer."}`),
+		},
+		{
+			name:  "structured output without question",
+			mode:  DecisionJSON,
+			reply: completion(`{"role": "assistant", "content": "{\"status\": \"need_more_info\"}"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newStubModelServer(t, tt.reply)
+			processor := newTestOpenAIProcessor(t, server, tt.mode)
+
+			outcome, err := processor.ProcessTask(context.Background(), "write a report", 1)
+			if !errors.Is(err, ErrModelResponse) {
+				t.Fatalf("ProcessTask = %+v, %v; want %v", outcome, err, ErrModelResponse)
+			}
+		})
+	}
+}
+
+func TestOpenAITaskProcessorContextTimeout(t *testing.T) {
+	server, _ := newStubModelServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done() // Never answers; released when the client gives up
+	})
+	processor := newTestOpenAIProcessor(t, server, DecisionTool)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := processor.ProcessTask(ctx, "write a report", 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ProcessTask error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("ProcessTask returned after %v, should honour the context deadline", elapsed)
+	}
+}
@@ -0,0 +1,62 @@
+// Package subnet - Miner Output Commitments
+//
+// This file binds a miner output to the request, the input and the miner clock it was
+// produced at, so votes, certificates and published graphs can refer to the exact output
+// that was validated without carrying its text.
+//
+// Commitment:
+//   - keccak256(prefix || "requestID|inputNumber|outputHash|clock"), where outputHash is
+//     OutputHash(output) and clock is "id:value" pairs sorted by node ID (as in attestations)
+//   - The miner sets it on every response before signing; validators recompute it,
+//     reject a response whose commitment does not match, and attest to it in their votes
+//   - Anyone holding the output and the response clock can reproduce it
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// outputCommitmentPrefix domain-separates output commitments from other keccak256 uses
+const outputCommitmentPrefix = "\x19PoCW Output Commitment:\n"
+
+// ErrOutputCommitment is returned when a response's commitment does not match its output
+var ErrOutputCommitment = errors.New("output commitment mismatch")
+
+// OutputCommitmentPreimage returns the string hashed into an output commitment
+func OutputCommitmentPreimage(requestID string, inputNumber int, output string, clock *vlc.Clock) string {
+	return strings.Join([]string{
+		requestID,
+		strconv.Itoa(inputNumber),
+		OutputHash(output),
+		canonicalClock(vlcToMap(clock)),
+	}, "|")
+}
+
+// OutputCommitment returns the 0x-prefixed commitment to an output produced for a request at a clock
+func OutputCommitment(requestID string, inputNumber int, output string, clock *vlc.Clock) string {
+	preimage := OutputCommitmentPreimage(requestID, inputNumber, output, clock)
+	return fmt.Sprintf("0x%x", crypto.Keccak256([]byte(outputCommitmentPrefix+preimage)))
+}
+
+// ResponseCommitment computes the output commitment for a miner response's own fields
+func ResponseCommitment(response *MinerResponseMessage) string {
+	return OutputCommitment(response.RequestID, response.InputNumber, response.Output, response.VLCClock)
+}
+
+// VerifyOutputCommitment checks that a response carries the commitment to its output
+func VerifyOutputCommitment(response *MinerResponseMessage) error {
+	if response.OutputCommitment == "" {
+		return fmt.Errorf("%w: %s carries no output commitment", ErrOutputCommitment, response.RequestID)
+	}
+	if expected := ResponseCommitment(response); !strings.EqualFold(response.OutputCommitment, expected) {
+		return fmt.Errorf("%w: %s commits to %s, its output and clock give %s",
+			ErrOutputCommitment, response.RequestID, response.OutputCommitment, expected)
+	}
+	return nil
+}
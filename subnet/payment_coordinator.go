@@ -12,6 +12,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -41,8 +42,10 @@ type PaymentCoordinator struct {
 	facilitatorURL  string // x402 facilitator service URL
 	paymentMode     string // direct or session (x402 V2)
 
-	// Payment tracking (per-task)
-	payments map[string]*PaymentTracker // taskID -> payment details
+	// Payment tracking (per-task). Miners verify and claim payments concurrently with the
+	// coordinator's rounds, so the map is only touched under paymentsMu.
+	paymentsMu sync.RWMutex
+	payments   map[string]*PaymentTracker // taskID -> payment details
 
 	// Session payment tracking (x402 V2)
 	useSessionPayments bool                       // Enable session-based payments
@@ -52,6 +55,10 @@ type PaymentCoordinator struct {
 	// Direct payment tracking (for tasks outside sessions)
 	directPaymentTotal int64 // Total direct payments in base units (e.g., USDC with 6 decimals)
 	directPaymentCount int   // Number of direct payments made
+
+	// Execution claims: one payment funds at most one execution (see ClaimExecution).
+	// Guarded by paymentsMu.
+	executions map[string]string // taskID -> executor that claimed the payment
 }
 
 // PaymentTracker tracks the lifecycle of a payment
@@ -68,6 +75,7 @@ type PaymentTracker struct {
 	ConsensusReached bool
 	UserAccepted    bool
 	QualityScore    float64
+	ExecutedBy      string // Miner whose execution this payment funded (see ClaimExecution)
 }
 
 // SessionPaymentStatus represents the status of a session payment
@@ -218,6 +226,7 @@ func NewPaymentCoordinator(rpcURL, contractAddressesFile, privateKeyHex string)
 		useSessionPayments:  paymentMode == "session",
 		tasksPerSession:     tasksPerSession,
 		sessions:            make(map[int]*SessionPayment),
+		executions:          make(map[string]string),
 	}
 
 	modeStr := paymentMode
@@ -471,7 +480,7 @@ func (pc *PaymentCoordinator) SettlePaymentWithFacilitator(
 		}
 	}
 
-	pc.payments[taskID] = &PaymentTracker{
+	pc.trackPayment(taskID, &PaymentTracker{
 		TaskID:           taskIDBytes,
 		Client:           clientAddr,
 		Agent:            agentAddr,
@@ -481,7 +490,7 @@ func (pc *PaymentCoordinator) SettlePaymentWithFacilitator(
 		ConsensusReached: false,
 		QualityScore:     0,
 		UserAccepted:     false,
-	}
+	})
 
 	return nil
 }
@@ -560,7 +569,7 @@ func (pc *PaymentCoordinator) DepositPayment(
 	}
 
 	// Track payment
-	pc.payments[taskID] = &PaymentTracker{
+	pc.trackPayment(taskID, &PaymentTracker{
 		TaskID:      taskIDBytes,
 		Client:      clientAddr,
 		Agent:       agentAddr,
@@ -568,7 +577,7 @@ func (pc *PaymentCoordinator) DepositPayment(
 		Status:      PaymentDeposited,
 		DepositTime: time.Now(),
 		Deadline:    time.Unix(deadline.Int64(), 0),
-	}
+	})
 
 	fmt.Printf("💰 Escrow deposit: %s %s (tx: %s)\n", formatEther(amount), pc.paymentTokenName, signedTx.Hash().Hex()[:10]+"...")
 
@@ -658,7 +667,7 @@ func (pc *PaymentCoordinator) DepositPaymentWithAuthorization(
 	}
 
 	// Track payment
-	pc.payments[taskID] = &PaymentTracker{
+	pc.trackPayment(taskID, &PaymentTracker{
 		TaskID:      taskIDBytes,
 		Client:      clientAddr,
 		Agent:       agentAddr,
@@ -666,7 +675,7 @@ func (pc *PaymentCoordinator) DepositPaymentWithAuthorization(
 		Status:      PaymentDeposited,
 		DepositTime: time.Now(),
 		Deadline:    time.Unix(validBefore.Int64(), 0),
-	}
+	})
 
 	fmt.Printf("💰 Payment deposited: %s %s (tx: %s)\n", formatEther(amount), pc.paymentTokenName, signedTx.Hash().Hex()[:10]+"...")
 
@@ -676,7 +685,7 @@ func (pc *PaymentCoordinator) DepositPaymentWithAuthorization(
 // ReleasePaymentDirectDemo transfers AIUSD directly from coordinator to agent for demo purposes
 // Bypasses escrow to show actual balance changes without requiring client signatures
 func (pc *PaymentCoordinator) ReleasePaymentDirectDemo(taskID string) error {
-	payment, exists := pc.payments[taskID]
+	payment, exists := pc.lookupPayment(taskID)
 	if !exists {
 		return fmt.Errorf("payment not found for task %s", taskID)
 	}
@@ -748,7 +757,7 @@ func (pc *PaymentCoordinator) ReleasePaymentDirectDemo(taskID string) error {
 
 // ReleasePayment releases payment to the agent after successful consensus and user acceptance
 func (pc *PaymentCoordinator) ReleasePayment(taskID string) error {
-	payment, exists := pc.payments[taskID]
+	payment, exists := pc.lookupPayment(taskID)
 	if !exists {
 		return fmt.Errorf("payment not found for task %s", taskID)
 	}
@@ -919,7 +928,7 @@ func (pc *PaymentCoordinator) ReleasePayment(taskID string) error {
 // RefundPaymentDirectDemo marks payment as refunded for demo purposes
 // In demo mode, coordinator pays from their own funds, so refund just means "don't transfer"
 func (pc *PaymentCoordinator) RefundPaymentDirectDemo(taskID string) error {
-	payment, exists := pc.payments[taskID]
+	payment, exists := pc.lookupPayment(taskID)
 	if !exists {
 		return fmt.Errorf("payment not found for task %s", taskID)
 	}
@@ -939,7 +948,7 @@ func (pc *PaymentCoordinator) RefundPaymentDirectDemo(taskID string) error {
 
 // RefundPayment refunds payment to the client on failure or rejection
 func (pc *PaymentCoordinator) RefundPayment(taskID string) error {
-	payment, exists := pc.payments[taskID]
+	payment, exists := pc.lookupPayment(taskID)
 	if !exists {
 		return fmt.Errorf("payment not found for task %s", taskID)
 	}
@@ -1091,7 +1100,7 @@ func (pc *PaymentCoordinator) RefundPayment(taskID string) error {
 
 // ReleasePartialPayment releases payment for approved tasks only (partial session payment)
 func (pc *PaymentCoordinator) ReleasePartialPayment(sessionID string, approvedTasks, totalTasks int) error {
-	payment, exists := pc.payments[sessionID]
+	payment, exists := pc.lookupPayment(sessionID)
 	if !exists {
 		// For session payments, try to release via facilitator directly
 		if pc.UseFacilitator() {
@@ -1167,7 +1176,7 @@ func (pc *PaymentCoordinator) ReleasePartialPayment(sessionID string, approvedTa
 
 // UpdatePaymentConsensus updates payment tracker with consensus results
 func (pc *PaymentCoordinator) UpdatePaymentConsensus(taskID string, consensusReached bool, qualityScore float64) {
-	if payment, exists := pc.payments[taskID]; exists {
+	if payment, exists := pc.lookupPayment(taskID); exists {
 		payment.ConsensusReached = consensusReached
 		payment.QualityScore = qualityScore
 	}
@@ -1175,14 +1184,14 @@ func (pc *PaymentCoordinator) UpdatePaymentConsensus(taskID string, consensusRea
 
 // UpdatePaymentUserAcceptance updates payment tracker with user acceptance
 func (pc *PaymentCoordinator) UpdatePaymentUserAcceptance(taskID string, userAccepted bool) {
-	if payment, exists := pc.payments[taskID]; exists {
+	if payment, exists := pc.lookupPayment(taskID); exists {
 		payment.UserAccepted = userAccepted
 	}
 }
 
 // ShouldReleasePayment determines if payment should be released based on consensus and user acceptance
 func (pc *PaymentCoordinator) ShouldReleasePayment(taskID string) bool {
-	payment, exists := pc.payments[taskID]
+	payment, exists := pc.lookupPayment(taskID)
 	if !exists {
 		return false
 	}
@@ -1198,7 +1207,7 @@ func (pc *PaymentCoordinator) InitializePaymentForDemo(taskID string, clientAddr
 	taskIDBytes := [32]byte{}
 	copy(taskIDBytes[:], []byte(taskID))
 
-	pc.payments[taskID] = &PaymentTracker{
+	pc.trackPayment(taskID, &PaymentTracker{
 		TaskID:      taskIDBytes,
 		Client:      clientAddr,
 		Agent:       agentAddr,
@@ -1206,18 +1215,61 @@ func (pc *PaymentCoordinator) InitializePaymentForDemo(taskID string, clientAddr
 		Status:      PaymentDeposited,
 		DepositTime: time.Now(),
 		Deadline:    time.Now().Add(1 * time.Hour),
-	}
+	})
 
 	fmt.Printf("💰 Demo payment: %s %s for task %s\n", formatEther(amount), pc.paymentTokenName, taskID)
 }
 
 func (pc *PaymentCoordinator) GetPaymentStatus(taskID string) *PaymentTracker {
-	if payment, exists := pc.payments[taskID]; exists {
+	if payment, exists := pc.lookupPayment(taskID); exists {
 		return payment
 	}
 	return nil
 }
 
+// lookupPayment returns the tracker of a task's payment
+func (pc *PaymentCoordinator) lookupPayment(taskID string) (*PaymentTracker, bool) {
+	pc.paymentsMu.RLock()
+	defer pc.paymentsMu.RUnlock()
+	payment, exists := pc.payments[taskID]
+	return payment, exists
+}
+
+// trackPayment starts tracking a task's payment
+func (pc *PaymentCoordinator) trackPayment(taskID string, payment *PaymentTracker) {
+	pc.paymentsMu.Lock()
+	defer pc.paymentsMu.Unlock()
+	pc.payments[taskID] = payment
+}
+
+// ClaimExecution records that a task's payment funds one execution by executorID.
+// Miners claim after VerifyPaymentLocked and before doing any work; a second claim for the
+// same task - a retry the miner no longer has cached, or another miner - is refused with
+// ErrPaymentAlreadyClaimed. The claim is also recorded on the task's PaymentTracker.
+//
+// Guarantee: claims live in this coordinator's memory. They are exclusive among the miners
+// that share this coordinator (the single-process demo) until it restarts; they are neither
+// journaled nor shared with miner processes that verify payments through their own
+// coordinator. Each miner's journal keeps that miner from executing a payment twice across
+// restarts (see CoreMiner.claimExecution); the escrow contract, not this claim, is what pays
+// an agent at most once.
+func (pc *PaymentCoordinator) ClaimExecution(taskID, executorID string) error {
+	pc.paymentsMu.Lock()
+	defer pc.paymentsMu.Unlock()
+
+	if pc.executions == nil {
+		pc.executions = make(map[string]string)
+	}
+	if claimedBy, claimed := pc.executions[taskID]; claimed {
+		return fmt.Errorf("%w: task %s already executed by %s", ErrPaymentAlreadyClaimed, taskID, claimedBy)
+	}
+	pc.executions[taskID] = executorID
+	if payment, exists := pc.payments[taskID]; exists {
+		payment.ExecutedBy = executorID
+	}
+	return nil
+}
+
 // GetPaymentTokenName returns the configured payment token name (USDC or AIUSD)
 func (pc *PaymentCoordinator) GetPaymentTokenName() string {
 	return pc.paymentTokenName
@@ -1250,7 +1302,7 @@ func (pc *PaymentCoordinator) VerifyPaymentLocked(taskID string, agentAddr commo
 	}
 
 	// First check local payment tracker (for direct payments or facilitator payments)
-	if trackedPayment, exists := pc.payments[taskID]; exists {
+	if trackedPayment, exists := pc.lookupPayment(taskID); exists {
 		// Verify the payment status is valid (deposited for escrow, pending/completed for direct)
 		if trackedPayment.Status != PaymentDeposited &&
 		   trackedPayment.Status != PaymentCompleted &&
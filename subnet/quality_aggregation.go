@@ -0,0 +1,139 @@
+// Package subnet - Quality Score Aggregation
+//
+// This file aggregates the quality scores carried in validator votes into a single
+// robust consensus quality. Accept/reject weights decide whether output is delivered;
+// the aggregate decides how good it was, and is what payments and reputation use.
+package subnet
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultTrimFraction is the share of total weight trimmed from each tail for TrimmedMean
+const DefaultTrimFraction = 0.25
+
+// WeightedScore is one validator's quality score and voting weight
+type WeightedScore struct {
+	Quality float64 `json:"quality"`
+	Weight  float64 `json:"weight"`
+}
+
+// QualityAggregate summarizes the validators' quality scores for one request.
+//
+// Metrics:
+//   - WeightedMedian: robust central score; a minority of outliers cannot move it far
+//   - TrimmedMean: weighted mean after dropping TrimFraction of weight from each tail
+//   - Variance / StdDev: weighted spread around the weighted mean
+//   - Disagreement: weighted mean absolute deviation from the median (0 = unanimous)
+type QualityAggregate struct {
+	Count          int     `json:"count"`
+	TotalWeight    float64 `json:"total_weight"`
+	WeightedMean   float64 `json:"weighted_mean"`
+	WeightedMedian float64 `json:"weighted_median"`
+	TrimmedMean    float64 `json:"trimmed_mean"`
+	Variance       float64 `json:"variance"`
+	StdDev         float64 `json:"std_dev"`
+	Disagreement   float64 `json:"disagreement"`
+	Min            float64 `json:"min"`
+	Max            float64 `json:"max"`
+}
+
+// AggregateQuality computes robust statistics over weighted quality scores.
+// Scores are clamped to [0, 1]; entries with non-positive weight are ignored.
+// trimFraction outside [0, 0.5) falls back to DefaultTrimFraction.
+func AggregateQuality(scores []WeightedScore, trimFraction float64) QualityAggregate {
+	if trimFraction < 0 || trimFraction >= 0.5 {
+		trimFraction = DefaultTrimFraction
+	}
+
+	sorted := make([]WeightedScore, 0, len(scores))
+	for _, s := range scores {
+		if s.Weight <= 0 {
+			continue
+		}
+		sorted = append(sorted, WeightedScore{Quality: clampQuality(s.Quality), Weight: s.Weight})
+	}
+	if len(sorted) == 0 {
+		return QualityAggregate{}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Quality < sorted[j].Quality })
+
+	agg := QualityAggregate{
+		Count: len(sorted),
+		Min:   sorted[0].Quality,
+		Max:   sorted[len(sorted)-1].Quality,
+	}
+
+	var weightedSum float64
+	for _, s := range sorted {
+		agg.TotalWeight += s.Weight
+		weightedSum += s.Quality * s.Weight
+	}
+	agg.WeightedMean = weightedSum / agg.TotalWeight
+	agg.WeightedMedian = weightedMedian(sorted, agg.TotalWeight)
+	agg.TrimmedMean = trimmedMean(sorted, agg.TotalWeight, trimFraction)
+
+	var squaredDev, absDev float64
+	for _, s := range sorted {
+		squaredDev += s.Weight * (s.Quality - agg.WeightedMean) * (s.Quality - agg.WeightedMean)
+		absDev += s.Weight * math.Abs(s.Quality-agg.WeightedMedian)
+	}
+	agg.Variance = squaredDev / agg.TotalWeight
+	agg.StdDev = math.Sqrt(agg.Variance)
+	agg.Disagreement = absDev / agg.TotalWeight
+
+	return agg
+}
+
+// weightedMedian returns the score at half the cumulative weight of sorted scores.
+// When the half-way point falls exactly between two scores, their midpoint is returned.
+func weightedMedian(sorted []WeightedScore, totalWeight float64) float64 {
+	half := totalWeight / 2
+	var cumulative float64
+	for i, s := range sorted {
+		cumulative += s.Weight
+		if math.Abs(cumulative-half) <= quorumEpsilon && i+1 < len(sorted) {
+			return (s.Quality + sorted[i+1].Quality) / 2
+		}
+		if cumulative > half {
+			return s.Quality
+		}
+	}
+	return sorted[len(sorted)-1].Quality
+}
+
+// trimmedMean returns the weighted mean of the weight band [trim×W, (1-trim)×W].
+// Scores straddling a cut point contribute only their weight inside the band.
+func trimmedMean(sorted []WeightedScore, totalWeight, trimFraction float64) float64 {
+	low := trimFraction * totalWeight
+	high := totalWeight - low
+
+	var cumulative, keptWeight, keptSum float64
+	for _, s := range sorted {
+		start, end := cumulative, cumulative+s.Weight
+		cumulative = end
+
+		kept := math.Min(end, high) - math.Max(start, low)
+		if kept <= 0 {
+			continue
+		}
+		keptWeight += kept
+		keptSum += kept * s.Quality
+	}
+	if keptWeight <= 0 {
+		return weightedMedian(sorted, totalWeight)
+	}
+	return keptSum / keptWeight
+}
+
+// clampQuality limits a quality score to the valid [0, 1] range
+func clampQuality(quality float64) float64 {
+	if math.IsNaN(quality) || quality < 0 {
+		return 0
+	}
+	if quality > 1 {
+		return 1
+	}
+	return quality
+}
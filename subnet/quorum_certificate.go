@@ -0,0 +1,437 @@
+// Package subnet - Quorum Certificates
+//
+// This file bundles validator vote attestations into compact certificates that prove
+// the registered validators agreed on a task outcome, verifiable by anyone holding the
+// validator address set from SubnetRegistry.getSubnet.
+//
+// Attestation:
+//   - Each non-abstaining validator signs keccak256(prefix || preimage) where preimage is
+//     "subnetID|requestID|outputCommitment|accept|quality|clock" (see AttestationPreimage
+//     and output_commitment.go)
+//   - quality is fixed to 6 decimals and clock is "id:value" pairs sorted by node ID, so
+//     the digest is reproducible outside Go (e.g., the JavaScript bridge)
+//
+// Weights:
+//   - Consensus weighs votes by accountability-adjusted weight, so the certificate carries
+//     the weight of every validator as used to decide the round, keyed by signer address
+//   - The round leader signs keccak256(prefix || "subnetID|requestID|outputCommitment|
+//     outcome|clock|address:weight,...") with addresses lower-case and sorted, weights
+//     fixed to 6 decimals (see WeightsPreimage)
+//   - Penalties can only lower a weight: no validator may weigh more than an equal share
+//     of the registered set, so a leader cannot inflate its own or a colluder's vote
+//   - The table names every registered validator, and quorum is measured against the
+//     whole registered set (n equal shares), so leaving validators out or lowering their
+//     weight never makes a quorum easier to reach
+//
+// Certificates:
+//   - TaskCertificate: attestations for one decided task (accepted or rejected)
+//   - EpochCertificate: the task certificates of an epoch plus a root hash over them
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// attestationSigningPrefix domain-separates vote attestations from subnet messages
+const attestationSigningPrefix = "\x19PoCW Vote Attestation:\n"
+
+// weightsSigningPrefix domain-separates a leader's signed weight table from attestations
+const weightsSigningPrefix = "\x19PoCW Quorum Weights:\n"
+
+// weightPrecision is the resolution certificate weights are rounded to (6 decimals);
+// verification allows this much rounding error per weight
+const weightPrecision = 1e-6
+
+var (
+	// ErrInvalidCertificate is returned when a certificate does not prove its outcome
+	ErrInvalidCertificate = errors.New("invalid quorum certificate")
+	// ErrUndecidedTask is returned when certifying a task that reached no quorum
+	ErrUndecidedTask = errors.New("task has no consensus outcome to certify")
+)
+
+// OutputHash returns the 0x-prefixed Keccak-256 hash of a miner output
+func OutputHash(output string) string {
+	return fmt.Sprintf("0x%x", crypto.Keccak256([]byte(output)))
+}
+
+// canonicalClock encodes a clock as "id:value" pairs sorted by node ID
+func canonicalClock(clock map[int]int) string {
+	ids := make([]int, 0, len(clock))
+	for id := range clock {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	pairs := make([]string, len(ids))
+	for i, id := range ids {
+		pairs[i] = fmt.Sprintf("%d:%d", id, clock[id])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// AttestationPreimage returns the string a validator signs to attest to its vote
+func AttestationPreimage(subnetID, requestID, outputCommitment string, accept bool, quality float64, clock map[int]int) string {
+	return strings.Join([]string{
+		subnetID,
+		requestID,
+		strings.ToLower(outputCommitment),
+		strconv.FormatBool(accept),
+		strconv.FormatFloat(clampQuality(quality), 'f', 6, 64),
+		canonicalClock(clock),
+	}, "|")
+}
+
+// AttestationDigest returns the Keccak-256 digest signed in a vote attestation
+func AttestationDigest(subnetID, requestID, outputCommitment string, accept bool, quality float64, clock map[int]int) []byte {
+	preimage := AttestationPreimage(subnetID, requestID, outputCommitment, accept, quality, clock)
+	return crypto.Keccak256([]byte(attestationSigningPrefix + preimage))
+}
+
+// voteAttestationDigest returns the attestation digest for a vote's own fields
+func voteAttestationDigest(vote *ValidatorVoteMessage) []byte {
+	return AttestationDigest(vote.SubnetID, vote.RequestID, vote.OutputCommitment, vote.Accept, vote.Quality, vlcToMap(vote.AttestedClock))
+}
+
+// attest signs the vote's attestation with the validator's key (abstentions are not attested)
+func (v *CoreValidator) attest(vote *ValidatorVoteMessage) {
+	if v.signer == nil || vote.Abstain {
+		return
+	}
+	signature, err := v.signer.SignDigest(voteAttestationDigest(vote))
+	if err != nil {
+		fmt.Printf("⚠️  Validator %s: Failed to attest vote for %s: %v\n", v.ID, vote.RequestID, err)
+		return
+	}
+	vote.Attestation = fmt.Sprintf("0x%x", signature)
+}
+
+// VoteAttestation is one validator's signed statement about a task outcome
+type VoteAttestation struct {
+	ValidatorID string  `json:"validatorId"`
+	Signer      string  `json:"signer"` // Address recovered from Signature when the certificate was built
+	Accept      bool    `json:"accept"`
+	Quality     float64 `json:"quality"`
+	Signature   string  `json:"signature"`
+}
+
+// TaskCertificate proves that a quorum of validator weight attested to the same outcome
+// for one miner output at one VLC clock. The output itself is not included; its
+// commitment identifies it.
+type TaskCertificate struct {
+	SubnetID         string             `json:"subnetId"`
+	RequestID        string             `json:"requestId"`
+	OutputCommitment string             `json:"outputCommitment"`
+	VLCClock         map[int]int        `json:"vlcClock"` // Clock of the miner response the validators judged
+	Outcome          ConsensusOutcome   `json:"outcome"`
+	Attestations     []VoteAttestation  `json:"attestations"`
+	Weights          map[string]float64 `json:"weights"`         // Consensus weight by lower-case validator address
+	Leader           string             `json:"leader"`          // Address of the round leader that signed Weights
+	LeaderSignature  string             `json:"leaderSignature"` // Leader's signature over the weights digest
+}
+
+// NewTaskCertificate bundles the attestations of votes on a decided task together with
+// the validator weights consensus used to decide it. The certificate still needs the
+// round leader's signature (see CoreValidator.SignCertificate).
+// Votes that are abstentions, unattested, or about another output or clock are left out.
+func NewTaskCertificate(response *MinerResponseMessage, outcome ConsensusOutcome, votes []*ValidatorVoteMessage, weights map[common.Address]float64) (*TaskCertificate, error) {
+	if outcome != OutcomeAccepted && outcome != OutcomeRejected {
+		return nil, fmt.Errorf("%w: %s is %s", ErrUndecidedTask, response.RequestID, outcome)
+	}
+
+	cert := &TaskCertificate{
+		SubnetID:         response.SubnetID,
+		RequestID:        response.RequestID,
+		OutputCommitment: ResponseCommitment(response),
+		VLCClock:         vlcToMap(response.VLCClock),
+		Outcome:          outcome,
+		Weights:          make(map[string]float64, len(weights)),
+	}
+	for address, weight := range weights {
+		// Rounded so the JSON value and the signed 6-decimal value agree
+		cert.Weights[strings.ToLower(address.Hex())] = math.Round(math.Max(weight, 0)/weightPrecision) * weightPrecision
+	}
+
+	for _, vote := range votes {
+		if vote.Abstain || vote.Attestation == "" || !strings.EqualFold(vote.OutputCommitment, cert.OutputCommitment) {
+			continue
+		}
+		if canonicalClock(vlcToMap(vote.AttestedClock)) != canonicalClock(cert.VLCClock) {
+			continue
+		}
+
+		signer, err := RecoverDigestSigner(cert.digest(vote.Accept, vote.Quality), vote.Attestation)
+		if err != nil {
+			continue
+		}
+		cert.Attestations = append(cert.Attestations, VoteAttestation{
+			ValidatorID: vote.ValidatorID,
+			Signer:      signer.Hex(),
+			Accept:      vote.Accept,
+			Quality:     vote.Quality,
+			Signature:   vote.Attestation,
+		})
+	}
+	return cert, nil
+}
+
+// digest returns the attestation digest for this certificate's task and a given vote
+func (c *TaskCertificate) digest(accept bool, quality float64) []byte {
+	return AttestationDigest(c.SubnetID, c.RequestID, c.OutputCommitment, accept, quality, c.VLCClock)
+}
+
+// WeightsPreimage returns the string a round leader signs to fix a certificate's weights
+func WeightsPreimage(subnetID, requestID, outputCommitment string, outcome ConsensusOutcome, clock map[int]int, weights map[string]float64) string {
+	addresses := make([]string, 0, len(weights))
+	for address := range weights {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	pairs := make([]string, len(addresses))
+	for i, address := range addresses {
+		pairs[i] = strings.ToLower(address) + ":" + strconv.FormatFloat(weights[address], 'f', 6, 64)
+	}
+	return strings.Join([]string{
+		subnetID,
+		requestID,
+		strings.ToLower(outputCommitment),
+		string(outcome),
+		canonicalClock(clock),
+		strings.Join(pairs, ","),
+	}, "|")
+}
+
+// weightsDigest returns the Keccak-256 digest the round leader signs
+func (c *TaskCertificate) weightsDigest() []byte {
+	preimage := WeightsPreimage(c.SubnetID, c.RequestID, c.OutputCommitment, c.Outcome, c.VLCClock, c.Weights)
+	return crypto.Keccak256([]byte(weightsSigningPrefix + preimage))
+}
+
+// SignCertificate signs the certificate's weights as the round leader
+func (v *CoreValidator) SignCertificate(cert *TaskCertificate) error {
+	if v.signer == nil {
+		return fmt.Errorf("%w: validator %s has no signing key", ErrInvalidCertificate, v.ID)
+	}
+	signature, err := v.signer.SignDigest(cert.weightsDigest())
+	if err != nil {
+		return err
+	}
+	cert.Leader = strings.ToLower(v.signer.Address().Hex())
+	cert.LeaderSignature = fmt.Sprintf("0x%x", signature)
+	return nil
+}
+
+// Hash returns the 0x-prefixed Keccak-256 hash of the certificate's JSON encoding
+func (c *TaskCertificate) Hash() string {
+	encoded, _ := json.Marshal(c) // Plain struct of strings, numbers and maps; cannot fail
+	return fmt.Sprintf("0x%x", crypto.Keccak256(encoded))
+}
+
+// Verify checks every attestation against the registered validator set and that
+// the weight of the signers agreeing with Outcome reaches quorum × the registered weight,
+// the n equal shares of the whole set.
+//
+// Weights must be signed by a registered validator (the round leader) and name exactly
+// the registered validators, each weighing at most an equal share of the set.
+// A quorum outside [MinQuorum, 1] uses DefaultQuorum. Zero addresses are ignored.
+func (c *TaskCertificate) Verify(validators []common.Address, quorum float64) error {
+	if !validQuorum(quorum) {
+		quorum = DefaultQuorum
+	}
+
+	registered := make(map[common.Address]bool)
+	for _, address := range validators {
+		if address != (common.Address{}) {
+			registered[address] = true
+		}
+	}
+	if len(registered) == 0 {
+		return fmt.Errorf("%w: empty validator set", ErrInvalidCertificate)
+	}
+
+	weights, err := c.verifyWeights(registered)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[common.Address]bool)
+	var agreeing float64
+	for _, attestation := range c.Attestations {
+		signer, err := RecoverDigestSigner(c.digest(attestation.Accept, attestation.Quality), attestation.Signature)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidCertificate, attestation.ValidatorID, err)
+		}
+		if !registered[signer] {
+			return fmt.Errorf("%w: %s signed by unregistered %s", ErrInvalidCertificate, attestation.ValidatorID, signer.Hex())
+		}
+		if seen[signer] {
+			return fmt.Errorf("%w: duplicate attestation from %s", ErrInvalidCertificate, signer.Hex())
+		}
+		seen[signer] = true
+
+		if attestation.Accept == (c.Outcome == OutcomeAccepted) {
+			agreeing += weights[signer]
+		}
+	}
+
+	// Each registered validator weighs at most 1/n, so the set weighs 1
+	threshold := quorum - weightPrecision*float64(len(registered)) - quorumEpsilon
+	if agreeing < threshold {
+		return fmt.Errorf("%w: %s has %.6f of the registered weight for %s, quorum %.2f",
+			ErrInvalidCertificate, c.RequestID, agreeing, c.Outcome, quorum)
+	}
+	return nil
+}
+
+// verifyWeights checks the leader's signature over Weights and that they name every
+// registered validator, and returns them by address
+func (c *TaskCertificate) verifyWeights(registered map[common.Address]bool) (map[common.Address]float64, error) {
+	leader, err := RecoverDigestSigner(c.weightsDigest(), c.LeaderSignature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s weights: %v", ErrInvalidCertificate, c.RequestID, err)
+	}
+	if !registered[leader] || !strings.EqualFold(leader.Hex(), c.Leader) {
+		return nil, fmt.Errorf("%w: %s weights signed by %s, not a registered leader", ErrInvalidCertificate, c.RequestID, leader.Hex())
+	}
+
+	share := 1/float64(len(registered)) + weightPrecision
+	weights := make(map[common.Address]float64, len(c.Weights))
+	for key, weight := range c.Weights {
+		if !common.IsHexAddress(key) {
+			return nil, fmt.Errorf("%w: %s weight for malformed address %q", ErrInvalidCertificate, c.RequestID, key)
+		}
+		address := common.HexToAddress(key)
+		if !registered[address] {
+			return nil, fmt.Errorf("%w: %s weight for unregistered %s", ErrInvalidCertificate, c.RequestID, address.Hex())
+		}
+		if _, duplicate := weights[address]; duplicate {
+			return nil, fmt.Errorf("%w: %s duplicate weight for %s", ErrInvalidCertificate, c.RequestID, address.Hex())
+		}
+		if weight < 0 || weight > share {
+			return nil, fmt.Errorf("%w: %s weight %.6f for %s outside [0, %.6f]",
+				ErrInvalidCertificate, c.RequestID, weight, address.Hex(), share)
+		}
+		weights[address] = weight
+	}
+	for address := range registered {
+		if _, ok := weights[address]; !ok {
+			return nil, fmt.Errorf("%w: %s has no weight for registered %s", ErrInvalidCertificate, c.RequestID, address.Hex())
+		}
+	}
+	return weights, nil
+}
+
+// EpochCertificate bundles the task certificates of one epoch.
+// Root commits to the task certificates in order so the epoch can be referenced by one hash.
+type EpochCertificate struct {
+	SubnetID    string            `json:"subnetId"`
+	EpochNumber int               `json:"epochNumber"`
+	Root        string            `json:"root"`
+	Tasks       []TaskCertificate `json:"tasks"`
+}
+
+// NewEpochCertificate creates an epoch certificate over the given task certificates
+func NewEpochCertificate(subnetID string, epochNumber int, tasks []TaskCertificate) *EpochCertificate {
+	return &EpochCertificate{
+		SubnetID:    subnetID,
+		EpochNumber: epochNumber,
+		Root:        epochCertificateRoot(tasks),
+		Tasks:       tasks,
+	}
+}
+
+// epochCertificateRoot hashes the concatenated task certificate hashes
+func epochCertificateRoot(tasks []TaskCertificate) string {
+	hashes := make([][]byte, len(tasks))
+	for i := range tasks {
+		hashes[i] = common.FromHex(tasks[i].Hash())
+	}
+	return fmt.Sprintf("0x%x", crypto.Keccak256(hashes...))
+}
+
+// Verify checks the root and every task certificate against the validator set
+func (e *EpochCertificate) Verify(validators []common.Address, quorum float64) error {
+	if root := epochCertificateRoot(e.Tasks); !strings.EqualFold(root, e.Root) {
+		return fmt.Errorf("%w: epoch %d root %s, expected %s", ErrInvalidCertificate, e.EpochNumber, e.Root, root)
+	}
+	for i := range e.Tasks {
+		if e.Tasks[i].SubnetID != e.SubnetID {
+			return fmt.Errorf("%w: task %s belongs to subnet %s", ErrInvalidCertificate, e.Tasks[i].RequestID, e.Tasks[i].SubnetID)
+		}
+		if err := e.Tasks[i].Verify(validators, quorum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchSubnetValidators reads a subnet's validator addresses from SubnetRegistry.getSubnet
+func FetchSubnetValidators(rpcURL string, registryAddress common.Address, subnetID string) ([]common.Address, error) {
+	getSubnetABI := `[{
+		"inputs": [{"internalType": "string", "name": "subnetId", "type": "string"}],
+		"name": "getSubnet",
+		"outputs": [
+			{"internalType": "address", "name": "miner", "type": "address"},
+			{"internalType": "uint256", "name": "minerAgentId", "type": "uint256"},
+			{"internalType": "address[4]", "name": "validators", "type": "address[4]"},
+			{"internalType": "bool", "name": "isActive", "type": "bool"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(getSubnetABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+
+	data, err := parsedABI.Pack("getSubnet", subnetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack function call: %w", err)
+	}
+
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum node: %w", err)
+	}
+	defer client.Close()
+
+	result, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &registryAddress, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call contract: %w", err)
+	}
+
+	outputs, err := parsedABI.Unpack("getSubnet", result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack result: %w", err)
+	}
+	if active, _ := outputs[3].(bool); !active {
+		return nil, fmt.Errorf("subnet %s is not active in registry %s", subnetID, registryAddress.Hex())
+	}
+
+	registered, ok := outputs[2].([4]common.Address)
+	if !ok {
+		return nil, fmt.Errorf("unexpected validators type %T", outputs[2])
+	}
+
+	validators := make([]common.Address, 0, len(registered))
+	for _, address := range registered {
+		if address != (common.Address{}) {
+			validators = append(validators, address)
+		}
+	}
+	return validators, nil
+}
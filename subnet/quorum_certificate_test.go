@@ -0,0 +1,145 @@
+package subnet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// certificateFixture is a miner response judged by four registered validators;
+// validators[0] leads the round
+type certificateFixture struct {
+	response   *MinerResponseMessage
+	validators []*CoreValidator
+	addresses  []common.Address
+}
+
+func newCertificateFixture(t *testing.T) *certificateFixture {
+	t.Helper()
+	f := &certificateFixture{response: NewCoreMiner("miner-1", "subnet-test").ProcessInput("task", 1, "req-1")}
+	for _, validatorID := range testValidatorIDs {
+		signer, err := GenerateMessageSigner()
+		if err != nil {
+			t.Fatal(err)
+		}
+		validator := NewCoreValidator(validatorID, "subnet-test", ConsensusValidator, 0.25)
+		validator.SetMessageSigner(signer)
+		f.validators = append(f.validators, validator)
+		f.addresses = append(f.addresses, signer.Address())
+	}
+	return f
+}
+
+// votes returns an attested vote from each of the first n validators
+func (f *certificateFixture) votes(n int, accept bool) []*ValidatorVoteMessage {
+	votes := make([]*ValidatorVoteMessage, n)
+	for i := range votes {
+		votes[i] = &ValidatorVoteMessage{
+			SubnetMessage:    SubnetMessage{SubnetID: f.response.SubnetID, RequestID: f.response.RequestID},
+			ValidatorID:      f.validators[i].ID,
+			Quality:          0.9,
+			Accept:           accept,
+			OutputCommitment: ResponseCommitment(f.response),
+			AttestedClock:    f.response.VLCClock,
+		}
+		f.validators[i].attest(votes[i])
+	}
+	return votes
+}
+
+// certificate builds a certificate over votes with the given weights, signed by the leader
+func (f *certificateFixture) certificate(t *testing.T, votes []*ValidatorVoteMessage, weights map[common.Address]float64) *TaskCertificate {
+	t.Helper()
+	cert, err := NewTaskCertificate(f.response, OutcomeAccepted, votes, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.validators[0].SignCertificate(cert); err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// equalWeights gives every registered validator an equal share
+func (f *certificateFixture) equalWeights() map[common.Address]float64 {
+	weights := make(map[common.Address]float64, len(f.addresses))
+	for _, address := range f.addresses {
+		weights[address] = 0.25
+	}
+	return weights
+}
+
+func TestTaskCertificateVerifiesQuorum(t *testing.T) {
+	f := newCertificateFixture(t)
+	cert := f.certificate(t, f.votes(3, true), f.equalWeights())
+	if err := cert.Verify(f.addresses, DefaultQuorum); err != nil {
+		t.Fatalf("3/4 accept certificate rejected: %v", err)
+	}
+
+	below := f.certificate(t, f.votes(2, true), f.equalWeights())
+	if err := below.Verify(f.addresses, DefaultQuorum); !errors.Is(err, ErrInvalidCertificate) {
+		t.Fatalf("2/4 accept certificate = %v, want %v", err, ErrInvalidCertificate)
+	}
+}
+
+func TestTaskCertificateRejectsLoneLeader(t *testing.T) {
+	f := newCertificateFixture(t)
+	leader := f.addresses[0]
+
+	alone := f.certificate(t, f.votes(1, true), map[common.Address]float64{leader: 0.25})
+	if err := alone.Verify(f.addresses, DefaultQuorum); !errors.Is(err, ErrInvalidCertificate) {
+		t.Fatalf("certificate weighing only the leader = %v, want %v", err, ErrInvalidCertificate)
+	}
+
+	// Zeroing the other validators instead of leaving them out does not help either
+	zeroed := f.equalWeights()
+	for _, address := range f.addresses[1:] {
+		zeroed[address] = 0
+	}
+	cert := f.certificate(t, f.votes(1, true), zeroed)
+	if err := cert.Verify(f.addresses, DefaultQuorum); !errors.Is(err, ErrInvalidCertificate) {
+		t.Fatalf("certificate zeroing the other validators = %v, want %v", err, ErrInvalidCertificate)
+	}
+}
+
+func TestTaskCertificateRejectsOmittedValidator(t *testing.T) {
+	f := newCertificateFixture(t)
+	weights := f.equalWeights()
+	delete(weights, f.addresses[3])
+
+	cert := f.certificate(t, f.votes(3, true), weights)
+	if err := cert.Verify(f.addresses, DefaultQuorum); err == nil || !strings.Contains(err.Error(), "no weight for registered") {
+		t.Fatalf("certificate omitting validator-4 = %v, want a missing weight error", err)
+	}
+}
+
+func TestTaskCertificateRejectsForgedWeight(t *testing.T) {
+	f := newCertificateFixture(t)
+
+	// Raised after the leader signed
+	tampered := f.certificate(t, f.votes(3, true), f.equalWeights())
+	for key := range tampered.Weights {
+		tampered.Weights[key] = 0.3
+	}
+	if err := tampered.Verify(f.addresses, DefaultQuorum); !errors.Is(err, ErrInvalidCertificate) {
+		t.Fatalf("certificate with weights altered after signing = %v, want %v", err, ErrInvalidCertificate)
+	}
+
+	// Signed by the leader, but above an equal share
+	inflated := f.equalWeights()
+	inflated[f.addresses[0]] = 0.5
+	cert := f.certificate(t, f.votes(2, true), inflated)
+	if err := cert.Verify(f.addresses, DefaultQuorum); !errors.Is(err, ErrInvalidCertificate) {
+		t.Fatalf("certificate inflating the leader's weight = %v, want %v", err, ErrInvalidCertificate)
+	}
+
+	// Lowered weights count for less, never for more
+	penalized := f.equalWeights()
+	penalized[f.addresses[2]] = 0.1
+	lowered := f.certificate(t, f.votes(3, true), penalized)
+	if err := lowered.Verify(f.addresses, DefaultQuorum); !errors.Is(err, ErrInvalidCertificate) {
+		t.Fatalf("certificate with 0.6 agreeing weight = %v, want %v", err, ErrInvalidCertificate)
+	}
+}
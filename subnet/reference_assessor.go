@@ -0,0 +1,196 @@
+// Package subnet - Reference-Answer Quality Assessment
+//
+// This file implements a QualityAssessor that compares a miner output with a reference
+// answer for the same task (JSON file). Like the rubric, scoring is deterministic and
+// needs no model.
+//
+// Scoring:
+//   - The reference is looked up by the task's original input (MinerResponseMessage.Task
+//     without clarifications), ignoring case and surrounding whitespace
+//   - Quality = token F1 between output and reference (lower-cased words)
+//   - Accept = quality >= the answer's min_similarity (default 0.5)
+//   - Tasks without a reference are abstained on
+package subnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// DefaultReferenceMinSimilarity is the similarity a reference requires to accept when it sets none
+const DefaultReferenceMinSimilarity = 0.5
+
+// ErrInvalidReferences is returned when a reference set cannot be used for assessment
+var ErrInvalidReferences = errors.New("invalid reference set")
+
+// ReferenceSet is a per-subnet collection of reference answers
+type ReferenceSet struct {
+	Name          string            `json:"name"`
+	Version       string            `json:"version,omitempty"`
+	SubnetID      string            `json:"subnet_id,omitempty"`      // Subnet the references are written for ("" = any)
+	MinSimilarity float64           `json:"min_similarity,omitempty"` // Default similarity required to accept
+	Answers       []ReferenceAnswer `json:"answers"`
+}
+
+// ReferenceAnswer is the expected answer to one task
+type ReferenceAnswer struct {
+	Task          string  `json:"task"`
+	Reference     string  `json:"reference"`
+	MinSimilarity float64 `json:"min_similarity,omitempty"` // Overrides the set's default
+}
+
+// ReferenceAssessor scores miner outputs by their similarity to reference answers.
+// Safe for concurrent use.
+type ReferenceAssessor struct {
+	set    ReferenceSet
+	byTask map[string]ReferenceAnswer
+}
+
+// NewReferenceAssessor validates a reference set and indexes it by task
+func NewReferenceAssessor(set ReferenceSet) (*ReferenceAssessor, error) {
+	if set.Name == "" {
+		return nil, fmt.Errorf("%w: no name", ErrInvalidReferences)
+	}
+	if len(set.Answers) == 0 {
+		return nil, fmt.Errorf("%w: %s has no answers", ErrInvalidReferences, set.Name)
+	}
+	if set.MinSimilarity == 0 {
+		set.MinSimilarity = DefaultReferenceMinSimilarity
+	}
+	if set.MinSimilarity < 0 || set.MinSimilarity > 1 {
+		return nil, fmt.Errorf("%w: %s min_similarity %v outside [0, 1]", ErrInvalidReferences, set.Name, set.MinSimilarity)
+	}
+
+	assessor := &ReferenceAssessor{set: set, byTask: make(map[string]ReferenceAnswer, len(set.Answers))}
+	for i, answer := range set.Answers {
+		key := referenceKey(answer.Task)
+		if key == "" || strings.TrimSpace(answer.Reference) == "" {
+			return nil, fmt.Errorf("%w: answer %d needs a task and a reference", ErrInvalidReferences, i+1)
+		}
+		if _, exists := assessor.byTask[key]; exists {
+			return nil, fmt.Errorf("%w: task %q listed twice", ErrInvalidReferences, answer.Task)
+		}
+		if answer.MinSimilarity == 0 {
+			answer.MinSimilarity = set.MinSimilarity
+		}
+		if answer.MinSimilarity < 0 || answer.MinSimilarity > 1 {
+			return nil, fmt.Errorf("%w: task %q min_similarity %v outside [0, 1]", ErrInvalidReferences, answer.Task, answer.MinSimilarity)
+		}
+		assessor.byTask[key] = answer
+	}
+	return assessor, nil
+}
+
+// LoadReferenceAssessor reads a reference file and indexes it. Unknown fields are rejected.
+func LoadReferenceAssessor(path string) (*ReferenceAssessor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read references: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var set ReferenceSet
+	if err := decoder.Decode(&set); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidReferences, path, err)
+	}
+	return NewReferenceAssessor(set)
+}
+
+// References returns the reference set the assessor compares against
+func (r *ReferenceAssessor) References() ReferenceSet {
+	return r.set
+}
+
+// Name returns the reference set's name and version, as reported in quality reports
+func (r *ReferenceAssessor) Name() string {
+	if r.set.Version == "" {
+		return "reference:" + r.set.Name
+	}
+	return fmt.Sprintf("reference:%s@%s", r.set.Name, r.set.Version)
+}
+
+// AssessQuality scores a response against its reference. It cannot abstain: a task without
+// a reference is reported as a rejection with quality 0. CoreValidator uses AssessDetailed,
+// which abstains instead.
+func (r *ReferenceAssessor) AssessQuality(response *MinerResponseMessage) (float64, bool) {
+	report := r.AssessDetailed(response)
+	if report.AbstainReason != "" {
+		return 0, false
+	}
+	return report.Quality, report.Accept
+}
+
+// AssessOrAbstain scores a response against its reference, or abstains if the task has none
+func (r *ReferenceAssessor) AssessOrAbstain(response *MinerResponseMessage) (float64, bool, string) {
+	report := r.AssessDetailed(response)
+	return report.Quality, report.Accept, report.AbstainReason
+}
+
+// AssessDetailed scores a response against its reference and reports the similarity
+func (r *ReferenceAssessor) AssessDetailed(response *MinerResponseMessage) *QualityReport {
+	report := &QualityReport{Assessor: r.Name()}
+	answer, ok := r.byTask[referenceKey(TaskInput(response.Task))]
+	if !ok {
+		report.AbstainReason = "no reference answer for this task"
+		fmt.Printf("📚 %s: abstaining on %s - %s\n", r.Name(), response.RequestID, report.AbstainReason)
+		return report
+	}
+
+	similarity := tokenF1(response.Output, answer.Reference)
+	report.Quality = clampQuality(similarity)
+	report.Accept = similarity >= answer.MinSimilarity
+	report.Criteria = []CriterionScore{{
+		Name:   "similarity",
+		Weight: 1,
+		Score:  report.Quality,
+		Passed: report.Accept,
+		Detail: fmt.Sprintf("token F1 %.2f against reference (min %.2f)", similarity, answer.MinSimilarity),
+	}}
+	fmt.Printf("📚 %s: %s similarity %.2f (accept=%v)\n", r.Name(), response.RequestID, similarity, report.Accept)
+	return report
+}
+
+// referenceKey normalizes a task for lookup
+func referenceKey(task string) string {
+	return strings.ToLower(strings.TrimSpace(task))
+}
+
+// tokenF1 returns the F1 score of the lower-cased word overlap between output and reference
+func tokenF1(output, reference string) float64 {
+	outputTokens, referenceTokens := similarityTokens(output), similarityTokens(reference)
+	if len(outputTokens) == 0 || len(referenceTokens) == 0 {
+		return 0
+	}
+
+	remaining := make(map[string]int, len(referenceTokens))
+	for _, token := range referenceTokens {
+		remaining[token]++
+	}
+	overlap := 0
+	for _, token := range outputTokens {
+		if remaining[token] > 0 {
+			remaining[token]--
+			overlap++
+		}
+	}
+	if overlap == 0 {
+		return 0
+	}
+
+	precision := float64(overlap) / float64(len(outputTokens))
+	recall := float64(overlap) / float64(len(referenceTokens))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// similarityTokens splits text into lower-cased words
+func similarityTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
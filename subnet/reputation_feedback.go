@@ -31,6 +31,7 @@ type TaskResult struct {
 	Success      bool      // Whether task was successful
 	QualityScore float64   // Quality score from validator consensus (0.0-1.0)
 	Timestamp    time.Time // When task completed
+	AgentID      *big.Int  // Agent that served the task (nil: the manager's AgentID)
 }
 
 // ReputationFeedbackManager manages task results and feedback submission
@@ -171,6 +172,19 @@ func (rfm *ReputationFeedbackManager) RecordTaskResult(
 	fmt.Printf("📝 Task %d recorded: %s (Quality: %.2f)\n", taskNumber, status, qualityScore)
 }
 
+// RecordMinerTaskResult records a task result credited to the agent of the miner that
+// served it (subnets with several miners)
+func (rfm *ReputationFeedbackManager) RecordMinerTaskResult(
+	taskID string,
+	taskNumber int,
+	agentID *big.Int,
+	success bool,
+	qualityScore float64,
+) {
+	rfm.RecordTaskResult(taskID, taskNumber, success, qualityScore)
+	rfm.TaskResults[len(rfm.TaskResults)-1].AgentID = agentID
+}
+
 // GenerateFeedbackAuth - DEPRECATED, kept for backward compatibility
 // Now just calls RecordTaskResult
 func (rfm *ReputationFeedbackManager) GenerateFeedbackAuth(
@@ -318,7 +332,8 @@ func NewReputationBatchSubmitter(
 	}, nil
 }
 
-// SubmitAllFeedback submits feedback for all tasks at once (new v1.0 flow)
+// SubmitAllFeedback submits feedback for all tasks at once (new v1.0 flow).
+// Tasks carrying an AgentID are credited to that agent instead of agentID.
 func (rbs *ReputationBatchSubmitter) SubmitAllFeedback(
 	agentID *big.Int,
 	tasks []TaskResult,
@@ -338,8 +353,14 @@ func (rbs *ReputationBatchSubmitter) SubmitAllFeedback(
 		// Use intent causal graph SVG as feedbackURI
 		feedbackURI := "https://coffee-defiant-raccoon-829.mypinata.cloud/ipfs/bafkreid4ud4ihbwgsxtnc7hkivef6whnqbrzzpncul3r3wxsvi4onjyl64"
 
+		// Feedback goes to the agent that served the task
+		taskAgentID := agentID
+		if task.AgentID != nil {
+			taskAgentID = task.AgentID
+		}
+
 		txHash, err := rbs.submitSingleFeedback(
-			agentID,
+			taskAgentID,
 			score,
 			tag1,
 			tag2,
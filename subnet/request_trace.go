@@ -0,0 +1,218 @@
+// Package subnet - Per-Request Causal Tracking
+//
+// This file records the VLC events of each request the miner serves so that requests
+// processed concurrently remain individually auditable.
+//
+// Event model for one miner step (ProcessInput or ProcessAdditionalInfo):
+//   - received: the request is admitted; the miner's clock is snapshotted, no increment.
+//     This snapshot is the response's RequestClock - every event the request depends on.
+//   - entered: +1 for the message entering the miner, applied together with received
+//   - left: +1 for the response leaving the miner, applied once the TaskProcessor returns
+//
+// Task processing runs between entered and left without holding the miner lock, so
+// concurrent requests overlap there. Admission reserves the step's left event on the miner
+// entry, and the next request is received after that reservation: steps take consecutive
+// pairs of miner entries in admission order and every response carries exactly
+// RequestClock+2, whichever step finishes first. Validators therefore see overlapping
+// requests as ordinary +2 steps; a response that arrives before its predecessor waits in
+// the causal buffer (see vlc/causal.go). The response is signed before it is published.
+package subnet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// RequestEventKind identifies a step in a request's lifecycle inside the miner
+type RequestEventKind string
+
+const (
+	RequestReceived RequestEventKind = "received" // Admitted for processing (clock snapshot, no increment)
+	RequestEntered  RequestEventKind = "entered"  // +1: message entering from validator
+	RequestLeft     RequestEventKind = "left"     // +1: response leaving to validator
+)
+
+// RequestEvent is one VLC event of a request
+type RequestEvent struct {
+	Kind        RequestEventKind `json:"kind"`
+	InputNumber int              `json:"input_number"`
+	Clock       *vlc.Clock       `json:"clock"` // Miner clock right after the event
+	At          time.Time        `json:"at"`
+}
+
+// RequestTrace is the ordered event history of one request, across the initial task
+// and any clarification steps
+type RequestTrace struct {
+	RequestID string         `json:"request_id"`
+	ClockID   uint64         `json:"clock_id"` // Miner's VLC node ID
+	Events    []RequestEvent `json:"events"`
+}
+
+// Verify checks that the trace is causally consistent: each step is received, entered,
+// left in that order, every event's clock follows the previous one, entered directly
+// follows received and left directly follows entered on the miner entry
+func (t *RequestTrace) Verify() error {
+	clockID := t.ClockID
+	if clockID == 0 {
+		clockID = MinerClockID
+	}
+	for i, event := range t.Events {
+		expected := []RequestEventKind{RequestReceived, RequestEntered, RequestLeft}[i%3]
+		if event.Kind != expected {
+			return fmt.Errorf("request %s: event %d is %s, expected %s", t.RequestID, i, event.Kind, expected)
+		}
+		if i == 0 {
+			continue
+		}
+		if order := t.Events[i-1].Clock.Compare(event.Clock); order != vlc.Less && order != vlc.Equal {
+			return fmt.Errorf("request %s: %s clock %v does not follow %s clock %v",
+				t.RequestID, event.Kind, event.Clock.Values, t.Events[i-1].Kind, t.Events[i-1].Clock.Values)
+		}
+		// Other requests may enter or leave while this one is processed, but never on the
+		// miner entries this step reserved at admission
+		previous := t.Events[i-1].Clock.Values[clockID]
+		switch {
+		case event.Kind == RequestEntered && event.Clock.Values[clockID] != previous+1:
+			return fmt.Errorf("request %s: entered must directly follow received on the miner entry", t.RequestID)
+		case event.Kind == RequestLeft && event.Clock.Values[clockID] != previous+1:
+			return fmt.Errorf("request %s: left must directly follow entered on the miner entry", t.RequestID)
+		}
+	}
+	return nil
+}
+
+// VerifyRequestCausality checks the per-request clocks a miner response carries:
+// the response clock must causally follow the clock at which the request was received,
+// with exactly the step's own two miner events on clockID (the sending miner's VLC node
+// ID) in between. Responses without a RequestClock (older miners) are accepted.
+func VerifyRequestCausality(response *MinerResponseMessage, clockID uint64) error {
+	if response.RequestClock == nil {
+		return nil
+	}
+	if response.VLCClock == nil {
+		return fmt.Errorf("request %s carries no response clock", response.RequestID)
+	}
+
+	if order := response.RequestClock.Compare(response.VLCClock); order != vlc.Less {
+		return fmt.Errorf("request %s: response clock %v does not follow request clock %v",
+			response.RequestID, response.VLCClock.Values, response.RequestClock.Values)
+	}
+	if response.VLCClock.Values[clockID] != response.RequestClock.Values[clockID]+2 {
+		return fmt.Errorf("request %s: miner entry %d advanced %d -> %d, expected +2",
+			response.RequestID, clockID, response.RequestClock.Values[clockID], response.VLCClock.Values[clockID])
+	}
+	return nil
+}
+
+// beginRequest admits a request: snapshots the clock as the received event, then applies
+// the +1 of the message entering the miner, records the entered event and reserves the
+// next miner entry for the step's left event. The snapshot is taken past every reservation
+// of requests still in flight and becomes the response's RequestClock. With a journal the admission is journaled first;
+// if that fails the clock is left unchanged and the request must not be processed.
+// A step the journal shows admitted but never completed is resumed instead: its entered
+// increment was already applied before the restart, so it is not applied again.
+func (m *CoreMiner) beginRequest(requestID string, inputNumber int, enterLabel string) (*vlc.Clock, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if received, ok := m.interrupted[requestID]; ok {
+		delete(m.interrupted, requestID)
+		fmt.Printf("Miner %s: Resuming task %s interrupted by a restart → VLC [%d]\n", m.ID, requestID, received.Values[m.ClockID]+1)
+		return received.Copy(), nil
+	}
+
+	received := m.VLCClock.Copy()
+	if received.Values[m.ClockID] < m.reservedEntry {
+		received.Values[m.ClockID] = m.reservedEntry // After the steps still in flight
+	}
+	entered := received.Copy()
+	entered.Inc(m.ClockID)
+	if err := m.journalLocked(JournalReceived, requestID, inputNumber, entered.Copy(), nil); err != nil {
+		fmt.Printf("❌ Miner %s: Task %s not admitted - %v\n", m.ID, requestID, err)
+		return nil, err
+	}
+
+	m.recordEventLocked(requestID, RequestReceived, inputNumber, received)
+	// VLC Protocol: +1 for message entering from validator
+	m.VLCClock = entered
+	m.reservedEntry = entered.Values[m.ClockID] + 1
+	m.recordEventLocked(requestID, RequestEntered, inputNumber, m.VLCClock.Copy())
+	fmt.Printf("Miner %s: %s → VLC [%d]\n", m.ID, enterLabel, m.VLCClock.Values[m.ClockID])
+	return received, nil
+}
+
+// completeRequest applies the step's leaving increment on the miner entry reserved at
+// admission, stamps the response with its clock and output commitment and records the
+// left event. The response is signed before it is
+// stored in the processing history or cached for duplicates, so no reader sees it change.
+// With a journal, the step is journaled first; if that fails the leaving increment is not
+// applied and the response becomes TaskFailed.
+func (m *CoreMiner) completeRequest(response *MinerResponseMessage, leaveLabel string) {
+	m.mu.Lock()
+
+	// Write-ahead: the journaled response carries the clock the increment will produce.
+	// It builds on the request's own clock, so later admissions do not leak into it.
+	left := response.RequestClock.Copy()
+	left.Values[m.ClockID] += 2
+	response.VLCClock = left
+	response.OutputCommitment = ResponseCommitment(response) // Binds the output to this request and clock
+	if err := m.journalLocked(JournalCompleted, response.RequestID, response.InputNumber, left.Copy(), response); err != nil {
+		fmt.Printf("❌ Miner %s: Task %s not committed - %v\n", m.ID, response.RequestID, err)
+		response.OutputType = TaskFailed
+		response.Output, response.InfoRequest = "", ""
+		response.Error = fmt.Sprintf("miner journal write failed: %v", err)
+		response.VLCClock = response.RequestClock.Copy()
+		response.VLCClock.Inc(m.ClockID) // Entered, never left
+		response.OutputCommitment = ResponseCommitment(response)
+		m.mu.Unlock()
+		m.signResponse(response)
+		return
+	}
+
+	// VLC Protocol: +1 for message leaving to validator
+	m.VLCClock.Merge([]*vlc.Clock{left})
+	m.recordEventLocked(response.RequestID, RequestLeft, response.InputNumber, left.Copy())
+	fmt.Printf("Miner %s: %s → VLC [%d]\n", m.ID, leaveLabel, left.Values[m.ClockID])
+	m.mu.Unlock()
+
+	m.signResponse(response)
+
+	// Published once signed; duplicates wait on the step until then (see admitStep)
+	m.mu.Lock()
+	m.processedInputs[response.InputNumber] = response
+	m.cacheResponseLocked(response, time.Now())
+	m.mu.Unlock()
+}
+
+// recordEventLocked appends an event to a request's trace. Caller must hold m.mu.
+func (m *CoreMiner) recordEventLocked(requestID string, kind RequestEventKind, inputNumber int, clock *vlc.Clock) {
+	trace, ok := m.requestTraces[requestID]
+	if !ok {
+		trace = &RequestTrace{RequestID: requestID, ClockID: m.ClockID}
+		m.requestTraces[requestID] = trace
+	}
+	trace.Events = append(trace.Events, RequestEvent{
+		Kind:        kind,
+		InputNumber: inputNumber,
+		Clock:       clock,
+		At:          time.Now(),
+	})
+}
+
+// GetRequestTrace returns a copy of a request's event history, or nil if unknown
+func (m *CoreMiner) GetRequestTrace(requestID string) *RequestTrace {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	trace, ok := m.requestTraces[requestID]
+	if !ok {
+		return nil
+	}
+	return &RequestTrace{
+		RequestID: trace.RequestID,
+		ClockID:   trace.ClockID,
+		Events:    append([]RequestEvent(nil), trace.Events...),
+	}
+}
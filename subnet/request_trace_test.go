@@ -0,0 +1,268 @@
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// sleepyProcessor holds each task briefly so concurrent requests overlap in the processor
+type sleepyProcessor struct{}
+
+func (sleepyProcessor) ProcessTask(ctx context.Context, input string, inputNumber int) (*TaskOutcome, error) {
+	select {
+	case <-time.After(time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &TaskOutcome{OutputType: OutputReady, Output: "done: " + input}, nil
+}
+
+func (sleepyProcessor) ProcessAdditionalInfo(ctx context.Context, originalInput, additionalInfo string, inputNumber int) (*TaskOutcome, error) {
+	return &TaskOutcome{OutputType: OutputReady, Output: originalInput + " " + additionalInfo}, nil
+}
+
+func newTestMiner(t *testing.T) (*CoreMiner, *MessageSigner) {
+	t.Helper()
+	signer, err := GenerateMessageSigner()
+	if err != nil {
+		t.Fatalf("GenerateMessageSigner: %v", err)
+	}
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(sleepyProcessor{})
+	miner.SetMessageSigner(signer)
+	return miner, signer
+}
+
+// TestConcurrentProcessInput runs overlapping requests while another goroutine reads the
+// published responses; run with -race to check a response is never written once published
+func TestConcurrentProcessInput(t *testing.T) {
+	miner, signer := newTestMiner(t)
+	const requests = 32
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, response := range miner.GetProcessedInputs() {
+				_ = response.Signature
+				_ = response.VLCClock.Values[miner.ClockID]
+			}
+		}
+	}()
+
+	responses := make([]*MinerResponseMessage, requests)
+	var workers sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		workers.Add(1)
+		go func(i int) {
+			defer workers.Done()
+			responses[i] = miner.ProcessInput(fmt.Sprintf("task %d", i), i+1, fmt.Sprintf("req-%d", i))
+		}(i)
+	}
+	workers.Wait()
+	close(done)
+	readers.Wait()
+
+	if got, want := miner.GetCurrentClock().Values[miner.ClockID], uint64(2*requests); got != want {
+		t.Fatalf("miner clock = %d, want %d", got, want)
+	}
+	entries := make(map[uint64]bool, requests)
+	for i, response := range responses {
+		entries[response.VLCClock.Values[miner.ClockID]] = true
+		if response.OutputType != OutputReady {
+			t.Fatalf("request %d: output type %v, error %q", i, response.OutputType, response.Error)
+		}
+		if err := VerifyRequestCausality(response, miner.ClockID); err != nil {
+			t.Errorf("request %d: %v", i, err)
+		}
+		if signerAddr, err := RecoverMessageSigner(response); err != nil || signerAddr != signer.Address() {
+			t.Errorf("request %d: signed by %v (%v), want %v", i, signerAddr, err, signer.Address())
+		}
+		trace := miner.GetRequestTrace(response.RequestID)
+		if trace == nil {
+			t.Fatalf("request %d: no trace", i)
+		}
+		if err := trace.Verify(); err != nil {
+			t.Errorf("request %d: %v", i, err)
+		}
+	}
+	for entry := uint64(2); entry <= 2*requests; entry += 2 {
+		if !entries[entry] {
+			t.Errorf("no response left at miner entry %d", entry)
+		}
+	}
+}
+
+// TestRequestEnteredAtAdmission checks the entering increment is applied before the task
+// runs, so a request still in the processor is visible in the miner's clock
+func TestRequestEnteredAtAdmission(t *testing.T) {
+	miner, _ := newTestMiner(t)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	miner.SetContextTaskProcessor(blockingProcessor{started: started, release: release})
+
+	result := make(chan *MinerResponseMessage, 1)
+	go func() { result <- miner.ProcessInput("task", 1, "req-blocked") }()
+	<-started
+
+	if got := miner.GetCurrentClock().Values[miner.ClockID]; got != 1 {
+		t.Fatalf("clock while processing = %d, want 1", got)
+	}
+	trace := miner.GetRequestTrace("req-blocked")
+	if trace == nil || len(trace.Events) != 2 || trace.Events[1].Kind != RequestEntered {
+		t.Fatalf("trace while processing = %+v, want received and entered", trace)
+	}
+
+	close(release)
+	response := <-result
+	if got := response.VLCClock.Values[miner.ClockID]; got != 2 {
+		t.Fatalf("response clock = %d, want 2", got)
+	}
+	if err := miner.GetRequestTrace("req-blocked").Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type blockingProcessor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p blockingProcessor) ProcessTask(ctx context.Context, input string, inputNumber int) (*TaskOutcome, error) {
+	close(p.started)
+	<-p.release
+	return &TaskOutcome{OutputType: OutputReady, Output: input}, nil
+}
+
+func (p blockingProcessor) ProcessAdditionalInfo(ctx context.Context, originalInput, additionalInfo string, inputNumber int) (*TaskOutcome, error) {
+	return &TaskOutcome{OutputType: OutputReady, Output: originalInput}, nil
+}
+
+// gatedProcessor holds each task until its gate is closed, reporting the task once started
+type gatedProcessor struct {
+	started chan string
+	gates   map[string]chan struct{}
+}
+
+func (p gatedProcessor) ProcessTask(ctx context.Context, input string, inputNumber int) (*TaskOutcome, error) {
+	p.started <- input
+	<-p.gates[input]
+	return &TaskOutcome{OutputType: OutputReady, Output: input}, nil
+}
+
+func (p gatedProcessor) ProcessAdditionalInfo(ctx context.Context, originalInput, additionalInfo string, inputNumber int) (*TaskOutcome, error) {
+	return &TaskOutcome{OutputType: OutputReady, Output: originalInput}, nil
+}
+
+// TestOverlappingResponsesPassValidator finishes the later of two overlapping requests
+// first and checks validators still accept both as consecutive steps
+func TestOverlappingResponsesPassValidator(t *testing.T) {
+	processor := gatedProcessor{
+		started: make(chan string),
+		gates:   map[string]chan struct{}{"first": make(chan struct{}), "second": make(chan struct{})},
+	}
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(processor)
+
+	results := make(map[string]chan *MinerResponseMessage)
+	for i, task := range []string{"first", "second"} {
+		results[task] = make(chan *MinerResponseMessage, 1)
+		go func(task string, number int) {
+			results[task] <- miner.ProcessInput(task, number, "req-"+task)
+		}(task, i+1)
+		<-processor.started // Admitted in this order
+	}
+	close(processor.gates["second"])
+	second := <-results["second"]
+	close(processor.gates["first"])
+	first := <-results["first"]
+
+	if got := first.VLCClock.Values[MinerClockID]; got != 2 {
+		t.Fatalf("first response clock = %d, want 2", got)
+	}
+	if got := second.VLCClock.Values[MinerClockID]; got != 4 {
+		t.Fatalf("second response clock = %d, want 4", got)
+	}
+
+	// Delivered as they arrive: the second waits for the first
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+	validator.RegisterMiner(miner.ID, MinerClockID)
+	validator.EnableCausalDelivery(vlc.CausalBufferConfig{})
+	if delivered, err := validator.ReceiveMinerResponse(second); err != nil || len(delivered) != 0 {
+		t.Fatalf("second response = %v, %v, want it held", delivered, err)
+	}
+	delivered, err := validator.ReceiveMinerResponse(first)
+	if err != nil || len(delivered) != 2 || delivered[0] != first || delivered[1] != second {
+		t.Fatalf("first response delivered %v, %v, want both in admission order", delivered, err)
+	}
+
+	// Checked independently in admission order
+	observer := NewCoreValidator("validator-2", "subnet-test", ConsensusValidator, 0.25)
+	observer.RegisterMiner(miner.ID, MinerClockID)
+	for _, response := range []*MinerResponseMessage{first, second} {
+		if _, err := observer.ObserveMinerResponse(response); err != nil {
+			t.Fatalf("%s rejected: %v", response.RequestID, err)
+		}
+	}
+	if violations := append(validator.SequenceViolations(), observer.SequenceViolations()...); len(violations) != 0 {
+		t.Fatalf("violations recorded: %+v", violations)
+	}
+}
+
+// unpaidVerifier finds no payment locked for any task
+type unpaidVerifier struct{}
+
+func (unpaidVerifier) VerifyPaymentLocked(taskID string, agentAddr common.Address, minAmount *big.Int) (bool, error) {
+	return false, nil
+}
+
+// TestUnprocessedResponseSkipsSequence checks a refused task, which takes no VLC step, is
+// not counted against the miner's sequence and is not accepted
+func TestUnprocessedResponseSkipsSequence(t *testing.T) {
+	miner := NewCoreMiner("miner-1", "subnet-test")
+	miner.SetContextTaskProcessor(sleepyProcessor{})
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+	validator.RegisterMiner(miner.ID, MinerClockID)
+
+	first := miner.ProcessInput("task 1", 1, "req-1")
+	miner.SetPaymentVerifier(unpaidVerifier{}, "0x0000000000000000000000000000000000000001", "1")
+	refused := miner.ProcessInput("task 2", 2, "req-2")
+	miner.SetPaymentVerifier(nil, "", "")
+	next := miner.ProcessInput("task 3", 3, "req-3")
+
+	if !refused.Unprocessed || refused.VLCClock.Values[MinerClockID] != 2 {
+		t.Fatalf("refused response = %+v, want unprocessed at the unchanged clock", refused)
+	}
+	for _, response := range []*MinerResponseMessage{first, refused, next} {
+		if delivered, err := validator.ReceiveMinerResponse(response); err != nil || len(delivered) != 1 {
+			t.Fatalf("%s = %v, %v, want it delivered", response.RequestID, delivered, err)
+		}
+		checkpoint, err := validator.ObserveMinerResponse(response)
+		if err != nil {
+			t.Fatalf("%s rejected: %v", response.RequestID, err)
+		}
+		if (checkpoint == nil) != response.Unprocessed {
+			t.Fatalf("%s checkpoint = %v, want one only for processed steps", response.RequestID, checkpoint)
+		}
+	}
+	if violations := validator.SequenceViolations(); len(violations) != 0 {
+		t.Fatalf("violations recorded: %+v", violations)
+	}
+	if vote := validator.VoteOnOutput(refused); vote.Accept {
+		t.Fatalf("refused task accepted: %+v", vote)
+	}
+}
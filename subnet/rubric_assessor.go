@@ -0,0 +1,316 @@
+// Package subnet - Rubric-Based Quality Assessment
+//
+// This file implements a QualityAssessor driven by a per-subnet rubric (JSON file). Scoring
+// is deterministic: every validator holding the same rubric gives an output the same score,
+// with no model involved.
+//
+// Rubric:
+//   - Criteria, each with a weight and one check:
+//     keywords (share of keywords found, or any of them), length (character/word bounds),
+//     json_schema (output is JSON conforming to a schema, see json_schema.go) and
+//     regex (output must, or must not, match)
+//   - Quality = weighted mean of criterion scores (each 0.0-1.0)
+//   - Accept = quality >= pass_threshold and every required criterion passed
+//
+// The per-criterion breakdown is returned as a QualityReport and carried in the vote.
+package subnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultRubricPassThreshold is the quality a rubric requires to accept when it sets none
+const DefaultRubricPassThreshold = 0.7
+
+// ErrInvalidRubric is returned when a rubric cannot be used for assessment
+var ErrInvalidRubric = errors.New("invalid rubric")
+
+// RubricCheckType names the check a rubric criterion applies to an output
+type RubricCheckType string
+
+const (
+	CheckKeywords   RubricCheckType = "keywords"    // Output mentions keywords
+	CheckLength     RubricCheckType = "length"      // Output length within bounds
+	CheckJSONSchema RubricCheckType = "json_schema" // Output is JSON conforming to a schema
+	CheckRegex      RubricCheckType = "regex"       // Output matches (or avoids) a pattern
+)
+
+// Rubric is a per-subnet quality rubric
+type Rubric struct {
+	Name          string            `json:"name"`
+	Version       string            `json:"version,omitempty"`
+	SubnetID      string            `json:"subnet_id,omitempty"`      // Subnet the rubric is written for ("" = any)
+	PassThreshold float64           `json:"pass_threshold,omitempty"` // Minimum quality to accept (default 0.7)
+	Criteria      []RubricCriterion `json:"criteria"`
+}
+
+// RubricCriterion is one weighted check. Only the fields of its Type apply.
+type RubricCriterion struct {
+	Name     string          `json:"name"`
+	Weight   float64         `json:"weight"`
+	Required bool            `json:"required,omitempty"` // Failing it rejects the output regardless of quality
+	Type     RubricCheckType `json:"type"`
+
+	// keywords
+	Keywords      []string `json:"keywords,omitempty"`
+	MatchAny      bool     `json:"match_any,omitempty"` // Score 1 if any keyword is found (default: share found)
+	CaseSensitive bool     `json:"case_sensitive,omitempty"`
+
+	// length (0 = unbounded)
+	MinChars int `json:"min_chars,omitempty"`
+	MaxChars int `json:"max_chars,omitempty"`
+	MinWords int `json:"min_words,omitempty"`
+	MaxWords int `json:"max_words,omitempty"`
+
+	// json_schema
+	Schema json.RawMessage `json:"schema,omitempty"`
+
+	// regex
+	Pattern      string `json:"pattern,omitempty"`
+	MustNotMatch bool   `json:"must_not_match,omitempty"`
+}
+
+// RubricAssessor scores miner outputs against a rubric. Safe for concurrent use.
+type RubricAssessor struct {
+	rubric   Rubric
+	criteria []compiledCriterion
+}
+
+// compiledCriterion is a criterion with its pattern or schema compiled
+type compiledCriterion struct {
+	RubricCriterion
+	pattern *regexp.Regexp
+	schema  *JSONSchema
+}
+
+// NewRubricAssessor validates and compiles a rubric
+func NewRubricAssessor(rubric Rubric) (*RubricAssessor, error) {
+	if rubric.Name == "" {
+		return nil, fmt.Errorf("%w: no name", ErrInvalidRubric)
+	}
+	if len(rubric.Criteria) == 0 {
+		return nil, fmt.Errorf("%w: %s has no criteria", ErrInvalidRubric, rubric.Name)
+	}
+	if rubric.PassThreshold == 0 {
+		rubric.PassThreshold = DefaultRubricPassThreshold
+	}
+	if rubric.PassThreshold < 0 || rubric.PassThreshold > 1 {
+		return nil, fmt.Errorf("%w: %s pass_threshold %v outside [0, 1]", ErrInvalidRubric, rubric.Name, rubric.PassThreshold)
+	}
+
+	assessor := &RubricAssessor{rubric: rubric}
+	seen := make(map[string]bool)
+	for i, criterion := range rubric.Criteria {
+		if criterion.Name == "" {
+			return nil, fmt.Errorf("%w: criterion %d has no name", ErrInvalidRubric, i+1)
+		}
+		if seen[criterion.Name] {
+			return nil, fmt.Errorf("%w: criterion %q listed twice", ErrInvalidRubric, criterion.Name)
+		}
+		seen[criterion.Name] = true
+		if criterion.Weight <= 0 {
+			return nil, fmt.Errorf("%w: criterion %q needs a positive weight", ErrInvalidRubric, criterion.Name)
+		}
+
+		compiled, err := compileCriterion(criterion)
+		if err != nil {
+			return nil, fmt.Errorf("%w: criterion %q: %v", ErrInvalidRubric, criterion.Name, err)
+		}
+		assessor.criteria = append(assessor.criteria, compiled)
+	}
+	return assessor, nil
+}
+
+// LoadRubricAssessor reads a rubric file and compiles it. Unknown fields are rejected so a
+// misspelled check parameter cannot silently change scores.
+func LoadRubricAssessor(path string) (*RubricAssessor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rubric: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var rubric Rubric
+	if err := decoder.Decode(&rubric); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRubric, path, err)
+	}
+	return NewRubricAssessor(rubric)
+}
+
+// compileCriterion checks a criterion's parameters for its type
+func compileCriterion(criterion RubricCriterion) (compiledCriterion, error) {
+	compiled := compiledCriterion{RubricCriterion: criterion}
+	switch criterion.Type {
+	case CheckKeywords:
+		if len(criterion.Keywords) == 0 {
+			return compiled, errors.New("keywords check lists no keywords")
+		}
+	case CheckLength:
+		if criterion.MinChars < 0 || criterion.MaxChars < 0 || criterion.MinWords < 0 || criterion.MaxWords < 0 {
+			return compiled, errors.New("length bounds must not be negative")
+		}
+		if (criterion.MaxChars > 0 && criterion.MinChars > criterion.MaxChars) ||
+			(criterion.MaxWords > 0 && criterion.MinWords > criterion.MaxWords) {
+			return compiled, errors.New("length minimum above maximum")
+		}
+	case CheckJSONSchema:
+		if len(criterion.Schema) == 0 {
+			return compiled, errors.New("json_schema check has no schema")
+		}
+		schema, err := CompileJSONSchema(criterion.Schema)
+		if err != nil {
+			return compiled, err
+		}
+		compiled.schema = schema
+	case CheckRegex:
+		pattern, err := regexp.Compile(criterion.Pattern)
+		if err != nil {
+			return compiled, err
+		}
+		compiled.pattern = pattern
+	default:
+		return compiled, fmt.Errorf("unknown check type %q", criterion.Type)
+	}
+	return compiled, nil
+}
+
+// Rubric returns the rubric the assessor scores against
+func (r *RubricAssessor) Rubric() Rubric {
+	return r.rubric
+}
+
+// Name returns the rubric's name and version, as reported in quality reports
+func (r *RubricAssessor) Name() string {
+	if r.rubric.Version == "" {
+		return "rubric:" + r.rubric.Name
+	}
+	return fmt.Sprintf("rubric:%s@%s", r.rubric.Name, r.rubric.Version)
+}
+
+// AssessQuality scores a response against the rubric
+func (r *RubricAssessor) AssessQuality(response *MinerResponseMessage) (float64, bool) {
+	report := r.AssessDetailed(response)
+	return report.Quality, report.Accept
+}
+
+// AssessDetailed scores a response against the rubric and returns the per-criterion breakdown
+func (r *RubricAssessor) AssessDetailed(response *MinerResponseMessage) *QualityReport {
+	report := &QualityReport{Assessor: r.Name(), Criteria: make([]CriterionScore, 0, len(r.criteria))}
+
+	var weighted, totalWeight float64
+	requiredPassed := true
+	passed := 0
+	for _, criterion := range r.criteria {
+		score, detail := criterion.evaluate(response.Output)
+		result := CriterionScore{
+			Name:     criterion.Name,
+			Weight:   criterion.Weight,
+			Score:    score,
+			Passed:   score >= 1,
+			Required: criterion.Required,
+			Detail:   detail,
+		}
+		if result.Passed {
+			passed++
+		} else if criterion.Required {
+			requiredPassed = false
+		}
+		weighted += criterion.Weight * score
+		totalWeight += criterion.Weight
+		report.Criteria = append(report.Criteria, result)
+	}
+
+	report.Quality = clampQuality(weighted / totalWeight)
+	report.Accept = requiredPassed && report.Quality >= r.rubric.PassThreshold
+	fmt.Printf("📏 %s: %s quality %.2f, %d/%d criteria passed (accept=%v)\n",
+		r.Name(), response.RequestID, report.Quality, passed, len(r.criteria), report.Accept)
+	return report
+}
+
+// evaluate applies the criterion's check to an output and returns its score and an explanation
+func (c *compiledCriterion) evaluate(output string) (float64, string) {
+	switch c.Type {
+	case CheckKeywords:
+		return c.evaluateKeywords(output)
+	case CheckLength:
+		return c.evaluateLength(output)
+	case CheckJSONSchema:
+		if err := c.schema.ValidateJSON(stripCodeFence(output)); err != nil {
+			return 0, err.Error()
+		}
+		return 1, "conforms to schema"
+	case CheckRegex:
+		matched := c.pattern.MatchString(output)
+		switch {
+		case matched && c.MustNotMatch:
+			return 0, fmt.Sprintf("matches forbidden pattern %q", c.Pattern)
+		case !matched && !c.MustNotMatch:
+			return 0, fmt.Sprintf("does not match %q", c.Pattern)
+		}
+		return 1, ""
+	}
+	return 0, fmt.Sprintf("unknown check type %q", c.Type)
+}
+
+// evaluateKeywords scores the share of keywords found (or any keyword, with MatchAny)
+func (c *compiledCriterion) evaluateKeywords(output string) (float64, string) {
+	if !c.CaseSensitive {
+		output = strings.ToLower(output)
+	}
+	var missing []string
+	for _, keyword := range c.Keywords {
+		needle := keyword
+		if !c.CaseSensitive {
+			needle = strings.ToLower(keyword)
+		}
+		if !strings.Contains(output, needle) {
+			missing = append(missing, keyword)
+		}
+	}
+
+	found := len(c.Keywords) - len(missing)
+	if c.MatchAny {
+		if found > 0 {
+			return 1, ""
+		}
+		return 0, "none of " + strings.Join(c.Keywords, ", ")
+	}
+	if len(missing) == 0 {
+		return 1, ""
+	}
+	return float64(found) / float64(len(c.Keywords)), "missing " + strings.Join(missing, ", ")
+}
+
+// evaluateLength scores 1 if the output's character and word counts are within bounds
+func (c *compiledCriterion) evaluateLength(output string) (float64, string) {
+	chars := utf8.RuneCountInString(output)
+	words := len(strings.Fields(output))
+	detail := fmt.Sprintf("%d characters, %d words", chars, words)
+
+	if (c.MinChars > 0 && chars < c.MinChars) || (c.MaxChars > 0 && chars > c.MaxChars) ||
+		(c.MinWords > 0 && words < c.MinWords) || (c.MaxWords > 0 && words > c.MaxWords) {
+		return 0, detail + " (out of bounds)"
+	}
+	return 1, detail
+}
+
+// stripCodeFence removes a Markdown code fence around an output, if present
+func stripCodeFence(output string) string {
+	trimmed := strings.TrimSpace(output)
+	if !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") || len(trimmed) < 6 {
+		return trimmed
+	}
+	body := strings.TrimSuffix(trimmed, "```")
+	if newline := strings.IndexByte(body, '\n'); newline >= 0 {
+		return strings.TrimSpace(body[newline+1:]) // Drop the opening fence and language tag
+	}
+	return trimmed
+}
@@ -0,0 +1,338 @@
+// Package subnet - Declarative VLC Sequence Rules
+//
+// This file replaces hand-coded sequence checks with a rule table. Validators know each
+// VLC participant by registration (clock ID, kind, name); a rule declares how a kind's own
+// clock entry must advance for a message type. Validators refuse messages from senders
+// that were never registered instead of bootstrapping them from their first message.
+//
+// Default rules:
+//   - miner, MinerResponseType: +2 (message entering the miner, response leaving it)
+//   - validator, any type: +1, and the sender may not have seen anything the receiver has not
+//
+// A rule for an exact message type wins over the kind's wildcard ("") rule. New participant
+// kinds (e.g., a tool-calling agent) need only RegisterParticipant and AddSequenceRule.
+//
+// Every rejection is recorded as a SequenceViolation; SequenceViolations returns the log.
+package subnet
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// ParticipantKind classifies a VLC participant for sequence validation
+type ParticipantKind string
+
+const (
+	ParticipantMiner     ParticipantKind = "miner"
+	ParticipantValidator ParticipantKind = "validator"
+)
+
+// maxSequenceViolations bounds the violation log kept by a validator
+const maxSequenceViolations = 256
+
+// SequenceRule declares how a participant kind's own clock entry advances per message
+type SequenceRule struct {
+	Kind        ParticipantKind   `json:"kind"`
+	MessageType SubnetMessageType `json:"message_type,omitempty"` // "" matches any message type
+	Increment   uint64            `json:"increment"`              // Exact advance of the sender's entry
+	// RequireOthersDelivered rejects messages whose other entries are ahead of the receiver's
+	// clock, i.e. the sender saw events the receiver has not validated
+	RequireOthersDelivered bool `json:"require_others_delivered,omitempty"`
+}
+
+// SequenceParticipant is a registered VLC participant
+type SequenceParticipant struct {
+	ClockID  uint64          `json:"clock_id"`
+	Kind     ParticipantKind `json:"kind"`
+	Name     string          `json:"name"`
+	Baseline uint64          `json:"baseline"` // Entry value before the participant's first message
+}
+
+// SequenceViolationKind identifies why a message failed sequence validation
+type SequenceViolationKind string
+
+const (
+	ViolationUnknownSender  SequenceViolationKind = "unknown_sender"  // Sender never registered
+	ViolationNoRule         SequenceViolationKind = "no_rule"         // No rule for the sender's kind and message type
+	ViolationMissingClock   SequenceViolationKind = "missing_clock"   // Message carries no entry for its sender
+	ViolationWrongIncrement SequenceViolationKind = "wrong_increment" // Sender's entry did not advance as declared
+	ViolationOthersAhead    SequenceViolationKind = "others_ahead"    // Sender saw events the receiver has not
+	ViolationCausalDelivery SequenceViolationKind = "causal_delivery" // Causal buffer refused the message
+	ViolationClockRegressed SequenceViolationKind = "clock_regressed" // Entries below the sender's previous message
+)
+
+// SequenceViolation is a structured report of a rejected message. It unwraps to ErrVLCSequence.
+type SequenceViolation struct {
+	Kind        SequenceViolationKind `json:"kind"`
+	ValidatorID string                `json:"validator_id"`
+	SenderID    uint64                `json:"sender_id"`
+	Participant string                `json:"participant"`
+	MessageType SubnetMessageType     `json:"message_type,omitempty"`
+	RequestID   string                `json:"request_id,omitempty"`
+	Expected    uint64                `json:"expected,omitempty"`  // Expected value of the sender's entry
+	Got         uint64                `json:"got,omitempty"`       // Value carried by the message
+	Ahead       []uint64              `json:"ahead,omitempty"`     // Other entries ahead of the local clock
+	Regressed   []uint64              `json:"regressed,omitempty"` // Entries below the sender's previous message
+	Detail      string                `json:"detail,omitempty"`
+	Local       map[uint64]uint64     `json:"local"`    // Receiver's clock when the message was checked
+	Incoming    map[uint64]uint64     `json:"incoming"` // Message clock
+	At          time.Time             `json:"at"`
+}
+
+// Error describes the violation
+func (sv *SequenceViolation) Error() string {
+	message := fmt.Sprintf("%s: %s from %s", ErrVLCSequence, sv.Kind, sv.Participant)
+	switch sv.Kind {
+	case ViolationWrongIncrement:
+		message += fmt.Sprintf(" - expected entry %d = %d, got %d", sv.SenderID, sv.Expected, sv.Got)
+	case ViolationOthersAhead:
+		message += fmt.Sprintf(" - entries %v ahead of local clock", sv.Ahead)
+	case ViolationClockRegressed:
+		message += fmt.Sprintf(" - entries %v below its previous message", sv.Regressed)
+	}
+	if sv.Detail != "" {
+		message += " - " + sv.Detail
+	}
+	return message
+}
+
+// Unwrap makes errors.Is(violation, ErrVLCSequence) hold
+func (sv *SequenceViolation) Unwrap() error {
+	return ErrVLCSequence
+}
+
+// DefaultSequenceRules returns the PoCW protocol's increment rules
+func DefaultSequenceRules() []SequenceRule {
+	return []SequenceRule{
+		{Kind: ParticipantMiner, MessageType: MinerResponseType, Increment: 2},
+		{Kind: ParticipantValidator, Increment: 1, RequireOthersDelivered: true},
+	}
+}
+
+// SetSequenceRules replaces the validator's sequence rules
+func (v *CoreValidator) SetSequenceRules(rules []SequenceRule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.sequenceRules = append([]SequenceRule(nil), rules...)
+	v.syncCausalIncrementsLocked()
+}
+
+// AddSequenceRule adds a rule, replacing any rule for the same kind and message type
+func (v *CoreValidator) AddSequenceRule(rule SequenceRule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for i, existing := range v.sequenceRules {
+		if existing.Kind == rule.Kind && existing.MessageType == rule.MessageType {
+			v.sequenceRules[i] = rule
+			v.syncCausalIncrementsLocked()
+			return
+		}
+	}
+	v.sequenceRules = append(v.sequenceRules, rule)
+	v.syncCausalIncrementsLocked()
+}
+
+// RegisterParticipant makes a VLC participant known to the validator; messages from
+// unregistered clock IDs are refused. Re-registering updates kind and name.
+func (v *CoreValidator) RegisterParticipant(participant SequenceParticipant) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.registerParticipantLocked(participant)
+}
+
+// registerParticipantLocked records a participant (caller holds v.mu)
+func (v *CoreValidator) registerParticipantLocked(participant SequenceParticipant) {
+	if participant.Name == "" {
+		participant.Name = getParticipantName(participant.ClockID)
+	}
+	if v.participants == nil {
+		v.participants = make(map[uint64]SequenceParticipant)
+	}
+	v.participants[participant.ClockID] = participant
+	v.syncCausalIncrementsLocked()
+}
+
+// Participants returns the registered VLC participants ordered by clock ID
+func (v *CoreValidator) Participants() []SequenceParticipant {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	participants := make([]SequenceParticipant, 0, len(v.participants))
+	for _, participant := range v.participants {
+		participants = append(participants, participant)
+	}
+	sort.Slice(participants, func(i, j int) bool { return participants[i].ClockID < participants[j].ClockID })
+	return participants
+}
+
+// SequenceViolations returns the most recent sequence violations, oldest first
+func (v *CoreValidator) SequenceViolations() []SequenceViolation {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return append([]SequenceViolation(nil), v.violations...)
+}
+
+// CheckSequence validates a message clock against the sender's rule and, if it holds,
+// merges the clock into the validator's. Returns the violation otherwise.
+func (v *CoreValidator) CheckSequence(msgType SubnetMessageType, incomingClock *vlc.Clock, senderID uint64) *SequenceViolation {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	participant, rule, violation := v.checkSequenceLocked(msgType, incomingClock, senderID)
+	if violation != nil {
+		v.recordViolationLocked(violation)
+		return violation
+	}
+
+	v.MinerClock.Merge([]*vlc.Clock{incomingClock})
+	fmt.Printf("Validator %s: VLC sequence validated (+%d) for %s - %v\n", v.ID, rule.Increment, participant.Name, incomingClock.Values)
+	return nil
+}
+
+// checkSequenceLocked applies the sender's rule without changing state (caller holds v.mu)
+func (v *CoreValidator) checkSequenceLocked(msgType SubnetMessageType, incomingClock *vlc.Clock, senderID uint64) (SequenceParticipant, SequenceRule, *SequenceViolation) {
+	participant, rule, violation := v.ruleForLocked(msgType, senderID)
+	if violation != nil {
+		return participant, rule, v.stampViolationLocked(violation, incomingClock)
+	}
+
+	got, present := uint64(0), false
+	if incomingClock != nil {
+		got, present = incomingClock.Values[senderID]
+	}
+	if !present {
+		return participant, rule, v.stampViolationLocked(&SequenceViolation{
+			Kind: ViolationMissingClock, SenderID: senderID, Participant: participant.Name, MessageType: msgType,
+		}, incomingClock)
+	}
+
+	expected := v.entryLocked(participant) + rule.Increment
+	if got != expected {
+		return participant, rule, v.stampViolationLocked(&SequenceViolation{
+			Kind: ViolationWrongIncrement, SenderID: senderID, Participant: participant.Name, MessageType: msgType,
+			Expected: expected, Got: got,
+		}, incomingClock)
+	}
+
+	if rule.RequireOthersDelivered {
+		var ahead []uint64
+		for id, value := range incomingClock.Values {
+			if id != senderID && value > v.MinerClock.Values[id] {
+				ahead = append(ahead, id)
+			}
+		}
+		if len(ahead) > 0 {
+			sort.Slice(ahead, func(i, j int) bool { return ahead[i] < ahead[j] })
+			return participant, rule, v.stampViolationLocked(&SequenceViolation{
+				Kind: ViolationOthersAhead, SenderID: senderID, Participant: participant.Name, MessageType: msgType,
+				Ahead: ahead,
+			}, incomingClock)
+		}
+	}
+	return participant, rule, nil
+}
+
+// ruleForLocked resolves a sender's registration and rule (caller holds v.mu)
+func (v *CoreValidator) ruleForLocked(msgType SubnetMessageType, senderID uint64) (SequenceParticipant, SequenceRule, *SequenceViolation) {
+	participant, known := v.participants[senderID]
+	if !known {
+		return SequenceParticipant{ClockID: senderID, Name: getParticipantName(senderID)}, SequenceRule{}, &SequenceViolation{
+			Kind: ViolationUnknownSender, SenderID: senderID, Participant: getParticipantName(senderID), MessageType: msgType,
+			Detail: "not a registered participant",
+		}
+	}
+	if rule, ok := v.findRuleLocked(participant.Kind, msgType); ok {
+		return participant, rule, nil
+	}
+	return participant, SequenceRule{}, &SequenceViolation{
+		Kind: ViolationNoRule, SenderID: senderID, Participant: participant.Name, MessageType: msgType,
+		Detail: fmt.Sprintf("no rule for %s messages of type %q", participant.Kind, msgType),
+	}
+}
+
+// findRuleLocked returns the rule for a kind and message type, preferring an exact
+// message type over the kind's wildcard rule (caller holds v.mu)
+func (v *CoreValidator) findRuleLocked(kind ParticipantKind, msgType SubnetMessageType) (SequenceRule, bool) {
+	var wildcard *SequenceRule
+	for i := range v.sequenceRules {
+		rule := &v.sequenceRules[i]
+		if rule.Kind != kind {
+			continue
+		}
+		if rule.MessageType == msgType {
+			return *rule, true
+		}
+		if rule.MessageType == "" && wildcard == nil {
+			wildcard = rule
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return SequenceRule{}, false
+}
+
+// entryLocked returns the local value of a participant's entry, or its baseline before
+// its first message (caller holds v.mu)
+func (v *CoreValidator) entryLocked(participant SequenceParticipant) uint64 {
+	if value, ok := v.MinerClock.Values[participant.ClockID]; ok {
+		return value
+	}
+	return participant.Baseline
+}
+
+// baselineClockLocked returns the local clock with every registered participant's entry
+// present, so causal delivery never bootstraps a sender (caller holds v.mu)
+func (v *CoreValidator) baselineClockLocked() *vlc.Clock {
+	local := v.MinerClock.Copy()
+	for id, participant := range v.participants {
+		if _, ok := local.Values[id]; !ok {
+			local.Values[id] = participant.Baseline
+		}
+	}
+	return local
+}
+
+// syncCausalIncrementsLocked points the causal buffer's per-sender increments at the
+// rules for each participant's responses (caller holds v.mu)
+func (v *CoreValidator) syncCausalIncrementsLocked() {
+	if v.causalBuffer == nil {
+		return
+	}
+	for id, participant := range v.participants {
+		if rule, ok := v.findRuleLocked(participant.Kind, MinerResponseType); ok && rule.Increment > 0 {
+			v.causalBuffer.SetIncrement(id, rule.Increment)
+		}
+	}
+}
+
+// stampViolationLocked fills a violation's context (caller holds v.mu)
+func (v *CoreValidator) stampViolationLocked(violation *SequenceViolation, incomingClock *vlc.Clock) *SequenceViolation {
+	violation.ValidatorID = v.ID
+	violation.Local = v.MinerClock.Copy().Values
+	violation.Incoming = incomingClock.Copy().Values
+	violation.At = time.Now()
+	return violation
+}
+
+// recordViolationLocked logs a violation and keeps it in the bounded log (caller holds v.mu)
+func (v *CoreValidator) recordViolationLocked(violation *SequenceViolation) {
+	fmt.Printf("🚫 Validator %s: %v (local %v, got %v)\n", v.ID, violation, violation.Local, violation.Incoming)
+	v.violations = append(v.violations, *violation)
+	if excess := len(v.violations) - maxSequenceViolations; excess > 0 {
+		v.violations = append([]SequenceViolation(nil), v.violations[excess:]...)
+	}
+}
+
+// participantNameLocked returns a participant's registered name (caller holds v.mu)
+func (v *CoreValidator) participantNameLocked(clockID uint64) string {
+	if participant, ok := v.participants[clockID]; ok {
+		return participant.Name
+	}
+	return getParticipantName(clockID)
+}
@@ -0,0 +1,134 @@
+package subnet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/vlc"
+)
+
+// clockAt builds a clock with the given entries
+func clockAt(values map[uint64]uint64) *vlc.Clock {
+	clock := vlc.New()
+	for id, value := range values {
+		clock.Values[id] = value
+	}
+	return clock
+}
+
+func TestCheckSequenceMinerIncrement(t *testing.T) {
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+
+	if violation := validator.CheckSequence(MinerResponseType, clockAt(map[uint64]uint64{MinerClockID: 2}), MinerClockID); violation != nil {
+		t.Fatalf("+2 miner response: %v", violation)
+	}
+
+	// +1 is half a step: the message entered the miner but never left
+	violation := validator.CheckSequence(MinerResponseType, clockAt(map[uint64]uint64{MinerClockID: 3}), MinerClockID)
+	if violation == nil || violation.Kind != ViolationWrongIncrement || violation.Expected != 4 || violation.Got != 3 {
+		t.Fatalf("+1 miner response = %+v, want %s expecting 4", violation, ViolationWrongIncrement)
+	}
+	if !errors.Is(violation, ErrVLCSequence) {
+		t.Fatalf("violation %v does not unwrap to %v", violation, ErrVLCSequence)
+	}
+	if got := validator.GetLastMinerClock().Values[MinerClockID]; got != 2 {
+		t.Fatalf("validator clock = %d after a rejected message, want 2", got)
+	}
+
+	// A replay of an accepted entry is rejected too
+	if violation := validator.CheckSequence(MinerResponseType, clockAt(map[uint64]uint64{MinerClockID: 2}), MinerClockID); violation == nil || violation.Kind != ViolationWrongIncrement {
+		t.Fatalf("replayed miner response = %+v, want %s", violation, ViolationWrongIncrement)
+	}
+	if violations := validator.SequenceViolations(); len(violations) != 2 || violations[0].Got != 3 {
+		t.Fatalf("violation log = %+v, want both rejections oldest first", violations)
+	}
+}
+
+func TestCheckSequenceRefusesUnregistered(t *testing.T) {
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+
+	// Never bootstrapped from its first message
+	if violation := validator.CheckSequence(ValidatorVoteType, clockAt(map[uint64]uint64{7: 1}), 7); violation == nil || violation.Kind != ViolationUnknownSender {
+		t.Fatalf("message from an unregistered sender = %+v, want %s", violation, ViolationUnknownSender)
+	}
+	if violation := validator.CheckSequence(ValidatorVoteType, clockAt(map[uint64]uint64{MinerClockID: 2}), MinerClockID); violation == nil || violation.Kind != ViolationNoRule {
+		t.Fatalf("miner message of a type without a rule = %+v, want %s", violation, ViolationNoRule)
+	}
+	if violation := validator.CheckSequence(MinerResponseType, clockAt(map[uint64]uint64{3: 1}), MinerClockID); violation == nil || violation.Kind != ViolationMissingClock {
+		t.Fatalf("miner response without the miner's entry = %+v, want %s", violation, ViolationMissingClock)
+	}
+	if validator.CheckSequence(MinerResponseType, nil, MinerClockID) == nil {
+		t.Fatal("miner response without a clock accepted")
+	}
+}
+
+func TestCheckSequenceValidatorRule(t *testing.T) {
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+	peer := ValidatorClockID(1)
+	validator.RegisterParticipant(SequenceParticipant{ClockID: peer, Kind: ParticipantValidator, Name: "validator-2"})
+
+	if violation := validator.CheckSequence(ValidatorVoteType, clockAt(map[uint64]uint64{peer: 1}), peer); violation != nil {
+		t.Fatalf("+1 validator message: %v", violation)
+	}
+
+	// The peer saw a miner response this validator has not validated
+	violation := validator.CheckSequence(ValidatorVoteType, clockAt(map[uint64]uint64{peer: 2, MinerClockID: 2}), peer)
+	if violation == nil || violation.Kind != ViolationOthersAhead || len(violation.Ahead) != 1 || violation.Ahead[0] != MinerClockID {
+		t.Fatalf("validator message ahead of the local clock = %+v, want %s on the miner's entry", violation, ViolationOthersAhead)
+	}
+
+	if violation := validator.CheckSequence(MinerResponseType, clockAt(map[uint64]uint64{MinerClockID: 2}), MinerClockID); violation != nil {
+		t.Fatalf("+2 miner response: %v", violation)
+	}
+	if violation := validator.CheckSequence(ValidatorVoteType, clockAt(map[uint64]uint64{peer: 2, MinerClockID: 2}), peer); violation != nil {
+		t.Fatalf("validator message once the miner response is validated: %v", violation)
+	}
+}
+
+func TestSequenceRulesExtend(t *testing.T) {
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+
+	// A new kind of participant needs only a registration and a rule
+	const agentClockID = 100
+	validator.RegisterParticipant(SequenceParticipant{ClockID: agentClockID, Kind: "agent", Name: "agent-1", Baseline: 10})
+	validator.AddSequenceRule(SequenceRule{Kind: "agent", MessageType: MinerResponseType, Increment: 3})
+	if violation := validator.CheckSequence(MinerResponseType, clockAt(map[uint64]uint64{agentClockID: 13}), agentClockID); violation != nil {
+		t.Fatalf("agent message 3 past its baseline: %v", violation)
+	}
+
+	// An exact message type wins over the kind's wildcard rule
+	peer := ValidatorClockID(1)
+	validator.RegisterParticipant(SequenceParticipant{ClockID: peer, Kind: ParticipantValidator})
+	validator.AddSequenceRule(SequenceRule{Kind: ParticipantValidator, MessageType: HeartbeatType, Increment: 2})
+	if violation := validator.CheckSequence(HeartbeatType, clockAt(map[uint64]uint64{peer: 1}), peer); violation == nil || violation.Expected != 2 {
+		t.Fatalf("+1 heartbeat under a +2 heartbeat rule = %+v, want it rejected", violation)
+	}
+	if violation := validator.CheckSequence(ValidatorVoteType, clockAt(map[uint64]uint64{peer: 1}), peer); violation != nil {
+		t.Fatalf("+1 vote under the wildcard rule: %v", violation)
+	}
+
+	// Adding a rule for the same kind and type replaces it
+	validator.AddSequenceRule(SequenceRule{Kind: ParticipantValidator, MessageType: HeartbeatType, Increment: 1})
+	if violation := validator.CheckSequence(HeartbeatType, clockAt(map[uint64]uint64{peer: 2}), peer); violation != nil {
+		t.Fatalf("+1 heartbeat after the rule was replaced: %v", violation)
+	}
+
+	participants := validator.Participants()
+	if len(participants) != 3 || participants[0].ClockID != MinerClockID || participants[2].ClockID != agentClockID {
+		t.Fatalf("participants = %+v, want the miner, the peer and the agent by clock ID", participants)
+	}
+	if participants[1].Name != getParticipantName(peer) {
+		t.Fatalf("unnamed participant registered as %q, want %q", participants[1].Name, getParticipantName(peer))
+	}
+}
+
+func TestSequenceViolationLogIsBounded(t *testing.T) {
+	validator := NewCoreValidator("validator-1", "subnet-test", ConsensusValidator, 0.25)
+	for i := 0; i < maxSequenceViolations+10; i++ {
+		validator.CheckSequence(MinerResponseType, clockAt(map[uint64]uint64{MinerClockID: uint64(i + 3)}), MinerClockID)
+	}
+	violations := validator.SequenceViolations()
+	if len(violations) != maxSequenceViolations || violations[len(violations)-1].Got != maxSequenceViolations+12 {
+		t.Fatalf("violation log holds %d ending at %d, want the latest %d", len(violations), violations[len(violations)-1].Got, maxSequenceViolations)
+	}
+}
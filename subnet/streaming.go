@@ -0,0 +1,304 @@
+// Package subnet - Streaming Miner Output
+//
+// This file lets a miner stream an output to the user-facing validator while it is
+// being generated. Chunks are one-way MinerChunkMessages; they are not VLC events.
+// The miner's entering/leaving increments, validator votes and payments still apply
+// only to the final MinerResponseMessage carrying the complete output.
+//
+// Integrity:
+//   - Each chunk carries the rolling digest d_i = keccak256(d_{i-1} || chunk_i), seeded
+//     with keccak256("pocw-stream:" || requestID)
+//   - The final response carries the last digest (StreamDigest) and every chunk's size
+//     (ChunkSizes), so any validator can recompute the digest from Output alone
+//   - The leader's ChunkAssembler additionally checks that the chunks it showed the
+//     user are exactly the final output
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// streamDigestPrefix domain-separates stream digests from other keccak256 uses
+const streamDigestPrefix = "pocw-stream:"
+
+var (
+	// ErrStreamMismatch is returned when a streamed output does not match its chunks or digest
+	ErrStreamMismatch = errors.New("streamed output mismatch")
+	// ErrStreamOutOfOrder is returned when a chunk's digest does not extend the stream
+	ErrStreamOutOfOrder = errors.New("stream chunk out of order")
+)
+
+// StreamingTaskProcessor is implemented by processors that can emit an output incrementally.
+// emit must be called with consecutive pieces of the output; the returned outcome's Output
+// (if set) must equal their concatenation. A processor may return NeedMoreInfo only if it
+// has not emitted anything.
+type StreamingTaskProcessor interface {
+	StreamTask(ctx context.Context, input string, inputNumber int, emit func(chunk string) error) (*TaskOutcome, error)
+}
+
+// ChunkSink delivers one signed chunk to the user-facing validator
+type ChunkSink func(ctx context.Context, chunk *MinerChunkMessage) error
+
+// ChunkListener is notified of every in-order chunk the leader receives,
+// with the output assembled so far
+type ChunkListener func(chunk *MinerChunkMessage, partial string)
+
+// streamSeed returns the initial rolling digest for a request
+func streamSeed(requestID string) []byte {
+	return crypto.Keccak256([]byte(streamDigestPrefix + requestID))
+}
+
+// encodeStreamDigest renders a digest in the 0x-prefixed hex form used on the wire
+func encodeStreamDigest(digest []byte) string {
+	return fmt.Sprintf("0x%x", digest)
+}
+
+// nextStreamDigest extends a rolling digest with one chunk
+func nextStreamDigest(previous []byte, chunk string) []byte {
+	return crypto.Keccak256(previous, []byte(chunk))
+}
+
+// ComputeStreamDigest recomputes the rolling digest of output split into chunks of the given sizes
+func ComputeStreamDigest(requestID, output string, sizes []int) (string, error) {
+	digest := streamSeed(requestID)
+	offset := 0
+	for i, size := range sizes {
+		if size < 0 || offset+size > len(output) {
+			return "", fmt.Errorf("%w: chunk %d size %d exceeds output", ErrStreamMismatch, i, size)
+		}
+		digest = nextStreamDigest(digest, output[offset:offset+size])
+		offset += size
+	}
+	if offset != len(output) {
+		return "", fmt.Errorf("%w: chunks cover %d of %d bytes", ErrStreamMismatch, offset, len(output))
+	}
+	return encodeStreamDigest(digest), nil
+}
+
+// VerifyStreamDigest checks a streamed response's StreamDigest against its Output.
+// Responses that were not streamed pass.
+func VerifyStreamDigest(response *MinerResponseMessage) error {
+	if response.StreamDigest == "" && len(response.ChunkSizes) == 0 {
+		return nil
+	}
+	digest, err := ComputeStreamDigest(response.RequestID, response.Output, response.ChunkSizes)
+	if err != nil {
+		return err
+	}
+	if digest != response.StreamDigest {
+		return fmt.Errorf("%w: %s digest %s, output hashes to %s", ErrStreamMismatch, response.RequestID, response.StreamDigest, digest)
+	}
+	return nil
+}
+
+// ChunkAssembler reassembles a streamed output on the leader, verifying each chunk's
+// rolling digest. Chunks arriving early are held until the gap is filled.
+type ChunkAssembler struct {
+	RequestID string
+
+	chunks  []string
+	digest  []byte
+	pending map[int]*MinerChunkMessage // sequence -> chunk waiting for its predecessors
+}
+
+// NewChunkAssembler creates an assembler for one request's stream
+func NewChunkAssembler(requestID string) *ChunkAssembler {
+	return &ChunkAssembler{
+		RequestID: requestID,
+		digest:    streamSeed(requestID),
+		pending:   make(map[int]*MinerChunkMessage),
+	}
+}
+
+// Add accepts a chunk and returns the chunks that are now in order (possibly none)
+func (a *ChunkAssembler) Add(chunk *MinerChunkMessage) ([]*MinerChunkMessage, error) {
+	if chunk.RequestID != a.RequestID {
+		return nil, fmt.Errorf("%w: chunk for %s in stream %s", ErrStreamOutOfOrder, chunk.RequestID, a.RequestID)
+	}
+	if chunk.Sequence < len(a.chunks) {
+		return nil, fmt.Errorf("%w: duplicate chunk %d of %s", ErrStreamOutOfOrder, chunk.Sequence, a.RequestID)
+	}
+	a.pending[chunk.Sequence] = chunk
+
+	var delivered []*MinerChunkMessage
+	for {
+		next, ok := a.pending[len(a.chunks)]
+		if !ok {
+			return delivered, nil
+		}
+		digest := nextStreamDigest(a.digest, next.Chunk)
+		if encodeStreamDigest(digest) != next.Digest {
+			return delivered, fmt.Errorf("%w: chunk %d of %s does not extend the stream digest",
+				ErrStreamOutOfOrder, next.Sequence, a.RequestID)
+		}
+		delete(a.pending, next.Sequence)
+		a.chunks = append(a.chunks, next.Chunk)
+		a.digest = digest
+		delivered = append(delivered, next)
+	}
+}
+
+// Output returns the output assembled from in-order chunks so far
+func (a *ChunkAssembler) Output() string {
+	return strings.Join(a.chunks, "")
+}
+
+// Verify checks that a final response is exactly the streamed chunks
+func (a *ChunkAssembler) Verify(response *MinerResponseMessage) error {
+	if len(a.pending) > 0 {
+		return fmt.Errorf("%w: %d chunk(s) of %s never became contiguous", ErrStreamMismatch, len(a.pending), a.RequestID)
+	}
+	if len(response.ChunkSizes) != len(a.chunks) {
+		return fmt.Errorf("%w: response lists %d chunks, received %d", ErrStreamMismatch, len(response.ChunkSizes), len(a.chunks))
+	}
+	for i, chunk := range a.chunks {
+		if response.ChunkSizes[i] != len(chunk) {
+			return fmt.Errorf("%w: chunk %d is %d bytes, response says %d", ErrStreamMismatch, i, len(chunk), response.ChunkSizes[i])
+		}
+	}
+	if a.Output() != response.Output {
+		return fmt.Errorf("%w: final output differs from streamed chunks", ErrStreamMismatch)
+	}
+	if encodeStreamDigest(a.digest) != response.StreamDigest {
+		return fmt.Errorf("%w: final digest differs from streamed chunks", ErrStreamMismatch)
+	}
+	return nil
+}
+
+// SetStreamTransport enables streaming: chunks of requests that ask for it are pushed
+// to the requesting validator over this transport
+func (m *CoreMiner) SetStreamTransport(t Transport) {
+	m.streamTransport = t
+}
+
+// ProcessInputStream is ProcessInputContext with streaming: if the task processor
+// implements StreamingTaskProcessor, chunks are signed and passed to sink (addressed to
+// receiver) as they are produced. The returned response carries the complete output,
+// its StreamDigest and ChunkSizes. A sink error fails the task.
+func (m *CoreMiner) ProcessInputStream(ctx context.Context, input string, inputNumber int, requestID string, receiver string, sink ChunkSink) *MinerResponseMessage {
+	if m.streamProcessor == nil || sink == nil {
+		return m.ProcessInputContext(ctx, input, inputNumber, requestID)
+	}
+	return m.processInput(ctx, input, inputNumber, requestID, func(ctx context.Context, response *MinerResponseMessage) (*TaskOutcome, error) {
+		return m.streamTask(ctx, response, input, receiver, sink)
+	})
+}
+
+// streamTask runs the streaming processor, signing and forwarding each chunk
+func (m *CoreMiner) streamTask(ctx context.Context, response *MinerResponseMessage, input string, receiver string, sink ChunkSink) (*TaskOutcome, error) {
+	digest := streamSeed(response.RequestID)
+	var chunks []string
+
+	emit := func(piece string) error {
+		digest = nextStreamDigest(digest, piece)
+		chunk := &MinerChunkMessage{
+			SubnetMessage: SubnetMessage{
+				SubnetID:  m.SubnetID,
+				RequestID: response.RequestID,
+				Type:      MinerChunkType,
+				Sender:    m.ID,
+				Receiver:  receiver,
+				Timestamp: time.Now().Unix(),
+			},
+			InputNumber: response.InputNumber,
+			Sequence:    len(chunks),
+			Chunk:       piece,
+			Digest:      encodeStreamDigest(digest),
+		}
+		if m.signer != nil {
+			if err := m.signer.Sign(chunk); err != nil {
+				return err
+			}
+		}
+		if err := sink(ctx, chunk); err != nil {
+			return fmt.Errorf("chunk %d undeliverable: %w", chunk.Sequence, err)
+		}
+		chunks = append(chunks, piece)
+		return nil
+	}
+
+	outcome, err := m.streamProcessor.StreamTask(ctx, input, response.InputNumber, emit)
+	if err != nil || outcome == nil || len(chunks) == 0 {
+		return outcome, err // Nothing streamed: an ordinary outcome
+	}
+
+	streamed := strings.Join(chunks, "")
+	switch {
+	case outcome.OutputType != OutputReady:
+		return nil, fmt.Errorf("%w: %s after %d streamed chunks", ErrStreamMismatch, outcome.OutputType, len(chunks))
+	case outcome.Output == "":
+		outcome.Output = streamed
+	case outcome.Output != streamed:
+		return nil, fmt.Errorf("%w: final output differs from streamed chunks", ErrStreamMismatch)
+	}
+
+	response.StreamDigest = encodeStreamDigest(digest)
+	for _, chunk := range chunks {
+		response.ChunkSizes = append(response.ChunkSizes, len(chunk))
+	}
+	return outcome, nil
+}
+
+// SetChunkListener registers a callback for streamed chunks (e.g., to show them to the user)
+func (v *CoreValidator) SetChunkListener(listener ChunkListener) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.chunkListener = listener
+}
+
+// ReceiveChunk feeds a streamed chunk into the request's assembler and notifies the
+// chunk listener of every chunk that is now in order
+func (v *CoreValidator) ReceiveChunk(chunk *MinerChunkMessage) error {
+	v.mu.Lock()
+	if v.streams == nil {
+		v.streams = make(map[string]*ChunkAssembler)
+	}
+	assembler, ok := v.streams[chunk.RequestID]
+	if !ok {
+		assembler = NewChunkAssembler(chunk.RequestID)
+		v.streams[chunk.RequestID] = assembler
+	}
+	delivered, err := assembler.Add(chunk)
+	partials := make([]string, len(delivered))
+	for i := range delivered {
+		partials[i] = strings.Join(assembler.chunks[:len(assembler.chunks)-len(delivered)+i+1], "")
+	}
+	listener := v.chunkListener
+	v.mu.Unlock()
+
+	if listener != nil {
+		for i, inOrder := range delivered {
+			listener(inOrder, partials[i])
+		}
+	}
+	return err
+}
+
+// VerifyStreamedOutput checks a final response against the chunks this validator
+// received for it, then discards the stream. Responses that were not streamed pass.
+func (v *CoreValidator) VerifyStreamedOutput(response *MinerResponseMessage) error {
+	v.mu.Lock()
+	assembler, ok := v.streams[response.RequestID]
+	delete(v.streams, response.RequestID)
+	v.mu.Unlock()
+
+	if !ok {
+		if len(response.ChunkSizes) > 0 {
+			return fmt.Errorf("%w: %s was streamed but no chunks arrived", ErrStreamMismatch, response.RequestID)
+		}
+		return nil
+	}
+	return assembler.Verify(response)
+}
+
+// NewStreamingUserInputMessage builds a signed user input that asks the miner to
+// stream its output back to this validator
+func (v *CoreValidator) NewStreamingUserInputMessage(requestID, minerID, input string, inputNumber int) *UserInputMessage {
+	return v.newUserInputMessage(requestID, minerID, input, inputNumber, true)
+}
@@ -0,0 +1,270 @@
+// Package subnet - Multi-Miner Task Routing
+//
+// This file lets a subnet host several miners. The round leader asks a TaskRouter which
+// miner serves each task; the assignment is sticky, so every clarification turn of a task
+// goes to the same miner, and the result is credited back to that miner.
+//
+// Each miner advances its own VLC entry (MinerClockIDFor its index). Validators learn
+// which entry belongs to which miner through RegisterMiner and validate every response
+// against the sending miner's entry.
+//
+// Strategies:
+//   - round_robin: miners take turns in registration order
+//   - least_loaded: fewest tasks in flight (ties rotate)
+//   - reputation: miners without results first, then highest locally observed reputation
+//     (ties rotate); see MinerStats.Reputation
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RoutingStrategy selects how a TaskRouter assigns tasks to miners
+type RoutingStrategy string
+
+const (
+	RouteRoundRobin  RoutingStrategy = "round_robin"  // Miners take turns
+	RouteLeastLoaded RoutingStrategy = "least_loaded" // Fewest tasks in flight
+	RouteReputation  RoutingStrategy = "reputation"   // Best success-weighted quality so far
+)
+
+// reputationPrior is the score a miner's reputation is smoothed towards, so a few
+// results do not dominate it
+const reputationPrior = 0.5
+
+var (
+	// ErrNoMiners is returned when a router has no miner to assign a task to
+	ErrNoMiners = errors.New("no miners available")
+	// ErrUnknownMiner is returned when a task is pinned to a miner the router does not know
+	ErrUnknownMiner = errors.New("unknown miner")
+)
+
+// ParseRoutingStrategy parses a strategy name; the empty string selects round robin
+func ParseRoutingStrategy(name string) (RoutingStrategy, error) {
+	switch strategy := RoutingStrategy(strings.ToLower(strings.TrimSpace(name))); strategy {
+	case "":
+		return RouteRoundRobin, nil
+	case RouteRoundRobin, RouteLeastLoaded, RouteReputation:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown routing strategy %q (expected %s, %s or %s)",
+			name, RouteRoundRobin, RouteLeastLoaded, RouteReputation)
+	}
+}
+
+// MinerStats is a router's view of one miner
+type MinerStats struct {
+	MinerID    string  `json:"miner_id"`
+	InFlight   int     `json:"in_flight"`   // Tasks assigned and not yet completed or released
+	Completed  int     `json:"completed"`   // Tasks with a recorded result
+	Succeeded  int     `json:"succeeded"`   // Completed tasks that were accepted and delivered
+	QualitySum float64 `json:"quality_sum"` // Sum of consensus quality over succeeded tasks
+}
+
+// Reputation returns the miner's success-weighted mean quality, smoothed towards
+// reputationPrior: (QualitySum + prior) / (Completed + 1). Failed tasks count as 0.
+func (s MinerStats) Reputation() float64 {
+	return (s.QualitySum + reputationPrior) / float64(s.Completed+1)
+}
+
+// TaskRouter assigns tasks to the miners of a subnet. Safe for concurrent use.
+type TaskRouter struct {
+	Strategy RoutingStrategy
+
+	mu          sync.Mutex
+	miners      []string               // Miner IDs in registration order
+	stats       map[string]*MinerStats // minerID -> running stats
+	assignments map[string]string      // requestID -> minerID (tasks in flight)
+	next        int                    // Index where the next rotation starts
+}
+
+// NewTaskRouter creates a router over the given miners
+func NewTaskRouter(strategy RoutingStrategy, minerIDs []string) *TaskRouter {
+	router := &TaskRouter{
+		Strategy:    strategy,
+		stats:       make(map[string]*MinerStats),
+		assignments: make(map[string]string),
+	}
+	for _, id := range minerIDs {
+		router.AddMiner(id)
+	}
+	return router
+}
+
+// AddMiner makes a miner eligible for new tasks (no-op if already known)
+func (r *TaskRouter) AddMiner(minerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.stats[minerID]; exists {
+		return
+	}
+	r.miners = append(r.miners, minerID)
+	r.stats[minerID] = &MinerStats{MinerID: minerID}
+}
+
+// Miners returns the router's miner IDs in registration order
+func (r *TaskRouter) Miners() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.miners...)
+}
+
+// Route assigns a task to a miner according to the router's strategy. A task that is
+// already assigned keeps its miner.
+func (r *TaskRouter) Route(requestID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if minerID, ok := r.assignments[requestID]; ok {
+		return minerID, nil
+	}
+	if len(r.miners) == 0 {
+		return "", ErrNoMiners
+	}
+
+	chosen := r.next % len(r.miners)
+	for offset := 1; offset < len(r.miners); offset++ {
+		candidate := (r.next + offset) % len(r.miners)
+		if r.betterLocked(r.miners[candidate], r.miners[chosen]) {
+			chosen = candidate
+		}
+	}
+	r.next = chosen + 1
+
+	minerID := r.miners[chosen]
+	r.assignLocked(requestID, minerID)
+	return minerID, nil
+}
+
+// Assign pins a task to a specific miner (e.g., all tasks of a payment session)
+func (r *TaskRouter) Assign(requestID, minerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.stats[minerID]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownMiner, minerID)
+	}
+	if assigned, ok := r.assignments[requestID]; ok {
+		if assigned != minerID {
+			return fmt.Errorf("task %s already assigned to %s", requestID, assigned)
+		}
+		return nil
+	}
+	r.assignLocked(requestID, minerID)
+	return nil
+}
+
+// betterLocked reports whether candidate should be preferred over current (caller holds r.mu).
+// Round robin never prefers a later candidate, so the rotation start wins.
+func (r *TaskRouter) betterLocked(candidate, current string) bool {
+	c, cur := r.stats[candidate], r.stats[current]
+	switch r.Strategy {
+	case RouteLeastLoaded:
+		return c.InFlight < cur.InFlight
+	case RouteReputation:
+		if untried := c.Completed == 0; untried != (cur.Completed == 0) {
+			return untried // Every miner gets a chance to build a reputation
+		}
+		if c.Reputation() != cur.Reputation() {
+			return c.Reputation() > cur.Reputation()
+		}
+		return c.InFlight < cur.InFlight
+	default:
+		return false
+	}
+}
+
+// assignLocked records an assignment (caller holds r.mu)
+func (r *TaskRouter) assignLocked(requestID, minerID string) {
+	r.assignments[requestID] = minerID
+	r.stats[minerID].InFlight++
+}
+
+// AssignedMiner returns the miner serving an in-flight task
+func (r *TaskRouter) AssignedMiner(requestID string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	minerID, ok := r.assignments[requestID]
+	return minerID, ok
+}
+
+// Complete records a task's result against the miner that served it and ends the assignment.
+// Returns the miner ID, or "" if the task was not assigned.
+func (r *TaskRouter) Complete(requestID string, success bool, quality float64) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	minerID, ok := r.assignments[requestID]
+	if !ok {
+		return ""
+	}
+	delete(r.assignments, requestID)
+
+	stats := r.stats[minerID]
+	stats.InFlight--
+	stats.Completed++
+	if success {
+		stats.Succeeded++
+		stats.QualitySum += quality
+	}
+	return minerID
+}
+
+// Release ends a task's assignment without recording a result (e.g., the round was dropped).
+// Releasing a completed or unknown task is a no-op.
+func (r *TaskRouter) Release(requestID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	minerID, ok := r.assignments[requestID]
+	if !ok {
+		return
+	}
+	delete(r.assignments, requestID)
+	r.stats[minerID].InFlight--
+}
+
+// Stats returns a snapshot of every miner's stats in registration order
+func (r *TaskRouter) Stats() []MinerStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]MinerStats, 0, len(r.miners))
+	for _, id := range r.miners {
+		stats = append(stats, *r.stats[id])
+	}
+	return stats
+}
+
+// RegisterMiner tells the validator which VLC node ID a miner advances and registers that
+// node as a miner participant. Responses from unregistered miners are validated against
+// MinerClockID.
+func (v *CoreValidator) RegisterMiner(minerID string, clockID uint64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.minerClockIDs == nil {
+		v.minerClockIDs = make(map[string]uint64)
+	}
+	v.minerClockIDs[minerID] = clockID
+	v.registerParticipantLocked(SequenceParticipant{ClockID: clockID, Kind: ParticipantMiner, Name: minerID})
+}
+
+// MinerClockIDOf returns the VLC node ID registered for a miner
+func (v *CoreValidator) MinerClockIDOf(minerID string) uint64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.minerClockIDLocked(minerID)
+}
+
+// minerClockIDLocked returns a miner's VLC node ID (caller holds v.mu)
+func (v *CoreValidator) minerClockIDLocked(minerID string) uint64 {
+	if clockID, ok := v.minerClockIDs[minerID]; ok {
+		return clockID
+	}
+	return MinerClockID
+}
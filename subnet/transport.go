@@ -0,0 +1,182 @@
+// Package subnet - Network Transport Abstraction
+//
+// This file defines the Transport interface that carries PoCW subnet messages between
+// participants, plus an in-memory implementation for single-binary subnets. Messages are
+// wrapped in an Envelope holding the JSON wire form, so the same handlers serve both
+// in-process and networked deployments (see http_transport.go).
+package subnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrParticipantUnreachable is returned when no route exists to the target participant
+	ErrParticipantUnreachable = errors.New("participant unreachable")
+	// ErrParticipantRegistered is returned when a participant ID is registered twice
+	ErrParticipantRegistered = errors.New("participant already registered")
+	// ErrUnexpectedMessage is returned when a handler receives a message type it does not serve
+	ErrUnexpectedMessage = errors.New("unexpected message type")
+	// ErrEnvelopeMismatch is returned when an envelope's routing disagrees with the message it carries
+	ErrEnvelopeMismatch = errors.New("envelope does not match message")
+	// ErrMisaddressedMessage is returned when a message addressed to one participant reaches another
+	ErrMisaddressedMessage = errors.New("message addressed to another participant")
+)
+
+// Envelope is the transport-level wrapper around a subnet message.
+// The payload holds the JSON encoding of the concrete message type named by Type.
+type Envelope struct {
+	Type    SubnetMessageType `json:"type"`    // Concrete message type of the payload
+	From    string            `json:"from"`    // Sending participant ID
+	To      string            `json:"to"`      // Receiving participant ID
+	Payload json.RawMessage   `json:"payload"` // JSON-encoded message
+}
+
+// NewEnvelope wraps a subnet message for transport, taking routing from its header
+func NewEnvelope(msg Signable) (*Envelope, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %v", msg.Header().Type, err)
+	}
+
+	header := msg.Header()
+	return &Envelope{
+		Type:    header.Type,
+		From:    header.Sender,
+		To:      header.Receiver,
+		Payload: payload,
+	}, nil
+}
+
+// Decode unmarshals the envelope payload into a concrete message
+func (e *Envelope) Decode(msg interface{}) error {
+	if err := json.Unmarshal(e.Payload, msg); err != nil {
+		return fmt.Errorf("failed to decode %s payload: %v", e.Type, err)
+	}
+	return nil
+}
+
+// DecodeMessage unmarshals the payload into a subnet message and binds the envelope to it:
+// the envelope's type and sender must be those of the message header, which the sender's
+// signature covers, so routing fields cannot be rewritten in transit
+func (e *Envelope) DecodeMessage(msg Signable) error {
+	if err := e.Decode(msg); err != nil {
+		return err
+	}
+	header := msg.Header()
+	if header.Type != e.Type || header.Sender != e.From {
+		return fmt.Errorf("%w: %s envelope from %q carries %s from %q",
+			ErrEnvelopeMismatch, e.Type, e.From, header.Type, header.Sender)
+	}
+	return nil
+}
+
+// checkAddressedTo rejects a message whose signed receiver is not participantID
+func checkAddressedTo(msg Signable, participantID string) error {
+	header := msg.Header()
+	if header.Receiver != participantID {
+		return fmt.Errorf("%w: %s for %q delivered to %s", ErrMisaddressedMessage, header.Type, header.Receiver, participantID)
+	}
+	return nil
+}
+
+// MessageHandler processes an incoming envelope and optionally returns a reply.
+// A nil reply with nil error acknowledges a one-way message.
+type MessageHandler func(ctx context.Context, env *Envelope) (*Envelope, error)
+
+// Transport carries subnet messages between participants.
+//
+// The protocol is request/response: a UI validator sends a user input to the miner and
+// receives the miner response, then sends the response to each validator and receives
+// a vote. Implementations must be safe for concurrent use.
+type Transport interface {
+	// Register makes a local participant reachable under participantID
+	Register(participantID string, handler MessageHandler) error
+
+	// Send delivers an envelope to a participant and waits for its reply
+	Send(ctx context.Context, participantID string, env *Envelope) (*Envelope, error)
+
+	// Close releases any resources (listeners, connections) held by the transport
+	Close() error
+}
+
+// InMemoryTransport delivers messages between participants in the same process.
+// Payloads are copied on every hop so participants never share message memory,
+// which keeps behavior identical to a networked transport.
+type InMemoryTransport struct {
+	mu       sync.RWMutex
+	handlers map[string]MessageHandler // participant ID -> handler
+}
+
+// NewInMemoryTransport creates an empty in-process transport
+func NewInMemoryTransport() *InMemoryTransport {
+	return &InMemoryTransport{
+		handlers: make(map[string]MessageHandler),
+	}
+}
+
+// Register adds a participant handler to the in-memory network
+func (t *InMemoryTransport) Register(participantID string, handler MessageHandler) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.handlers[participantID]; exists {
+		return fmt.Errorf("%w: %s", ErrParticipantRegistered, participantID)
+	}
+	t.handlers[participantID] = handler
+	return nil
+}
+
+// Send invokes the target participant's handler directly
+func (t *InMemoryTransport) Send(ctx context.Context, participantID string, env *Envelope) (*Envelope, error) {
+	t.mu.RLock()
+	handler, exists := t.handlers[participantID]
+	t.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrParticipantUnreachable, participantID)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reply, err := handler(ctx, copyEnvelope(env))
+	if err != nil {
+		return nil, err
+	}
+	return copyEnvelope(reply), nil
+}
+
+// Unregister removes a participant; later sends to it fail with ErrParticipantUnreachable
+func (t *InMemoryTransport) Unregister(participantID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.handlers, participantID)
+}
+
+// Close removes all registered participants
+func (t *InMemoryTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers = make(map[string]MessageHandler)
+	return nil
+}
+
+// copyEnvelope returns a deep copy of an envelope (nil-safe)
+func copyEnvelope(env *Envelope) *Envelope {
+	if env == nil {
+		return nil
+	}
+	payload := make(json.RawMessage, len(env.Payload))
+	copy(payload, env.Payload)
+	return &Envelope{
+		Type:    env.Type,
+		From:    env.From,
+		To:      env.To,
+		Payload: payload,
+	}
+}
@@ -0,0 +1,469 @@
+// Package subnet - Transport Participant Handlers
+//
+// This file binds CoreMiner and CoreValidator to a Transport. A handler decodes the
+// envelope, authenticates the sender, invokes the core component and wraps the reply.
+// The Send* / Request* helpers are the caller side used by the round coordinator.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// NewMinerHandler exposes a CoreMiner on a transport.
+//
+// Served message types:
+//   - UserInputType: merge sender clock, ProcessInputContext, reply with MinerResponseMessage
+//     (ProcessInputStream pushing chunks back to the sender if it asked to stream and the
+//     miner has a stream transport)
+//   - AdditionalInfoType: merge sender clock, ProcessDialogTurnContext (or ProcessAdditionalInfoContext
+//     without History), reply with MinerResponseMessage
+//   - FinalOutputType: merge the round-end clock, no reply
+//
+// Every message must be addressed to the miner. If registry is non-nil, messages that are
+// unsigned or forged are rejected.
+func NewMinerHandler(m *CoreMiner, registry *SignerRegistry) MessageHandler {
+	return func(ctx context.Context, env *Envelope) (*Envelope, error) {
+		switch env.Type {
+		case UserInputType:
+			var msg UserInputMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(registry, &msg); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&msg, m.ID); err != nil {
+				return nil, err
+			}
+			if msg.VLCClock != nil {
+				m.UpdateValidatorClock(msg.VLCClock)
+			}
+			if msg.Stream && m.streamTransport != nil {
+				sink := func(ctx context.Context, chunk *MinerChunkMessage) error {
+					return SendMinerChunk(ctx, m.streamTransport, msg.Sender, chunk)
+				}
+				return NewEnvelope(m.ProcessInputStream(ctx, msg.Input, msg.InputNumber, msg.RequestID, msg.Sender, sink))
+			}
+			return NewEnvelope(m.ProcessInputContext(ctx, msg.Input, msg.InputNumber, msg.RequestID))
+
+		case AdditionalInfoType:
+			var msg AdditionalInfoMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(registry, &msg); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&msg, m.ID); err != nil {
+				return nil, err
+			}
+			if msg.VLCClock != nil {
+				m.UpdateValidatorClock(msg.VLCClock)
+			}
+			if len(msg.History) > 0 {
+				return NewEnvelope(m.ProcessDialogTurnContext(ctx, msg.OriginalInput, msg.History, msg.InputNumber, msg.RequestID))
+			}
+			return NewEnvelope(m.ProcessAdditionalInfoContext(ctx, msg.OriginalInput, msg.AdditionalInfo, msg.InputNumber, msg.RequestID))
+
+		case FinalOutputType:
+			var msg FinalOutputMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(registry, &msg); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&msg, m.ID); err != nil {
+				return nil, err
+			}
+			if msg.VLCClock != nil {
+				m.UpdateValidatorClock(msg.VLCClock)
+			}
+			return nil, nil
+
+		default:
+			return nil, fmt.Errorf("%w: miner %s does not serve %s", ErrUnexpectedMessage, m.ID, env.Type)
+		}
+	}
+}
+
+// NewValidatorHandler exposes a CoreValidator on a transport.
+//
+// Served message types:
+//   - MinerResponseType: authenticate the miner response, check its stream digest (if streamed)
+//     and its clock against the validator's own view of the miner, and reply with a ValidatorVoteMessage
+//   - ClockCheckpointType: authenticate a peer's checkpoint and compare it with the validator's
+//     own view of the miner, recording fork evidence (no reply)
+//   - MinerChunkType: authenticate a streamed chunk and feed it to the validator's assembler (no reply)
+//   - VoteCommitRequestType: authenticate, check the clock, assess and reply with a sealed VoteCommitMessage
+//     (the sender becomes the round leader)
+//   - VoteRevealType: authenticate and, for the round leader with a quorum of commitments, reply
+//     with the ValidatorVoteMessage sealed for that request
+//   - HeartbeatType: authenticate the probe and reply with a signed HeartbeatMessage carrying
+//     the validator's clock
+//   - LeaderHandoverType: authenticate, take over or step down, and reply with a HeartbeatMessage
+//     carrying the clock the validator now leads with
+//
+// Miner responses and checkpoints are relayed, so they are authenticated by their signer; every
+// other message must be addressed to the validator.
+func NewValidatorHandler(v *CoreValidator) MessageHandler {
+	return func(ctx context.Context, env *Envelope) (*Envelope, error) {
+		switch env.Type {
+		case MinerResponseType:
+			var response MinerResponseMessage
+			if err := env.DecodeMessage(&response); err != nil {
+				return nil, err
+			}
+			if err := v.VerifyMinerResponse(&response); err != nil {
+				return nil, err
+			}
+			if err := VerifyStreamDigest(&response); err != nil {
+				return nil, err
+			}
+			if _, err := v.ObserveMinerResponse(&response); err != nil {
+				return nil, err
+			}
+			return NewEnvelope(v.VoteOnOutput(&response))
+
+		case ClockCheckpointType:
+			var msg ClockCheckpointMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			return nil, v.ReceiveClockCheckpoint(&msg)
+
+		case MinerChunkType:
+			var chunk MinerChunkMessage
+			if err := env.DecodeMessage(&chunk); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(v.signerRegistry, &chunk); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&chunk, v.ID); err != nil {
+				return nil, err
+			}
+			return nil, v.ReceiveChunk(&chunk)
+
+		case VoteCommitRequestType:
+			var msg VoteCommitRequestMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(v.signerRegistry, &msg); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&msg, v.ID); err != nil {
+				return nil, err
+			}
+			if msg.Response == nil {
+				return nil, fmt.Errorf("%w: commit request for %s carries no response", ErrUnexpectedMessage, msg.RequestID)
+			}
+			if err := v.VerifyMinerResponse(msg.Response); err != nil {
+				return nil, err
+			}
+			if _, err := v.ObserveMinerResponse(msg.Response); err != nil {
+				return nil, err
+			}
+			commit, err := v.CommitVote(msg.Response, msg.Sender)
+			if err != nil {
+				return nil, err
+			}
+			return NewEnvelope(commit)
+
+		case VoteRevealType:
+			var msg VoteRevealMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(v.signerRegistry, &msg); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&msg, v.ID); err != nil {
+				return nil, err
+			}
+			vote, err := v.RevealVote(&msg)
+			if err != nil {
+				return nil, err
+			}
+			return NewEnvelope(vote)
+
+		case HeartbeatType:
+			var msg HeartbeatMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(v.signerRegistry, &msg); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&msg, v.ID); err != nil {
+				return nil, err
+			}
+			return NewEnvelope(v.NewHeartbeatMessage(msg.Sender, msg.Epoch))
+
+		case LeaderHandoverType:
+			var msg LeaderHandoverMessage
+			if err := env.DecodeMessage(&msg); err != nil {
+				return nil, err
+			}
+			if err := verifyIfConfigured(v.signerRegistry, &msg); err != nil {
+				return nil, err
+			}
+			if err := checkAddressedTo(&msg, v.ID); err != nil {
+				return nil, err
+			}
+			if err := v.ApplyLeaderHandover(&msg); err != nil {
+				return nil, err
+			}
+			return NewEnvelope(v.NewHeartbeatMessage(msg.Sender, msg.Epoch))
+
+		default:
+			return nil, fmt.Errorf("%w: validator %s does not serve %s", ErrUnexpectedMessage, v.ID, env.Type)
+		}
+	}
+}
+
+// RoundRunner runs a round delegated to this process's leader and returns its record for
+// the epoch, or nil if the round was dropped
+type RoundRunner func(ctx context.Context, request *RoundRequestMessage) *RoundData
+
+// NewLeaderHandler exposes a CoreValidator that can lead rounds for a remote gateway.
+// It serves RoundRequestType - authenticated, addressed to the validator, sent by a registered
+// validator and only while the validator holds the leader role - by running the round with
+// run and replying with a signed RoundResultMessage. Rounds run one at a time; every other
+// message type is served by NewValidatorHandler.
+func NewLeaderHandler(v *CoreValidator, run RoundRunner) MessageHandler {
+	validatorHandler := NewValidatorHandler(v)
+	var roundMu sync.Mutex
+
+	return func(ctx context.Context, env *Envelope) (*Envelope, error) {
+		if env.Type != RoundRequestType {
+			return validatorHandler(ctx, env)
+		}
+
+		var msg RoundRequestMessage
+		if err := env.DecodeMessage(&msg); err != nil {
+			return nil, err
+		}
+		if err := verifyIfConfigured(v.signerRegistry, &msg); err != nil {
+			return nil, err
+		}
+		if err := checkAddressedTo(&msg, v.ID); err != nil {
+			return nil, err
+		}
+		if err := v.checkValidatorSender(msg.Sender); err != nil {
+			return nil, err
+		}
+
+		roundMu.Lock()
+		defer roundMu.Unlock()
+		if !v.IsUserInterface() {
+			return nil, fmt.Errorf("%w: %s does not lead epoch %d", ErrNotRoundLeader, v.ID, msg.Epoch)
+		}
+		return NewEnvelope(v.NewRoundResultMessage(&msg, run(ctx, &msg)))
+	}
+}
+
+// verifyIfConfigured authenticates a message when a registry is available
+func verifyIfConfigured(registry *SignerRegistry, msg Signable) error {
+	if registry == nil {
+		return nil
+	}
+	return registry.Verify(msg)
+}
+
+// SendUserInput forwards a user task to a miner and returns its response
+func SendUserInput(ctx context.Context, t Transport, minerID string, msg *UserInputMessage) (*MinerResponseMessage, error) {
+	return sendForMinerResponse(ctx, t, minerID, msg)
+}
+
+// SendAdditionalInfo forwards a user clarification to a miner and returns its response
+func SendAdditionalInfo(ctx context.Context, t Transport, minerID string, msg *AdditionalInfoMessage) (*MinerResponseMessage, error) {
+	return sendForMinerResponse(ctx, t, minerID, msg)
+}
+
+// SendMinerChunk delivers a streamed output chunk to the user-facing validator (one-way)
+func SendMinerChunk(ctx context.Context, t Transport, validatorID string, chunk *MinerChunkMessage) error {
+	env, err := NewEnvelope(chunk)
+	if err != nil {
+		return err
+	}
+	_, err = t.Send(ctx, validatorID, env)
+	return err
+}
+
+// SendClockCheckpoint gossips a checkpoint to a peer validator (one-way)
+func SendClockCheckpoint(ctx context.Context, t Transport, validatorID string, msg *ClockCheckpointMessage) error {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	_, err = t.Send(ctx, validatorID, env)
+	return err
+}
+
+// SendFinalOutput delivers a round result to a participant (one-way)
+func SendFinalOutput(ctx context.Context, t Transport, participantID string, msg *FinalOutputMessage) error {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	_, err = t.Send(ctx, participantID, env)
+	return err
+}
+
+// RequestValidatorVote sends a miner response to a validator and returns its vote
+func RequestValidatorVote(ctx context.Context, t Transport, validatorID string, response *MinerResponseMessage) (*ValidatorVoteMessage, error) {
+	env, err := NewEnvelope(response)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.Send(ctx, validatorID, env)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil || reply.Type != ValidatorVoteType {
+		return nil, fmt.Errorf("%w: expected %s from %s", ErrUnexpectedMessage, ValidatorVoteType, validatorID)
+	}
+
+	var vote ValidatorVoteMessage
+	if err := reply.DecodeMessage(&vote); err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// RequestVoteCommit asks a validator to assess a miner response and returns its sealed vote
+func RequestVoteCommit(ctx context.Context, t Transport, validatorID string, msg *VoteCommitRequestMessage) (*VoteCommitMessage, error) {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.Send(ctx, validatorID, env)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil || reply.Type != VoteCommitType {
+		return nil, fmt.Errorf("%w: expected %s from %s", ErrUnexpectedMessage, VoteCommitType, validatorID)
+	}
+
+	var commit VoteCommitMessage
+	if err := reply.DecodeMessage(&commit); err != nil {
+		return nil, err
+	}
+	return &commit, nil
+}
+
+// RequestVoteReveal asks a validator to open its sealed vote
+func RequestVoteReveal(ctx context.Context, t Transport, validatorID string, msg *VoteRevealMessage) (*ValidatorVoteMessage, error) {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.Send(ctx, validatorID, env)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil || reply.Type != ValidatorVoteType {
+		return nil, fmt.Errorf("%w: expected %s from %s", ErrUnexpectedMessage, ValidatorVoteType, validatorID)
+	}
+
+	var vote ValidatorVoteMessage
+	if err := reply.DecodeMessage(&vote); err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// sendForMinerResponse sends a message to a miner and decodes the MinerResponseMessage reply
+func sendForMinerResponse(ctx context.Context, t Transport, minerID string, msg Signable) (*MinerResponseMessage, error) {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.Send(ctx, minerID, env)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil || reply.Type != MinerResponseType {
+		return nil, fmt.Errorf("%w: expected %s from %s", ErrUnexpectedMessage, MinerResponseType, minerID)
+	}
+
+	var response MinerResponseMessage
+	if err := reply.DecodeMessage(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SendHeartbeat probes a validator and returns its heartbeat reply
+func SendHeartbeat(ctx context.Context, t Transport, validatorID string, msg *HeartbeatMessage) (*HeartbeatMessage, error) {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.Send(ctx, validatorID, env)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil || reply.Type != HeartbeatType {
+		return nil, fmt.Errorf("%w: expected %s from %s", ErrUnexpectedMessage, HeartbeatType, validatorID)
+	}
+
+	var heartbeat HeartbeatMessage
+	if err := reply.DecodeMessage(&heartbeat); err != nil {
+		return nil, err
+	}
+	return &heartbeat, nil
+}
+
+// SendLeaderHandover tells a validator who leads an epoch and returns its heartbeat reply
+func SendLeaderHandover(ctx context.Context, t Transport, validatorID string, msg *LeaderHandoverMessage) (*HeartbeatMessage, error) {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.Send(ctx, validatorID, env)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil || reply.Type != HeartbeatType {
+		return nil, fmt.Errorf("%w: expected %s from %s", ErrUnexpectedMessage, HeartbeatType, validatorID)
+	}
+
+	var heartbeat HeartbeatMessage
+	if err := reply.DecodeMessage(&heartbeat); err != nil {
+		return nil, err
+	}
+	return &heartbeat, nil
+}
+
+// RequestRound asks the epoch's leader to run a round and returns its result
+func RequestRound(ctx context.Context, t Transport, validatorID string, msg *RoundRequestMessage) (*RoundResultMessage, error) {
+	env, err := NewEnvelope(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := t.Send(ctx, validatorID, env)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil || reply.Type != RoundResultType {
+		return nil, fmt.Errorf("%w: expected %s from %s", ErrUnexpectedMessage, RoundResultType, validatorID)
+	}
+
+	var result RoundResultMessage
+	if err := reply.DecodeMessage(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
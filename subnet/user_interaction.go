@@ -0,0 +1,84 @@
+// Package subnet - User Interaction
+//
+// This file defines what the round leader asks the end user and how it waits for the
+// answer. Two prompts reach the user:
+//   - Clarification: a miner's question; the user's answer is fed to the miner's next turn
+//   - Review: the miner's output; the user accepts or rejects it with feedback
+//
+// Handlers implementing InteractiveUserHandler (e.g., HTTPUserHandler) reach a real user and
+// may time out; plain UserInteractionHandlers are simulations that answer at once.
+package subnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoUserResponse is returned when the user did not answer a prompt in time
+var ErrNoUserResponse = errors.New("no user response")
+
+// UserPromptKind says what a prompt asks of the user
+type UserPromptKind string
+
+const (
+	PromptClarification UserPromptKind = "clarification" // Answer the miner's question
+	PromptReview        UserPromptKind = "review"        // Accept or reject the output
+)
+
+// UserPrompt is one question put to the end user
+type UserPrompt struct {
+	ID          string         `json:"id"` // Unique per request, kind and turn; set by the handler if empty
+	Kind        UserPromptKind `json:"kind"`
+	RequestID   string         `json:"request_id"`
+	InputNumber int            `json:"input_number"`
+	Task        string         `json:"task"`               // The user's task, with clarifications so far
+	Question    string         `json:"question,omitempty"` // Miner's question (clarification)
+	Turn        int            `json:"turn,omitempty"`     // Clarification turn, from 1
+	Output      string         `json:"output,omitempty"`   // Miner output (review)
+}
+
+// UserReply is the user's answer to a prompt
+type UserReply struct {
+	PromptID string `json:"prompt_id"`
+	Accept   *bool  `json:"accept,omitempty"`   // Required for reviews
+	Feedback string `json:"feedback,omitempty"` // Optional review comment
+	Answer   string `json:"answer,omitempty"`   // Required for clarifications
+}
+
+// promptID derives a prompt's ID from its request, kind and turn
+func promptID(prompt *UserPrompt) string {
+	if prompt.Kind == PromptClarification {
+		return fmt.Sprintf("%s/%s/%d", prompt.RequestID, prompt.Kind, prompt.Turn)
+	}
+	return fmt.Sprintf("%s/%s", prompt.RequestID, prompt.Kind)
+}
+
+// ReviewWithUser asks the user to accept or reject a miner output. Only the user interface
+// validator talks to the user. Handlers that only simulate users answer through
+// SimulateUserInteraction.
+func (v *CoreValidator) ReviewWithUser(ctx context.Context, prompt *UserPrompt) (bool, string, error) {
+	prompt.Kind = PromptReview
+	if !v.IsUserInterface() {
+		return false, "", fmt.Errorf("%w: %s is not the user interface validator", ErrNoUserResponse, v.ID)
+	}
+	if interactive, ok := v.userInteractionHandler.(InteractiveUserHandler); ok {
+		return interactive.ReviewOutput(ctx, prompt)
+	}
+	accept, feedback := v.SimulateUserInteraction(prompt.InputNumber, prompt.Output)
+	return accept, feedback, nil
+}
+
+// AskUser relays a miner's clarification question to the user and returns the answer.
+// Only the user interface validator talks to the user.
+func (v *CoreValidator) AskUser(ctx context.Context, prompt *UserPrompt) (string, error) {
+	prompt.Kind = PromptClarification
+	if !v.IsUserInterface() {
+		return "", fmt.Errorf("%w: %s is not the user interface validator", ErrNoUserResponse, v.ID)
+	}
+	interactive, ok := v.userInteractionHandler.(InteractiveUserHandler)
+	if !ok {
+		return "", fmt.Errorf("%w: %s has no handler that can ask the user", ErrNoUserResponse, v.ID)
+	}
+	return interactive.RequestClarification(ctx, prompt)
+}
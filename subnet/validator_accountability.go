@@ -0,0 +1,440 @@
+// Package subnet - Validator Accountability
+//
+// This file tracks how each validator votes relative to the final consensus and
+// reduces the effective voting weight of validators that equivocate, disagree with
+// consensus too often, or consistently report outlier quality scores. Every weight
+// change is recorded in an audit log.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrEquivocation is returned when a validator casts two different votes for one request
+	ErrEquivocation = errors.New("validator equivocation")
+	// ErrVoterMismatch is returned when a vote's sender is not the validator it votes as
+	ErrVoterMismatch = errors.New("vote sender is not the voting validator")
+)
+
+// maxTrackedRequests bounds the per-request vote history used for equivocation detection
+const maxTrackedRequests = 1024
+
+// AccountabilityConfig defines when validators are penalized; zero values use the defaults
+type AccountabilityConfig struct {
+	ReviewWindow      int     // Decided rounds per review (default 5)
+	MinAgreementRate  float64 // Agreement with consensus below this in a window is slashed (default 0.6)
+	OutlierDistance   float64 // |quality - consensus quality| above this is an outlier (default 0.3)
+	MaxOutlierRate    float64 // Outlier share above this in a window is slashed (default 0.5)
+	SlashFactor       float64 // Weight multiplier for agreement/outlier penalties (default 0.5)
+	EquivocationSlash float64 // Weight multiplier on equivocation (default 0: weight removed)
+	AuditLogPath      string  // Optional JSONL file that receives every weight change
+}
+
+// withDefaults fills zero values with the default accountability rules
+func (c AccountabilityConfig) withDefaults() AccountabilityConfig {
+	if c.ReviewWindow <= 0 {
+		c.ReviewWindow = 5
+	}
+	if c.MinAgreementRate <= 0 {
+		c.MinAgreementRate = 0.6
+	}
+	if c.OutlierDistance <= 0 {
+		c.OutlierDistance = 0.3
+	}
+	if c.MaxOutlierRate <= 0 {
+		c.MaxOutlierRate = 0.5
+	}
+	if c.SlashFactor <= 0 || c.SlashFactor >= 1 {
+		c.SlashFactor = 0.5
+	}
+	if c.EquivocationSlash < 0 || c.EquivocationSlash >= 1 {
+		c.EquivocationSlash = 0
+	}
+	return c
+}
+
+// ValidatorRecord is the accountability history of one validator
+type ValidatorRecord struct {
+	ValidatorID     string   `json:"validator_id"`
+	BaseWeight      float64  `json:"base_weight"`      // Weight at registration
+	EffectiveWeight float64  `json:"effective_weight"` // Current weight after penalties
+	Rounds          int      `json:"rounds"`           // Decided rounds voted in
+	Agreements      int      `json:"agreements"`       // Votes matching the consensus decision
+	Outliers        int      `json:"outliers"`         // Quality scores far from consensus quality
+	Abstentions     int      `json:"abstentions"`      // Rounds abstained
+	Equivocations   int      `json:"equivocations"`    // Conflicting votes detected
+	Flags           []string `json:"flags,omitempty"`  // Reasons this validator was penalized
+
+	// Current review window
+	windowRounds     int
+	windowAgreements int
+	windowOutliers   int
+}
+
+// AgreementRate returns the share of decided rounds in which the validator agreed with consensus
+func (r ValidatorRecord) AgreementRate() float64 {
+	if r.Rounds == 0 {
+		return 1
+	}
+	return float64(r.Agreements) / float64(r.Rounds)
+}
+
+// OutlierRate returns the share of decided rounds in which the validator's quality was an outlier
+func (r ValidatorRecord) OutlierRate() float64 {
+	if r.Rounds == 0 {
+		return 0
+	}
+	return float64(r.Outliers) / float64(r.Rounds)
+}
+
+// WeightChange is one audit log entry
+type WeightChange struct {
+	ValidatorID string    `json:"validator_id"`
+	OldWeight   float64   `json:"old_weight"`
+	NewWeight   float64   `json:"new_weight"`
+	Reason      string    `json:"reason"`
+	RequestID   string    `json:"request_id,omitempty"` // Round that triggered the change
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// ValidatorAccountability records validator behavior and adjusts effective weights.
+//
+// Usage per round:
+//  1. ObserveVote for every authenticated vote (detects equivocation)
+//  2. EffectiveWeight to weigh the vote in the QualityAssessment
+//  3. RecordOutcome once consensus is decided (agreement and outlier tracking)
+type ValidatorAccountability struct {
+	mu       sync.Mutex
+	config   AccountabilityConfig
+	records  map[string]*ValidatorRecord
+	votes    map[string]map[string]*ValidatorVoteMessage // requestID -> validatorID -> first vote
+	order    []string                                    // requestIDs in arrival order, for pruning
+	auditLog []WeightChange
+
+	// OnWeightChange, if set, is called after every weight change (e.g., to update CoreValidator.Weight)
+	OnWeightChange func(change WeightChange)
+}
+
+// NewValidatorAccountability creates an accountability tracker
+func NewValidatorAccountability(config AccountabilityConfig) *ValidatorAccountability {
+	return &ValidatorAccountability{
+		config:  config.withDefaults(),
+		records: make(map[string]*ValidatorRecord),
+		votes:   make(map[string]map[string]*ValidatorVoteMessage),
+	}
+}
+
+// RegisterValidator adds a validator with its base voting weight
+func (va *ValidatorAccountability) RegisterValidator(validatorID string, weight float64) {
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	if _, exists := va.records[validatorID]; exists {
+		return
+	}
+	va.records[validatorID] = &ValidatorRecord{
+		ValidatorID:     validatorID,
+		BaseWeight:      weight,
+		EffectiveWeight: weight,
+	}
+}
+
+// EffectiveWeight returns a validator's current weight (0 for unknown validators)
+func (va *ValidatorAccountability) EffectiveWeight(validatorID string) float64 {
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	if record, exists := va.records[validatorID]; exists {
+		return record.EffectiveWeight
+	}
+	return 0
+}
+
+// TotalWeight returns the sum of all effective weights (the registered weight for quorums)
+func (va *ValidatorAccountability) TotalWeight() float64 {
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	var total float64
+	for _, record := range va.records {
+		total += record.EffectiveWeight
+	}
+	return total
+}
+
+// Weights returns a snapshot of every validator's effective weight, so a round can be
+// decided and certified with the same weights even if penalties land mid-round
+func (va *ValidatorAccountability) Weights() map[string]float64 {
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	weights := make(map[string]float64, len(va.records))
+	for id, record := range va.records {
+		weights[id] = record.EffectiveWeight
+	}
+	return weights
+}
+
+// ObserveVote records a vote and detects equivocation.
+// Returns ErrEquivocation (and slashes the validator) if the validator already cast a
+// different vote for the same request; an identical re-delivery is accepted.
+// Returns ErrVoterMismatch, without penalizing anyone, if the vote's sender is not the
+// validator it votes as.
+func (va *ValidatorAccountability) ObserveVote(vote *ValidatorVoteMessage) error {
+	if err := checkVoter(vote); err != nil {
+		return err
+	}
+
+	var change *WeightChange
+	defer func() {
+		if change != nil {
+			va.notify(*change)
+		}
+	}()
+
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	requestVotes, exists := va.votes[vote.RequestID]
+	if !exists {
+		requestVotes = make(map[string]*ValidatorVoteMessage)
+		va.votes[vote.RequestID] = requestVotes
+		va.order = append(va.order, vote.RequestID)
+		va.pruneLocked()
+	}
+
+	previous, voted := requestVotes[vote.ValidatorID]
+	if !voted {
+		requestVotes[vote.ValidatorID] = vote
+		return nil
+	}
+	if sameVote(previous, vote) {
+		return nil
+	}
+
+	record := va.records[vote.ValidatorID]
+	if record == nil {
+		return fmt.Errorf("%w: %s (unregistered) on %s", ErrEquivocation, vote.ValidatorID, vote.RequestID)
+	}
+	record.Equivocations++
+	change = va.slashLocked(record, va.config.EquivocationSlash, "equivocation", vote.RequestID)
+
+	return fmt.Errorf("%w: %s voted accept=%v quality=%.2f then accept=%v quality=%.2f on %s",
+		ErrEquivocation, vote.ValidatorID, previous.Accept, previous.Quality, vote.Accept, vote.Quality, vote.RequestID)
+}
+
+// checkVoter rejects a vote whose sender (the signer) is not the validator it votes as,
+// so no validator can spend another's weight or have it penalized
+func checkVoter(vote *ValidatorVoteMessage) error {
+	if vote.Sender != vote.ValidatorID {
+		return fmt.Errorf("%w: %s sent a vote as %s on %s", ErrVoterMismatch, vote.Sender, vote.ValidatorID, vote.RequestID)
+	}
+	return nil
+}
+
+// Penalize slashes a validator for a protocol violation outside normal voting,
+// such as a commit-reveal mismatch or a reveal that never arrived
+func (va *ValidatorAccountability) Penalize(validatorID, requestID, reason string) {
+	var change *WeightChange
+	defer func() {
+		if change != nil {
+			va.notify(*change)
+		}
+	}()
+
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	if record := va.records[validatorID]; record != nil {
+		change = va.slashLocked(record, va.config.SlashFactor, reason, requestID)
+	}
+}
+
+// sameVote reports whether two votes carry the same decision
+func sameVote(a, b *ValidatorVoteMessage) bool {
+	return a.Accept == b.Accept && a.Abstain == b.Abstain && math.Abs(a.Quality-b.Quality) < 1e-9
+}
+
+// pruneLocked drops the oldest request histories beyond maxTrackedRequests
+func (va *ValidatorAccountability) pruneLocked() {
+	for len(va.order) > maxTrackedRequests {
+		delete(va.votes, va.order[0])
+		va.order = va.order[1:]
+	}
+}
+
+// RecordOutcome compares every observed vote for a request with the consensus result
+// and reviews validators whose window is complete. Rounds that did not reach a quorum
+// are not scored.
+func (va *ValidatorAccountability) RecordOutcome(requestID string, outcome ConsensusOutcome, consensusQuality float64) {
+	var changes []WeightChange
+	defer func() {
+		for _, change := range changes {
+			va.notify(change)
+		}
+	}()
+
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	if outcome != OutcomeAccepted && outcome != OutcomeRejected {
+		return
+	}
+	accepted := outcome == OutcomeAccepted
+
+	for validatorID, vote := range va.votes[requestID] {
+		record := va.records[validatorID]
+		if record == nil {
+			continue
+		}
+		if vote.Abstain {
+			record.Abstentions++
+			continue
+		}
+
+		record.Rounds++
+		record.windowRounds++
+		if vote.Accept == accepted {
+			record.Agreements++
+			record.windowAgreements++
+		}
+		if math.Abs(vote.Quality-consensusQuality) > va.config.OutlierDistance {
+			record.Outliers++
+			record.windowOutliers++
+		}
+
+		if record.windowRounds >= va.config.ReviewWindow {
+			if change := va.reviewLocked(record, requestID); change != nil {
+				changes = append(changes, *change)
+			}
+		}
+	}
+}
+
+// reviewLocked evaluates a completed review window and resets it
+func (va *ValidatorAccountability) reviewLocked(record *ValidatorRecord, requestID string) *WeightChange {
+	rounds := float64(record.windowRounds)
+	agreement := float64(record.windowAgreements) / rounds
+	outliers := float64(record.windowOutliers) / rounds
+	record.windowRounds, record.windowAgreements, record.windowOutliers = 0, 0, 0
+
+	switch {
+	case agreement < va.config.MinAgreementRate:
+		return va.slashLocked(record, va.config.SlashFactor,
+			fmt.Sprintf("low agreement (%.0f%% over %d rounds)", agreement*100, int(rounds)), requestID)
+	case outliers > va.config.MaxOutlierRate:
+		return va.slashLocked(record, va.config.SlashFactor,
+			fmt.Sprintf("outlier quality scores (%.0f%% over %d rounds)", outliers*100, int(rounds)), requestID)
+	}
+	return nil
+}
+
+// slashLocked multiplies a validator's effective weight and appends an audit entry
+func (va *ValidatorAccountability) slashLocked(record *ValidatorRecord, factor float64, reason, requestID string) *WeightChange {
+	change := WeightChange{
+		ValidatorID: record.ValidatorID,
+		OldWeight:   record.EffectiveWeight,
+		NewWeight:   record.EffectiveWeight * factor,
+		Reason:      reason,
+		RequestID:   requestID,
+		Timestamp:   time.Now(),
+	}
+	record.EffectiveWeight = change.NewWeight
+	record.Flags = append(record.Flags, reason)
+	va.auditLog = append(va.auditLog, change)
+	va.appendAuditFileLocked(change)
+
+	fmt.Printf("⚖️  Validator %s slashed: weight %.3f → %.3f (%s)\n",
+		change.ValidatorID, change.OldWeight, change.NewWeight, reason)
+	return &change
+}
+
+// appendAuditFileLocked writes a weight change to the JSONL audit file, if configured
+func (va *ValidatorAccountability) appendAuditFileLocked(change WeightChange) {
+	if va.config.AuditLogPath == "" {
+		return
+	}
+	file, err := os.OpenFile(va.config.AuditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to open accountability audit log: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	line, _ := json.Marshal(change)
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		fmt.Printf("⚠️  Failed to write accountability audit log: %v\n", err)
+	}
+}
+
+// notify invokes the weight change callback outside the lock
+func (va *ValidatorAccountability) notify(change WeightChange) {
+	if va.OnWeightChange != nil {
+		va.OnWeightChange(change)
+	}
+}
+
+// Record returns a copy of a validator's accountability record
+func (va *ValidatorAccountability) Record(validatorID string) (ValidatorRecord, bool) {
+	va.mu.Lock()
+	defer va.mu.Unlock()
+
+	record, exists := va.records[validatorID]
+	if !exists {
+		return ValidatorRecord{}, false
+	}
+	snapshot := *record
+	snapshot.Flags = append([]string(nil), record.Flags...)
+	return snapshot, true
+}
+
+// Records returns copies of all validator records sorted by validator ID
+func (va *ValidatorAccountability) Records() []ValidatorRecord {
+	va.mu.Lock()
+	ids := make([]string, 0, len(va.records))
+	for id := range va.records {
+		ids = append(ids, id)
+	}
+	va.mu.Unlock()
+
+	sort.Strings(ids)
+	records := make([]ValidatorRecord, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := va.Record(id); ok {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// AuditLog returns a copy of every weight change made so far
+func (va *ValidatorAccountability) AuditLog() []WeightChange {
+	va.mu.Lock()
+	defer va.mu.Unlock()
+	return append([]WeightChange(nil), va.auditLog...)
+}
+
+// PrintSummary prints per-validator agreement, outlier and weight statistics
+func (va *ValidatorAccountability) PrintSummary() {
+	fmt.Printf("\nValidator accountability:\n")
+	for _, record := range va.Records() {
+		status := "✅"
+		if len(record.Flags) > 0 {
+			status = "⚠️ "
+		}
+		fmt.Printf("  %s %s: agreement %.0f%%, outliers %.0f%%, equivocations %d, weight %.3f/%.3f\n",
+			status, record.ValidatorID, record.AgreementRate()*100, record.OutlierRate()*100,
+			record.Equivocations, record.EffectiveWeight, record.BaseWeight)
+	}
+	if log := va.AuditLog(); len(log) > 0 {
+		fmt.Printf("  Weight changes: %d (see audit log)\n", len(log))
+	}
+}
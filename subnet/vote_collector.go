@@ -0,0 +1,154 @@
+// Package subnet - Asynchronous Vote Collection
+//
+// This file gathers validator votes concurrently under a per-request deadline so a slow
+// or crashed validator cannot stall the subnet.
+//
+// Finalization:
+//   - Early: as soon as the assessment is decided (quorum reached, or provably unreachable)
+//   - Deadline: with whatever votes arrived; an undecided assessment becomes
+//     OutcomeQuorumNotReached and callers should refund the task
+//
+// Outstanding requests are cancelled when collection finalizes.
+package subnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultVoteDeadline is how long a round waits for validator votes
+const DefaultVoteDeadline = 10 * time.Second
+
+// VoteRequester asks one validator for its vote on the current request.
+// Implementations must return promptly once ctx is done.
+type VoteRequester func(ctx context.Context, validatorID string) (*ValidatorVoteMessage, error)
+
+// VoteAdmitter decides whether a received vote counts and with what weight
+// (e.g., signature checks, equivocation detection, accountability weights).
+// A non-nil error discards the vote.
+type VoteAdmitter func(vote *ValidatorVoteMessage) (weight float64, err error)
+
+// VoteCollection is the result of one round of vote collection
+type VoteCollection struct {
+	Votes    []*ValidatorVoteMessage // Admitted votes, in arrival order
+	Failed   map[string]error        // Validators whose request failed or whose vote was discarded
+	Missing  []string                // Validators that had not answered when collection finalized
+	Outcome  ConsensusOutcome        // Outcome of the (closed) assessment
+	TimedOut bool                    // Whether the deadline passed before the assessment was decided
+	Elapsed  time.Duration           // Time from the first request to finalization
+}
+
+// Undecided reports whether the round ended without accept or reject consensus
+func (c *VoteCollection) Undecided() bool {
+	return c.Outcome != OutcomeAccepted && c.Outcome != OutcomeRejected
+}
+
+// VoteCollector requests votes from all validators in parallel and feeds them into a
+// QualityAssessment until it is decided or the deadline passes
+type VoteCollector struct {
+	Deadline time.Duration // Per-request deadline (zero: DefaultVoteDeadline)
+	Request  VoteRequester // How to obtain a vote from one validator
+	Admit    VoteAdmitter  // Vote filter and weighting (nil: use the vote's own weight)
+}
+
+// NewVoteCollector creates a vote collector with the given deadline and requester
+func NewVoteCollector(deadline time.Duration, request VoteRequester) *VoteCollector {
+	return &VoteCollector{
+		Deadline: deadline,
+		Request:  request,
+	}
+}
+
+// voteResult carries one validator's answer back to the collecting goroutine
+type voteResult struct {
+	validatorID string
+	vote        *ValidatorVoteMessage
+	err         error
+}
+
+// Collect requests votes from validatorIDs concurrently and adds admitted votes to
+// assessment. Returns once the assessment is decided, every validator has answered,
+// or the deadline passes - whichever comes first. The assessment is always closed.
+func (c *VoteCollector) Collect(ctx context.Context, assessment *QualityAssessment, validatorIDs []string) *VoteCollection {
+	deadline := c.Deadline
+	if deadline <= 0 {
+		deadline = DefaultVoteDeadline
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	start := time.Now()
+	results := make(chan voteResult, len(validatorIDs)) // Buffered: late answers never block
+	for _, validatorID := range validatorIDs {
+		go func() {
+			vote, err := c.Request(ctx, validatorID)
+			results <- voteResult{validatorID: validatorID, vote: vote, err: err}
+		}()
+	}
+
+	collection := &VoteCollection{
+		Failed: make(map[string]error),
+	}
+	answered := make(map[string]bool, len(validatorIDs))
+
+collect:
+	for len(answered) < len(validatorIDs) {
+		select {
+		case result := <-results:
+			answered[result.validatorID] = true
+			if err := c.admit(assessment, result); err != nil {
+				collection.Failed[result.validatorID] = err
+				continue
+			}
+			collection.Votes = append(collection.Votes, result.vote)
+
+			if assessment.Outcome() != OutcomePending {
+				break collect // Decided; remaining votes cannot change the outcome
+			}
+		case <-ctx.Done():
+			collection.TimedOut = assessment.Outcome() == OutcomePending
+			break collect
+		}
+	}
+
+	for _, validatorID := range validatorIDs {
+		if !answered[validatorID] {
+			collection.Missing = append(collection.Missing, validatorID)
+		}
+	}
+
+	assessment.Close()
+	collection.Outcome = assessment.Outcome()
+	collection.Elapsed = time.Since(start)
+	return collection
+}
+
+// admit validates one answer and adds it to the assessment
+func (c *VoteCollector) admit(assessment *QualityAssessment, result voteResult) error {
+	if result.err != nil {
+		return result.err
+	}
+	if result.vote == nil {
+		return fmt.Errorf("validator %s returned no vote", result.validatorID)
+	}
+	if result.vote.ValidatorID != result.validatorID {
+		return fmt.Errorf("vote from %s claims to be from %s", result.validatorID, result.vote.ValidatorID)
+	}
+
+	weight := result.vote.Weight
+	if c.Admit != nil {
+		var err error
+		if weight, err = c.Admit(result.vote); err != nil {
+			return err
+		}
+	}
+
+	if result.vote.Abstain {
+		assessment.AddAbstention(weight)
+	} else {
+		assessment.AddQualityVote(weight, result.vote.Accept, result.vote.Quality)
+	}
+	return nil
+}
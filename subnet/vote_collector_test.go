@@ -0,0 +1,125 @@
+package subnet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testValidatorIDs = []string{"validator-1", "validator-2", "validator-3", "validator-4"}
+
+// stubVoters answers for each validator: an accept vote, or nothing until ctx is done if slow
+type stubVoters struct {
+	slow map[string]bool
+
+	mu        sync.Mutex
+	cancelled []string // Slow validators whose request was cancelled
+}
+
+func (s *stubVoters) request(ctx context.Context, validatorID string) (*ValidatorVoteMessage, error) {
+	if s.slow[validatorID] {
+		<-ctx.Done()
+		s.mu.Lock()
+		s.cancelled = append(s.cancelled, validatorID)
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	return &ValidatorVoteMessage{
+		SubnetMessage: SubnetMessage{RequestID: "req-1", Sender: validatorID},
+		ValidatorID:   validatorID,
+		Weight:        0.25,
+		Quality:       0.9,
+		Accept:        true,
+	}, nil
+}
+
+func TestVoteCollectorFinalizesOnceQuorumIsReached(t *testing.T) {
+	voters := &stubVoters{slow: map[string]bool{"validator-4": true}}
+	collector := NewVoteCollector(10*time.Second, voters.request)
+	assessment := NewQualityAssessment("req-1", DefaultConsensusConfig(1.0))
+
+	collection := collector.Collect(context.Background(), assessment, testValidatorIDs)
+
+	if collection.Outcome != OutcomeAccepted || collection.TimedOut || collection.Undecided() {
+		t.Fatalf("collection = %+v, want accepted before the deadline", collection)
+	}
+	if collection.Elapsed > 5*time.Second {
+		t.Fatalf("collection took %v, should finalize as soon as 3/4 accepted", collection.Elapsed)
+	}
+	if len(collection.Votes) != 3 || len(collection.Missing) != 1 || collection.Missing[0] != "validator-4" {
+		t.Fatalf("votes %d, missing %v; want 3 votes and validator-4 missing", len(collection.Votes), collection.Missing)
+	}
+
+	// The outstanding request is cancelled once collection finalizes
+	waitFor(t, func() bool {
+		voters.mu.Lock()
+		defer voters.mu.Unlock()
+		return len(voters.cancelled) == 1
+	})
+}
+
+func TestVoteCollectorDeadline(t *testing.T) {
+	voters := &stubVoters{slow: map[string]bool{"validator-3": true, "validator-4": true}}
+	collector := NewVoteCollector(50*time.Millisecond, voters.request)
+	assessment := NewQualityAssessment("req-1", DefaultConsensusConfig(1.0))
+
+	collection := collector.Collect(context.Background(), assessment, testValidatorIDs)
+
+	if collection.Outcome != OutcomeQuorumNotReached || !collection.TimedOut || !collection.Undecided() {
+		t.Fatalf("collection = %+v, want quorum not reached at the deadline", collection)
+	}
+	if collection.Elapsed < 50*time.Millisecond {
+		t.Fatalf("collection finalized after %v, before the deadline", collection.Elapsed)
+	}
+	if len(collection.Votes) != 2 || len(collection.Missing) != 2 {
+		t.Fatalf("votes %d, missing %v; want 2 votes and 2 missing", len(collection.Votes), collection.Missing)
+	}
+	if assessment.Outcome() != OutcomeQuorumNotReached {
+		t.Fatalf("assessment left %s, want it closed", assessment.Outcome())
+	}
+}
+
+func TestVoteCollectorDiscardsInadmissibleVotes(t *testing.T) {
+	voters := &stubVoters{}
+	request := func(ctx context.Context, validatorID string) (*ValidatorVoteMessage, error) {
+		vote, err := voters.request(ctx, validatorID)
+		if validatorID == "validator-2" {
+			vote.ValidatorID = "validator-1" // Impersonation
+		}
+		return vote, err
+	}
+	errForged := errors.New("bad signature")
+	collector := NewVoteCollector(time.Second, request)
+	collector.Admit = func(vote *ValidatorVoteMessage) (float64, error) {
+		if vote.ValidatorID == "validator-3" {
+			return 0, errForged
+		}
+		return vote.Weight, nil
+	}
+	assessment := NewQualityAssessment("req-1", DefaultConsensusConfig(1.0))
+
+	collection := collector.Collect(context.Background(), assessment, testValidatorIDs)
+
+	if collection.Outcome != OutcomeQuorumNotReached || len(collection.Votes) != 2 {
+		t.Fatalf("collection = %+v, want 2 admitted votes and no quorum", collection)
+	}
+	if _, failed := collection.Failed["validator-2"]; !failed {
+		t.Fatalf("impersonating vote admitted: %v", collection.Failed)
+	}
+	if !errors.Is(collection.Failed["validator-3"], errForged) {
+		t.Fatalf("validator-3 failure = %v, want %v", collection.Failed["validator-3"], errForged)
+	}
+}
+
+// waitFor polls cond for up to a second
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatal("condition not reached within a second")
+}
@@ -0,0 +1,157 @@
+// Subnet Node Mode
+//
+// Runs one PoCW subnet participant per OS process so the miner and validators can be
+// deployed on different hosts. Participants exchange signed subnet messages over the
+// HTTP transport. Selected with SUBNET_NODE_ROLE:
+//   - miner:       serves miner-<NODE_ID> on NODE_LISTEN (NODE_ID defaults to 1)
+//   - validator:   serves validator-<NODE_ID> on NODE_LISTEN (NODE_ID 2-4) and runs the rounds
+//     delegated to it while it leads an epoch
+//   - coordinator: runs validator-1 and drives the demo rounds against remote peers,
+//     routing tasks across DEMO_MINER_COUNT miners; rounds of epochs won by a remote
+//     validator are delegated to it
+//
+// A validator that may lead needs the miners and the other validators in its SUBNET_PEERS, and
+// the coordinator must set NODE_LISTEN so remote leaders can collect validator-1's vote.
+//
+// Peers are addressed by participant ID:
+//
+//	SUBNET_PEERS="miner-1=http://10.0.0.5:7001,validator-2=http://10.0.0.6:7002,..."
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/hetu-project/FLUX-Mining-8004-x402/subnet"
+	"github.com/hetu-project/FLUX-Mining-8004-x402/subnet/demo"
+)
+
+// parseSubnetPeers parses "id=url,id=url" into a participant ID -> base URL map
+func parseSubnetPeers(spec string) map[string]string {
+	peers := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		peers[parts[0]] = strings.TrimRight(parts[1], "/")
+	}
+	return peers
+}
+
+// newNodeTransport creates the HTTP transport for this process from NODE_LISTEN and SUBNET_PEERS
+func newNodeTransport(defaultListen string) *subnet.HTTPTransport {
+	listenAddr := os.Getenv("NODE_LISTEN")
+	if listenAddr == "" {
+		listenAddr = defaultListen
+	}
+
+	transport := subnet.NewHTTPTransport(listenAddr)
+	for id, url := range parseSubnetPeers(os.Getenv("SUBNET_PEERS")) {
+		transport.AddPeer(id, url)
+		fmt.Printf("   Peer %s → %s\n", id, url)
+	}
+	return transport
+}
+
+// nodeSubnetID returns the subnet ID from environment or the default registered subnet
+func nodeSubnetID() string {
+	subnetID := os.Getenv("SUBNET_ID")
+	if subnetID == "" {
+		subnetID = "subnet-1"
+	}
+	return subnetID
+}
+
+// RunSubnetNode serves a single miner or validator participant until interrupted
+func RunSubnetNode(role string) {
+	subnetID := nodeSubnetID()
+	signers := demo.NewDemoSignerRegistry()
+
+	var participantID string
+	var handler subnet.MessageHandler
+	var transport *subnet.HTTPTransport
+
+	switch role {
+	case "miner":
+		nodeID := 1
+		if value := os.Getenv("NODE_ID"); value != "" {
+			var err error
+			if nodeID, err = strconv.Atoi(value); err != nil || nodeID < 1 {
+				fmt.Printf("❌ NODE_ID must be 1 or more for miner nodes\n")
+				os.Exit(1)
+			}
+		}
+		fmt.Printf("⛏️  Starting subnet miner node %d (%s)...\n", nodeID, subnetID)
+		defaultListen := ":7001"
+		if nodeID > 1 {
+			defaultListen = fmt.Sprintf(":%d", 7100+nodeID) // Clear of validator ports 7002-7004
+		}
+		transport = newNodeTransport(defaultListen)
+
+		miner := demo.NewDemoMiner(nodeID, subnetID, signers)
+		demo.EnableDemoMinerPayments(miner, nodeID)
+		miner.SetStreamTransport(transport) // Streams reach validator-1 if it is in SUBNET_PEERS
+		participantID, handler = miner.ID, subnet.NewMinerHandler(miner, signers)
+
+	case "validator":
+		nodeID, err := strconv.Atoi(os.Getenv("NODE_ID"))
+		if err != nil || nodeID < 2 || nodeID > demo.DemoValidatorCount {
+			fmt.Printf("❌ NODE_ID must be 2-%d for validator nodes (validator-1 runs as coordinator)\n", demo.DemoValidatorCount)
+			os.Exit(1)
+		}
+		fmt.Printf("🛡️  Starting subnet validator node %d (%s)...\n", nodeID, subnetID)
+		transport = newNodeTransport(fmt.Sprintf(":%d", 7000+nodeID))
+
+		// The validator leads rounds through its own coordinator when it wins an epoch
+		validator := demo.NewDemoValidator(nodeID, subnetID, signers)
+		leader := demo.NewNetworkedDemoCoordinator(subnetID, transport, signers, validator, demo.DemoMinerIDs(), demo.DemoValidatorIDs())
+		participantID, handler = validator.ID, leader.LeaderHandler()
+
+	default:
+		fmt.Printf("❌ Unknown SUBNET_NODE_ROLE %q (expected miner, validator or coordinator)\n", role)
+		os.Exit(1)
+	}
+
+	if err := transport.Register(participantID, handler); err != nil {
+		fmt.Printf("❌ Failed to start %s: %v\n", participantID, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %s ready\n", participantID)
+
+	// Serve until interrupted
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	fmt.Printf("🛑 Shutting down %s...\n", participantID)
+	transport.Close()
+}
+
+// newNetworkedCoordinator creates the demo coordinator for SUBNET_NODE_ROLE=coordinator.
+// Validator-1 runs locally; the miners and validators 2-4 are reached via SUBNET_PEERS.
+func newNetworkedCoordinator(subnetID string) *demo.DemoCoordinator {
+	fmt.Println("🌐 Starting subnet coordinator node (validator-1)...")
+	signers := demo.NewDemoSignerRegistry()
+	transport := newNodeTransport("") // Client-only unless NODE_LISTEN is set
+
+	uiValidator := demo.NewDemoValidator(1, subnetID, signers)
+	if err := transport.Register(uiValidator.ID, subnet.NewValidatorHandler(uiValidator)); err != nil {
+		fmt.Printf("❌ Failed to register %s: %v\n", uiValidator.ID, err)
+		os.Exit(1)
+	}
+
+	return demo.NewNetworkedDemoCoordinator(
+		subnetID,
+		transport,
+		signers,
+		uiValidator,
+		demo.DemoMinerIDs(),
+		demo.DemoValidatorIDs(),
+	)
+}
@@ -0,0 +1,222 @@
+package vlc
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Causal delivery errors
+var (
+	ErrStaleMessage  = errors.New("vlc: message already delivered or superseded")
+	ErrBufferFull    = errors.New("vlc: causal buffer full")
+	ErrGapTooLarge   = errors.New("vlc: message too far ahead of delivered clock")
+	ErrMissingSender = errors.New("vlc: message clock has no entry for its sender")
+)
+
+// Default causal buffer limits
+const (
+	DefaultMaxBuffered = 64
+	DefaultGapTimeout  = 30 * time.Second
+	DefaultMaxGap      = 16
+)
+
+// CausalMessage is a payload stamped with its sender's clock
+type CausalMessage struct {
+	SenderID uint64      // Clock entry owned by the sender
+	Clock    *Clock      // Sender's clock when the message was sent
+	Payload  interface{} // Application message, returned untouched on delivery
+	Received time.Time   // Arrival time, set by the buffer if zero
+}
+
+// CausalBufferConfig configures a CausalBuffer; zero values use the defaults
+type CausalBufferConfig struct {
+	MaxBuffered int               // Maximum messages held at once
+	GapTimeout  time.Duration     // How long a message may wait for its predecessors
+	MaxGap      uint64            // Maximum increments a message may be ahead of the delivered clock
+	Increments  map[uint64]uint64 // Expected per-message increment by sender (default 1; e.g. 2 for a miner)
+}
+
+// CausalBuffer holds messages that arrive before their causal predecessors and
+// releases them in VLC order once the gap is filled.
+//
+// A message from sender s with clock M is deliverable against local clock L when:
+//   - M[s] == L[s] + increment(s)   (next message from s)
+//   - M[k] <= L[k] for every k != s (everything the sender had seen is delivered)
+//
+// A sender with no entry in L is bootstrapped by its first message. The buffer does
+// not own the local clock: callers pass it in and merge delivered messages into it.
+type CausalBuffer struct {
+	mu      sync.Mutex
+	config  CausalBufferConfig
+	pending []*CausalMessage
+}
+
+// NewCausalBuffer creates a causal delivery buffer
+func NewCausalBuffer(config CausalBufferConfig) *CausalBuffer {
+	if config.MaxBuffered <= 0 {
+		config.MaxBuffered = DefaultMaxBuffered
+	}
+	if config.GapTimeout <= 0 {
+		config.GapTimeout = DefaultGapTimeout
+	}
+	if config.MaxGap == 0 {
+		config.MaxGap = DefaultMaxGap
+	}
+	return &CausalBuffer{
+		config: config,
+	}
+}
+
+// increment returns the expected clock step for a sender
+func (b *CausalBuffer) increment(senderID uint64) uint64 {
+	if step, ok := b.config.Increments[senderID]; ok && step > 0 {
+		return step
+	}
+	return 1
+}
+
+// deliverable reports whether msg can be delivered against local
+func (b *CausalBuffer) deliverable(local *Clock, msg *CausalMessage) bool {
+	localSender, known := local.Values[msg.SenderID]
+	if !known {
+		return true // Bootstrap: first message from this sender
+	}
+	if msg.Clock.Values[msg.SenderID] != localSender+b.increment(msg.SenderID) {
+		return false
+	}
+	for id, value := range msg.Clock.Values {
+		if id != msg.SenderID && value > local.Values[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// stale reports whether msg was already delivered (or superseded) according to local
+func stale(local *Clock, msg *CausalMessage) bool {
+	localSender, known := local.Values[msg.SenderID]
+	return known && msg.Clock.Values[msg.SenderID] <= localSender
+}
+
+// Receive offers a message for delivery against the caller's local clock.
+//
+// Returns the messages that are now deliverable, in causal order: the received message
+// if it is next, followed by any buffered messages it unblocked. Returns no messages
+// and nil error if the message was buffered. The caller should merge each delivered
+// message's clock into its local clock.
+func (b *CausalBuffer) Receive(local *Clock, msg *CausalMessage) ([]*CausalMessage, error) {
+	if msg == nil || msg.Clock == nil {
+		return nil, ErrMissingSender
+	}
+	if _, ok := msg.Clock.Values[msg.SenderID]; !ok {
+		return nil, ErrMissingSender
+	}
+	if msg.Received.IsZero() {
+		msg.Received = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	working := local.Copy()
+	if stale(working, msg) {
+		return nil, ErrStaleMessage
+	}
+
+	if !b.deliverable(working, msg) {
+		localSender := working.Values[msg.SenderID]
+		if msg.Clock.Values[msg.SenderID]-localSender > b.config.MaxGap*b.increment(msg.SenderID) {
+			return nil, ErrGapTooLarge
+		}
+		if len(b.pending) >= b.config.MaxBuffered {
+			return nil, ErrBufferFull
+		}
+		b.pending = append(b.pending, msg)
+		return nil, nil
+	}
+
+	delivered := []*CausalMessage{msg}
+	working.Merge([]*Clock{msg.Clock})
+	return append(delivered, b.drain(working)...), nil
+}
+
+// drain releases buffered messages that became deliverable, updating working as it goes.
+// Buffered messages made stale by a delivery are dropped.
+func (b *CausalBuffer) drain(working *Clock) []*CausalMessage {
+	var delivered []*CausalMessage
+
+	for progress := true; progress; {
+		progress = false
+
+		// Release in VLC order so equal-priority candidates are deterministic
+		sort.SliceStable(b.pending, func(i, j int) bool {
+			return b.pending[i].Clock.Compare(b.pending[j].Clock) == Less
+		})
+
+		remaining := b.pending[:0]
+		for _, msg := range b.pending {
+			switch {
+			case stale(working, msg):
+				// Duplicate of something already delivered
+			case !progress && b.deliverable(working, msg):
+				working.Merge([]*Clock{msg.Clock})
+				delivered = append(delivered, msg)
+				progress = true
+			default:
+				remaining = append(remaining, msg)
+			}
+		}
+		b.pending = remaining
+	}
+	return delivered
+}
+
+// Release re-checks buffered messages against the caller's local clock.
+// Use this after the local clock advanced through other means (e.g., local events).
+func (b *CausalBuffer) Release(local *Clock) []*CausalMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.drain(local.Copy())
+}
+
+// Expire removes and returns messages that waited longer than the gap timeout.
+// Their predecessors are presumed lost; callers decide whether to resync or discard.
+func (b *CausalBuffer) Expire(now time.Time) []*CausalMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var expired []*CausalMessage
+	remaining := b.pending[:0]
+	for _, msg := range b.pending {
+		if now.Sub(msg.Received) > b.config.GapTimeout {
+			expired = append(expired, msg)
+		} else {
+			remaining = append(remaining, msg)
+		}
+	}
+	b.pending = remaining
+	return expired
+}
+
+// Pending returns the number of buffered messages
+func (b *CausalBuffer) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
+
+// SetIncrement sets the expected per-message increment for a sender (e.g., a miner
+// joining after the buffer was created)
+func (b *CausalBuffer) SetIncrement(senderID, step uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	increments := make(map[uint64]uint64, len(b.config.Increments)+1)
+	for id, existing := range b.config.Increments {
+		increments[id] = existing
+	}
+	increments[senderID] = step
+	b.config.Increments = increments
+}
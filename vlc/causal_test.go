@@ -0,0 +1,198 @@
+package vlc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// clockOf builds a clock with the given entries
+func clockOf(values map[uint64]uint64) *Clock {
+	clock := New()
+	for id, value := range values {
+		clock.Values[id] = value
+	}
+	return clock
+}
+
+// message builds a message from sender stamped with the given clock entries
+func message(sender uint64, values map[uint64]uint64) *CausalMessage {
+	return &CausalMessage{SenderID: sender, Clock: clockOf(values)}
+}
+
+// entries returns the sender entry of each delivered message, in delivery order
+func entries(delivered []*CausalMessage) []uint64 {
+	values := make([]uint64, len(delivered))
+	for i, msg := range delivered {
+		values[i] = msg.Clock.Values[msg.SenderID]
+	}
+	return values
+}
+
+func sameEntries(got []*CausalMessage, want ...uint64) bool {
+	values := entries(got)
+	if len(values) != len(want) {
+		return false
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCausalBufferBootstrapsUnknownSender(t *testing.T) {
+	buffer := NewCausalBuffer(CausalBufferConfig{})
+
+	delivered, err := buffer.Receive(New(), message(1, map[uint64]uint64{1: 5}))
+	if err != nil || !sameEntries(delivered, 5) {
+		t.Fatalf("first message from an unknown sender = %v, %v, want it delivered", entries(delivered), err)
+	}
+
+	if _, err := buffer.Receive(New(), message(1, map[uint64]uint64{2: 1})); !errors.Is(err, ErrMissingSender) {
+		t.Fatalf("message without its sender's entry = %v, want %v", err, ErrMissingSender)
+	}
+}
+
+func TestCausalBufferUsesSenderIncrement(t *testing.T) {
+	buffer := NewCausalBuffer(CausalBufferConfig{Increments: map[uint64]uint64{1: 2}})
+	local := clockOf(map[uint64]uint64{1: 2, 2: 0})
+
+	// A miner's response is +2 (entered and left); +1 is not its next message
+	if delivered, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 3})); err != nil || len(delivered) != 0 {
+		t.Fatalf("+1 from a +2 sender = %v, %v, want it held", entries(delivered), err)
+	}
+	if delivered, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 4})); err != nil || !sameEntries(delivered, 4) {
+		t.Fatalf("+2 from a +2 sender = %v, %v, want only it delivered", entries(delivered), err)
+	}
+	if buffer.Pending() != 0 {
+		t.Fatalf("pending = %d, want the +1 message dropped once superseded", buffer.Pending())
+	}
+
+	// Senders without a configured increment step by 1, until one is set
+	if delivered, err := buffer.Receive(local, message(2, map[uint64]uint64{2: 1})); err != nil || !sameEntries(delivered, 1) {
+		t.Fatalf("+1 from a default sender = %v, %v, want it delivered", entries(delivered), err)
+	}
+	buffer.SetIncrement(2, 2)
+	if delivered, err := buffer.Receive(local, message(2, map[uint64]uint64{2: 1})); err != nil || len(delivered) != 0 {
+		t.Fatalf("+1 after SetIncrement(2) = %v, %v, want it held", entries(delivered), err)
+	}
+}
+
+func TestCausalBufferReleasesInOrder(t *testing.T) {
+	buffer := NewCausalBuffer(CausalBufferConfig{Increments: map[uint64]uint64{1: 2}})
+	local := clockOf(map[uint64]uint64{1: 2, 2: 0})
+
+	// Arrive newest first; a validator message that saw the miner's 4 waits for it too
+	held := []*CausalMessage{
+		message(1, map[uint64]uint64{1: 8}),
+		message(2, map[uint64]uint64{1: 4, 2: 1}),
+		message(1, map[uint64]uint64{1: 6}),
+	}
+	for _, msg := range held {
+		if delivered, err := buffer.Receive(local, msg); err != nil || len(delivered) != 0 {
+			t.Fatalf("message %v = %v, %v, want it held", msg.Clock.Values, entries(delivered), err)
+		}
+	}
+	if buffer.Pending() != 3 {
+		t.Fatalf("pending = %d, want 3", buffer.Pending())
+	}
+
+	delivered, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 4}))
+	if err != nil || len(delivered) != 4 {
+		t.Fatalf("filling the gap delivered %v, %v, want all four messages", entries(delivered), err)
+	}
+	for i, want := range []struct{ sender, entry uint64 }{{1, 4}, {2, 1}, {1, 6}, {1, 8}} {
+		if delivered[i].SenderID != want.sender || delivered[i].Clock.Values[want.sender] != want.entry {
+			t.Fatalf("delivery %d = %v from %d, want entry %d from %d",
+				i, delivered[i].Clock.Values, delivered[i].SenderID, want.entry, want.sender)
+		}
+	}
+	if buffer.Pending() != 0 {
+		t.Fatalf("pending after release = %d, want 0", buffer.Pending())
+	}
+}
+
+func TestCausalBufferReleaseAfterLocalAdvance(t *testing.T) {
+	buffer := NewCausalBuffer(CausalBufferConfig{})
+	local := clockOf(map[uint64]uint64{1: 0, 2: 0})
+
+	// Depends on a local event (entry 2) that has not happened yet
+	if delivered, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 1, 2: 1})); err != nil || len(delivered) != 0 {
+		t.Fatalf("message ahead of the local clock = %v, %v, want it held", entries(delivered), err)
+	}
+	if released := buffer.Release(local); len(released) != 0 {
+		t.Fatalf("released %v before the local clock advanced", entries(released))
+	}
+	local.Inc(2)
+	if released := buffer.Release(local); !sameEntries(released, 1) {
+		t.Fatalf("released %v after the local clock advanced, want the held message", entries(released))
+	}
+}
+
+func TestCausalBufferRejectsGapTooLarge(t *testing.T) {
+	buffer := NewCausalBuffer(CausalBufferConfig{MaxGap: 2, MaxBuffered: 1, Increments: map[uint64]uint64{1: 2}})
+	local := clockOf(map[uint64]uint64{1: 2})
+
+	// MaxGap counts the sender's messages: at most 2 steps of +2 ahead
+	if _, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 8})); !errors.Is(err, ErrGapTooLarge) {
+		t.Fatalf("three steps ahead = %v, want %v", err, ErrGapTooLarge)
+	}
+	if delivered, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 6})); err != nil || len(delivered) != 0 {
+		t.Fatalf("two steps ahead = %v, %v, want it held", entries(delivered), err)
+	}
+	if _, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 6})); !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("second held message with MaxBuffered 1 = %v, want %v", err, ErrBufferFull)
+	}
+	if _, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 2})); !errors.Is(err, ErrStaleMessage) {
+		t.Fatalf("already delivered entry = %v, want %v", err, ErrStaleMessage)
+	}
+}
+
+func TestCausalBufferDropsStaleDuplicates(t *testing.T) {
+	buffer := NewCausalBuffer(CausalBufferConfig{Increments: map[uint64]uint64{1: 2}})
+	local := clockOf(map[uint64]uint64{1: 2})
+
+	// The same early message arrives twice (e.g., a retried send) before the gap is filled
+	for i := 0; i < 2; i++ {
+		if delivered, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 6})); err != nil || len(delivered) != 0 {
+			t.Fatalf("early copy %d = %v, %v, want it held", i+1, entries(delivered), err)
+		}
+	}
+
+	delivered, err := buffer.Receive(local, message(1, map[uint64]uint64{1: 4}))
+	if err != nil || !sameEntries(delivered, 4, 6) {
+		t.Fatalf("filling the gap delivered %v, %v, want 4 then 6 once", entries(delivered), err)
+	}
+	if buffer.Pending() != 0 {
+		t.Fatalf("pending = %d, want the duplicate dropped", buffer.Pending())
+	}
+}
+
+func TestCausalBufferExpire(t *testing.T) {
+	buffer := NewCausalBuffer(CausalBufferConfig{GapTimeout: time.Second})
+	local := clockOf(map[uint64]uint64{1: 0})
+
+	arrived := time.Now()
+	early := message(1, map[uint64]uint64{1: 2})
+	early.Received = arrived
+	later := message(1, map[uint64]uint64{1: 3})
+	later.Received = arrived.Add(time.Second)
+	for _, msg := range []*CausalMessage{early, later} {
+		if _, err := buffer.Receive(local, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expired := buffer.Expire(arrived.Add(500 * time.Millisecond)); len(expired) != 0 {
+		t.Fatalf("expired %v within the gap timeout", entries(expired))
+	}
+	expired := buffer.Expire(arrived.Add(1500 * time.Millisecond))
+	if len(expired) != 1 || expired[0] != early {
+		t.Fatalf("expired %v, want only the message held past the gap timeout", entries(expired))
+	}
+	if buffer.Pending() != 1 {
+		t.Fatalf("pending = %d, want the later message still held", buffer.Pending())
+	}
+}